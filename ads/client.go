@@ -1,6 +1,7 @@
 package ads
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/yatesdr/plcio/internal/ctxlock"
 	"github.com/yatesdr/plcio/logging"
 )
 
@@ -34,22 +36,54 @@ func (c *Client) connErrorIfDown() error {
 // It handles symbol discovery, handle management, and type-safe read/write operations.
 type Client struct {
 	conn        *adsConnection
+	address     string // Host of the AMS router (TCP 48898)
 	targetNetId AmsNetId
 	targetPort  uint16
 	localNetId  AmsNetId
 	localPort   uint16
+	sourceNetId AmsNetId // Explicit local AMS Net ID (zero = derive from local IP)
+
+	// Clients addressing other ports of the target over this client's
+	// connection (see OpenPort). ports is guarded by mu; parent is set on
+	// the port clients. handler is this client's registration on conn.
+	parent  *Client
+	ports   map[uint16]*Client
+	handler *portHandler
 
 	// Symbol cache for efficient access
 	symbols     map[string]*SymbolEntry
 	symbolsMu   sync.RWMutex
 	symbolsLoaded bool
 
+	// Data type table (guarded by symbolsMu, see DataTypes). Keys are
+	// upper-case type names; nil until uploaded.
+	dataTypes map[string]*DataType
+
+	// Symbol version tracking (guarded by symbolsMu, see WithSymbolVersionHandler)
+	symbolVersion      uint8
+	symbolVersionKnown bool
+	onSymbolVersion    func(oldVersion, newVersion uint8)
+
 	// Connection state
-	connected bool
-	mu        sync.Mutex
+	connected    bool
+	timeout      time.Duration
+	allowControl bool
+	mu           sync.Mutex
+
+	// Context binding (see BindContext). ctx is guarded by mu; ctxMu
+	// serializes bindings.
+	ctx   context.Context
+	ctxMu ctxlock.Mutex
 
 	// Device info (cached after first read)
 	deviceInfo *DeviceInfo
+
+	// Device notifications (see Subscribe). subsMu is taken by the reader
+	// goroutine to dispatch samples, so it is never held across a request.
+	subs          []*Subscription
+	notifyHandles map[uint32]*Subscription
+	unclaimed     map[uint32]notificationSample
+	subsMu        sync.Mutex
 }
 
 // SymbolEntry holds cached information about a symbol.
@@ -76,9 +110,14 @@ func (d *DeviceInfo) String() string {
 
 // options holds configuration options for Connect.
 type options struct {
-	targetNetId AmsNetId
-	targetPort  uint16
-	timeout     time.Duration
+	targetNetId  AmsNetId
+	targetPort   uint16
+	sourceNetId  AmsNetId
+	timeout      time.Duration
+	allowControl bool
+	route        *RouteConfig
+
+	onSymbolVersion func(oldVersion, newVersion uint8)
 }
 
 // Option is a functional option for Connect.
@@ -103,6 +142,28 @@ func WithAmsPort(port uint16) Option {
 	}
 }
 
+// WithSourceNetId configures the local (source) AMS Net ID sent in every
+// request. It must match the route configured on the target. If not
+// specified, it is derived from the local IP address of the connection (IP.1.1).
+func WithSourceNetId(netId string) Option {
+	return func(o *options) {
+		parsed, err := ParseAmsNetId(netId)
+		if err == nil {
+			o.sourceNetId = parsed
+		}
+	}
+}
+
+// WithRoute registers a route for this client on the target via the UDP
+// add-route service (see AddRoute) before connecting. Empty NetId and Host
+// fields of the route are filled in from the client's source Net ID and
+// local IP address.
+func WithRoute(route RouteConfig) Option {
+	return func(o *options) {
+		o.route = &route
+	}
+}
+
 // WithTimeout configures the connection and operation timeout.
 // Default is 5 seconds.
 func WithTimeout(d time.Duration) Option {
@@ -111,9 +172,24 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithAllowControl enables WriteControl, which can start, stop, and reset
+// the PLC runtime. Changing a production controller's run state is
+// disruptive, so without it WriteControl returns ErrControlNotAllowed.
+func WithAllowControl() Option {
+	return func(o *options) {
+		o.allowControl = true
+	}
+}
+
 // Connect establishes a connection to a Beckhoff TwinCAT PLC at the given address.
 // The address should be an IP address or hostname (port 48898 is used for ADS).
 func Connect(address string, opts ...Option) (*Client, error) {
+	return ConnectContext(context.Background(), address, opts...)
+}
+
+// ConnectContext is like Connect but aborts the TCP dial and the initial
+// device-info exchange when ctx is cancelled or its deadline passes.
+func ConnectContext(ctx context.Context, address string, opts ...Option) (*Client, error) {
 	// Apply options
 	cfg := &options{
 		targetPort: PortTC3PLC1, // Default TwinCAT 3 PLC runtime 1
@@ -130,6 +206,16 @@ func Connect(address string, opts ...Option) (*Client, error) {
 		host = address
 	}
 
+	if cfg.route != nil {
+		route := *cfg.route
+		if route.NetId.IsZero() {
+			route.NetId = cfg.sourceNetId
+		}
+		if err := AddRouteContext(ctx, host, route, cfg.timeout); err != nil {
+			return nil, fmt.Errorf("Connect: %w", err)
+		}
+	}
+
 	// Derive target Net ID from IP if not specified
 	if cfg.targetNetId.IsZero() {
 		cfg.targetNetId, err = AmsNetIdFromIP(host)
@@ -143,7 +229,8 @@ func Connect(address string, opts ...Option) (*Client, error) {
 	logging.DebugConnect("ADS", tcpAddr)
 	logging.DebugLog("ADS", "Connection params: targetNetId=%s, targetPort=%d", cfg.targetNetId.String(), cfg.targetPort)
 
-	conn, err := net.DialTimeout("tcp", tcpAddr, cfg.timeout)
+	d := net.Dialer{Timeout: cfg.timeout}
+	conn, err := d.DialContext(ctx, "tcp", tcpAddr)
 	if err != nil {
 		logging.DebugConnectError("ADS", tcpAddr, err)
 		return nil, fmt.Errorf("Connect: %w", err)
@@ -157,40 +244,42 @@ func Connect(address string, opts ...Option) (*Client, error) {
 		tcpConn.SetKeepAlivePeriod(30 * time.Second)
 	}
 
-	// Derive local AMS Net ID from the connection's local IP address.
-	// This must match what the Beckhoff route expects (typically IP.1.1).
-	var localNetId AmsNetId
-	if localAddr, ok := conn.LocalAddr().(*net.TCPAddr); ok {
-		// Get IPv4 address (handles IPv6-mapped IPv4 addresses)
-		ip := localAddr.IP
-		if ip4 := ip.To4(); ip4 != nil {
-			ip = ip4
-		}
-		localNetId, err = AmsNetIdFromIP(ip.String())
+	// Derive local AMS Net ID from the connection's local IP address unless
+	// one was given. This must match what the Beckhoff route expects
+	// (typically IP.1.1).
+	localNetId := cfg.sourceNetId
+	if localNetId.IsZero() {
+		localNetId, err = localNetIdFor(conn)
 		if err != nil {
 			conn.Close()
-			return nil, fmt.Errorf("Connect: cannot derive local AMS Net ID from %s: %w", ip.String(), err)
+			return nil, fmt.Errorf("Connect: %w", err)
 		}
-	} else {
-		// Fallback to generic ID (may not work with strict routes)
-		localNetId = AmsNetId{0, 0, 0, 0, 1, 1}
 	}
 	localPort := uint16(32768 + (time.Now().UnixNano() % 1000)) // Random-ish port
 
-	adsConn := newAdsConnection(conn, localNetId, localPort)
+	adsConn := newAdsConnection(conn, localNetId, localPort, cfg.timeout)
 
 	client := &Client{
-		conn:        adsConn,
-		targetNetId: cfg.targetNetId,
-		targetPort:  cfg.targetPort,
-		localNetId:  localNetId,
-		localPort:   localPort,
-		symbols:     make(map[string]*SymbolEntry),
-		connected:   true,
-	}
+		conn:            adsConn,
+		address:         host,
+		targetNetId:     cfg.targetNetId,
+		targetPort:      cfg.targetPort,
+		localNetId:      localNetId,
+		localPort:       localPort,
+		sourceNetId:     cfg.sourceNetId,
+		symbols:         make(map[string]*SymbolEntry),
+		connected:       true,
+		timeout:         cfg.timeout,
+		allowControl:    cfg.allowControl,
+		onSymbolVersion: cfg.onSymbolVersion,
+	}
+
+	client.startConnection(adsConn)
 
 	// Verify connection by reading device info
+	client.ctx = ctx
 	info, err := client.readDeviceInfo()
+	client.ctx = nil
 	if err != nil {
 		logging.DebugError("ADS", "readDeviceInfo", err)
 		conn.Close()
@@ -198,6 +287,12 @@ func Connect(address string, opts ...Option) (*Client, error) {
 	}
 	client.deviceInfo = info
 
+	// Watch for online changes so cached symbols and handles never go stale.
+	// Not every target (e.g. the system service port) has a symbol table.
+	if err := client.watchSymbolVersion(); err != nil {
+		logging.DebugLog("ADS", "Symbol version watch unavailable: %v", err)
+	}
+
 	logging.DebugConnectSuccess("ADS", tcpAddr, fmt.Sprintf("device=%s, local=%s:%d, target=%s:%d",
 		info.String(), localNetId.String(), localPort, cfg.targetNetId.String(), cfg.targetPort))
 
@@ -210,6 +305,13 @@ func (c *Client) Close() {
 		return
 	}
 
+	// Port clients lose their connection with this one
+	if c.parent == nil {
+		for _, port := range c.openPorts() {
+			port.Close()
+		}
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -231,6 +333,14 @@ func (c *Client) Close() {
 
 	logging.DebugLog("ADS", "Released %d symbol handles", handleCount)
 
+	c.closeSubscriptionsUnsafe()
+
+	if c.parent != nil {
+		// The connection belongs to the parent; only give up the port
+		c.detachUnsafe()
+		return
+	}
+
 	if c.conn != nil {
 		c.conn.close()
 		c.conn = nil
@@ -264,6 +374,9 @@ func (c *Client) Reconnect() error {
 	if c == nil {
 		return fmt.Errorf("nil client")
 	}
+	if c.parent != nil {
+		return c.reconnectPort()
+	}
 
 	c.mu.Lock()
 	if c.connected {
@@ -284,18 +397,32 @@ func (c *Client) Reconnect() error {
 	}
 	c.symbolsMu.Unlock()
 
+	host := c.address
 	targetNetId := c.targetNetId
+	sourceNetId := c.sourceNetId
 	localPort := c.localPort
+	timeout := c.timeout
+	ctx := c.ctx
 	c.mu.Unlock()
 
-	// Derive host from target Net ID (first 4 bytes are typically the IP)
-	host := fmt.Sprintf("%d.%d.%d.%d", targetNetId[0], targetNetId[1], targetNetId[2], targetNetId[3])
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if host == "" {
+		// Derive host from target Net ID (first 4 bytes are typically the IP)
+		host = fmt.Sprintf("%d.%d.%d.%d", targetNetId[0], targetNetId[1], targetNetId[2], targetNetId[3])
+	}
 
 	// Connect to ADS TCP port
 	tcpAddr := fmt.Sprintf("%s:%d", host, DefaultTCPPort)
 	logging.DebugLog("ADS", "Reconnecting to %s", tcpAddr)
 
-	conn, err := net.DialTimeout("tcp", tcpAddr, 10*time.Second)
+	d := net.Dialer{Timeout: timeout}
+	conn, err := d.DialContext(ctx, "tcp", tcpAddr)
 	if err != nil {
 		logging.DebugConnectError("ADS", tcpAddr, err)
 		return fmt.Errorf("reconnect failed: %w", err)
@@ -310,18 +437,18 @@ func (c *Client) Reconnect() error {
 	}
 
 	// Derive local AMS Net ID from the connection's local IP address.
-	var localNetId AmsNetId
-	if localAddr, ok := conn.LocalAddr().(*net.TCPAddr); ok {
-		localNetId, err = AmsNetIdFromIP(localAddr.IP.String())
+	localNetId := sourceNetId
+	if localNetId.IsZero() {
+		localNetId, err = localNetIdFor(conn)
 		if err != nil {
 			conn.Close()
-			return fmt.Errorf("reconnect: cannot derive local AMS Net ID: %w", err)
+			return fmt.Errorf("reconnect: %w", err)
 		}
-	} else {
-		localNetId = AmsNetId{0, 0, 0, 0, 1, 1}
 	}
 
-	adsConn := newAdsConnection(conn, localNetId, localPort)
+	adsConn := newAdsConnection(conn, localNetId, localPort, c.timeout)
+
+	c.startConnection(adsConn)
 
 	c.mu.Lock()
 	c.conn = adsConn
@@ -344,9 +471,35 @@ func (c *Client) Reconnect() error {
 
 	logging.DebugConnectSuccess("ADS", tcpAddr, fmt.Sprintf("reconnected, device=%s", info.String()))
 
+	c.resubscribe()
+
+	for _, port := range c.openPorts() {
+		port.adoptConnection(adsConn)
+	}
+
 	return nil
 }
 
+// localNetIdFor derives the local AMS Net ID (IP.1.1) from the local IP
+// address of conn.
+func localNetIdFor(conn net.Conn) (AmsNetId, error) {
+	localAddr, ok := conn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		// Fallback to generic ID (may not work with strict routes)
+		return AmsNetId{0, 0, 0, 0, 1, 1}, nil
+	}
+	// Get IPv4 address (handles IPv6-mapped IPv4 addresses)
+	ip := localAddr.IP
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	netId, err := AmsNetIdFromIP(ip.String())
+	if err != nil {
+		return AmsNetId{}, fmt.Errorf("cannot derive local AMS Net ID from %s: %w", ip.String(), err)
+	}
+	return netId, nil
+}
+
 // isConnectionError checks if an error indicates the TCP connection is broken.
 func isConnectionError(err error) bool {
 	if err == nil {
@@ -413,7 +566,7 @@ func (c *Client) readDeviceInfo() (*DeviceInfo, error) {
 	}
 
 	// Send ReadDeviceInfo command (no data)
-	resp, err := c.conn.sendRequest(c.targetNetId, c.targetPort, CmdReadDeviceInfo, nil)
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdReadDeviceInfo, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -555,7 +708,7 @@ func (c *Client) readBatch(symbolNames []string, entries []*SymbolEntry) ([]*Tag
 
 	logging.DebugLog("ADS", "SumUp Read: %d symbols, readLen=%d, writeLen=%d", count, totalReadLen, writeLen)
 
-	resp, err := c.conn.sendRequest(c.targetNetId, c.targetPort, CmdReadWrite, reqData)
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdReadWrite, reqData)
 	if err != nil {
 		if isConnectionError(err) {
 			logging.DebugDisconnect("ADS", c.targetNetId.String(), fmt.Sprintf("batch read failed: %v", err))
@@ -612,22 +765,7 @@ func (c *Client) readBatch(symbolNames []string, entries []*SymbolEntry) ([]*Tag
 		}
 
 		// Determine element count for arrays
-		elemSize := TypeSize(entry.Info.TypeCode)
-		elemCount := 1
-		if elemSize > 0 && int(size) > elemSize {
-			elemCount = int(size) / elemSize
-		}
-
-		// Special handling for string/wstring arrays
-		if elemCount == 1 && (entry.Info.TypeCode == TypeString || entry.Info.TypeCode == TypeWString) {
-			typeName := strings.ToUpper(entry.Info.TypeName)
-			if strings.Contains(typeName, "ARRAY") {
-				arrayCount := parseArrayCountFromTypeName(typeName)
-				if arrayCount > 1 {
-					elemCount = arrayCount
-				}
-			}
-		}
+		elemCount := symbolElementCount(&entry.Info, size)
 
 		// Copy data bytes
 		dataBytes := make([]byte, size)
@@ -690,7 +828,10 @@ func (c *Client) readSymbol(name string) (*TagValue, error) {
 	}
 
 	// Ensure we have a handle
-	if entry.Handle == 0 {
+	c.symbolsMu.RLock()
+	haveHandle := entry.Handle != 0
+	c.symbolsMu.RUnlock()
+	if !haveHandle {
 		logging.DebugLog("ADS", "Acquiring handle for %s", name)
 		handle, err := c.acquireHandle(name)
 		if err != nil {
@@ -704,9 +845,9 @@ func (c *Client) readSymbol(name string) (*TagValue, error) {
 			return nil, err
 		}
 		logging.DebugLog("ADS", "Acquired handle 0x%08X for %s", handle, name)
-		c.symbolsMu.Lock()
-		entry.Handle = handle
-		c.symbolsMu.Unlock()
+		if err := c.cacheHandle(name, entry, handle); err != nil {
+			return nil, err
+		}
 	}
 
 	// Read using handle
@@ -717,14 +858,18 @@ func (c *Client) readSymbol(name string) (*TagValue, error) {
 		c.connected = false
 		return nil, fmt.Errorf("not connected")
 	}
+	handle, err := c.entryHandleUnsafe(entry)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build read request: [IndexGroup 4] [IndexOffset 4] [Length 4]
 	data := make([]byte, 12)
 	binary.LittleEndian.PutUint32(data[0:4], IndexGroupSymbolValueByHandle)
-	binary.LittleEndian.PutUint32(data[4:8], entry.Handle)
+	binary.LittleEndian.PutUint32(data[4:8], handle)
 	binary.LittleEndian.PutUint32(data[8:12], entry.Info.Size)
 
-	resp, err := c.conn.sendRequest(c.targetNetId, c.targetPort, CmdRead, data)
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdRead, data)
 	if err != nil {
 		// Check for connection error
 		if isConnectionError(err) {
@@ -750,24 +895,7 @@ func (c *Client) readSymbol(name string) (*TagValue, error) {
 	}
 
 	// Determine element count for arrays
-	elemSize := TypeSize(entry.Info.TypeCode)
-	count := 1
-	if elemSize > 0 && int(length) > elemSize {
-		count = int(length) / elemSize
-	}
-
-	// Special handling for string/wstring arrays - parse from TypeName
-	if count == 1 && (entry.Info.TypeCode == TypeString || entry.Info.TypeCode == TypeWString) {
-		// Check if TypeName indicates an array (e.g., "ARRAY [0..4] OF STRING")
-		typeName := strings.ToUpper(entry.Info.TypeName)
-		if strings.Contains(typeName, "ARRAY") {
-			// Try to extract array bounds from TypeName
-			arrayCount := parseArrayCountFromTypeName(typeName)
-			if arrayCount > 1 {
-				count = arrayCount
-			}
-		}
-	}
+	count := symbolElementCount(&entry.Info, length)
 
 	return &TagValue{
 		Name:     name,
@@ -795,36 +923,23 @@ func (c *Client) Write(symbolName string, value interface{}) error {
 		return fmt.Errorf("symbol %q is read-only", symbolName)
 	}
 
-	// Encode value - special handling for string arrays
-	var data []byte
-	if strSlice, ok := value.([]string); ok && (entry.Info.TypeCode == TypeString || entry.Info.TypeCode == TypeWString) {
-		// String array: need to pad each element to fixed size
-		// Get actual array element count from TypeName (e.g., "ARRAY [0..4] OF STRING")
-		arrayCount := parseArrayCountFromTypeName(entry.Info.TypeName)
-		if arrayCount < 1 {
-			arrayCount = len(strSlice) // Fallback to input length
-		}
-		elemSize := int(entry.Info.Size) / arrayCount
-		if elemSize < 1 {
-			elemSize = 81 // Default STRING size
-		}
-		data, err = encodeStringArray(strSlice, elemSize, entry.Info.TypeCode == TypeWString)
-	} else {
-		data, err = EncodeValueWithType(value, entry.Info.TypeCode)
-	}
+	data, err := encodeSymbolValue(&entry.Info, value)
 	if err != nil {
 		return fmt.Errorf("encode value: %w", err)
 	}
 
 	// Ensure we have a handle
-	if entry.Handle == 0 {
+	c.symbolsMu.RLock()
+	haveHandle := entry.Handle != 0
+	c.symbolsMu.RUnlock()
+	if !haveHandle {
 		handle, err := c.acquireHandle(symbolName)
 		if err != nil {
 			return err
 		}
-		c.symbolsMu.Lock()
-		entry.Handle = handle
-		c.symbolsMu.Unlock()
+		if err := c.cacheHandle(symbolName, entry, handle); err != nil {
+			return err
+		}
 	}
 
 	// Write using handle
@@ -834,15 +949,19 @@ func (c *Client) Write(symbolName string, value interface{}) error {
 	if c.conn == nil {
 		return fmt.Errorf("not connected")
 	}
+	handle, err := c.entryHandleUnsafe(entry)
+	if err != nil {
+		return err
+	}
 
 	// Build write request: [IndexGroup 4] [IndexOffset 4] [Length 4] [Data n]
 	req := make([]byte, 12+len(data))
 	binary.LittleEndian.PutUint32(req[0:4], IndexGroupSymbolValueByHandle)
-	binary.LittleEndian.PutUint32(req[4:8], entry.Handle)
+	binary.LittleEndian.PutUint32(req[4:8], handle)
 	binary.LittleEndian.PutUint32(req[8:12], uint32(len(data)))
 	copy(req[12:], data)
 
-	resp, err := c.conn.sendRequest(c.targetNetId, c.targetPort, CmdWrite, req)
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdWrite, req)
 	if err != nil {
 		return err
 	}
@@ -860,6 +979,25 @@ func (c *Client) Write(symbolName string, value interface{}) error {
 	return nil
 }
 
+// encodeSymbolValue encodes a value for writing to the symbol described by info.
+func encodeSymbolValue(info *TagInfo, value interface{}) ([]byte, error) {
+	// Special handling for string arrays
+	if strSlice, ok := value.([]string); ok && (info.TypeCode == TypeString || info.TypeCode == TypeWString) {
+		// String array: need to pad each element to fixed size
+		// Get actual array element count from TypeName (e.g., "ARRAY [0..4] OF STRING")
+		arrayCount := parseArrayCountFromTypeName(info.TypeName)
+		if arrayCount < 1 {
+			arrayCount = len(strSlice) // Fallback to input length
+		}
+		elemSize := int(info.Size) / arrayCount
+		if elemSize < 1 {
+			elemSize = 81 // Default STRING size
+		}
+		return encodeStringArray(strSlice, elemSize, info.TypeCode == TypeWString)
+	}
+	return EncodeValueWithType(value, info.TypeCode)
+}
+
 // encodeStringArray encodes a string slice with fixed-size padding for each element.
 // This is needed for TwinCAT STRING arrays where each element has a fixed size.
 func encodeStringArray(strings []string, elemSize int, isWString bool) ([]byte, error) {
@@ -914,14 +1052,17 @@ func (c *Client) getSymbolEntry(name string) (*SymbolEntry, error) {
 		return nil, err
 	}
 
-	// Cache it
+	// Cache it, unless a concurrent lookup already did
+	c.symbolsMu.Lock()
+	defer c.symbolsMu.Unlock()
+	if cached, ok := c.symbols[name]; ok {
+		return cached, nil
+	}
 	entry = &SymbolEntry{
 		Info:   *info,
 		Handle: 0,
 	}
-	c.symbolsMu.Lock()
 	c.symbols[name] = entry
-	c.symbolsMu.Unlock()
 
 	return entry, nil
 }
@@ -946,7 +1087,7 @@ func (c *Client) getSymbolInfo(name string) (*TagInfo, error) {
 	binary.LittleEndian.PutUint32(req[12:16], uint32(len(nameBytes)))
 	copy(req[16:], nameBytes)
 
-	resp, err := c.conn.sendRequest(c.targetNetId, c.targetPort, CmdReadWrite, req)
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdReadWrite, req)
 	if err != nil {
 		return nil, err
 	}
@@ -1133,6 +1274,30 @@ func mapAdsType(adsType uint32) uint16 {
 	}
 }
 
+// symbolElementCount returns the number of elements in size bytes of the
+// symbol's type (1 for scalars).
+func symbolElementCount(info *TagInfo, size uint32) int {
+	elemSize := TypeSize(info.TypeCode)
+	count := 1
+	if elemSize > 0 && int(size) > elemSize {
+		count = int(size) / elemSize
+	}
+
+	// Special handling for string/wstring arrays - parse from TypeName
+	if count == 1 && (info.TypeCode == TypeString || info.TypeCode == TypeWString) {
+		// Check if TypeName indicates an array (e.g., "ARRAY [0..4] OF STRING")
+		typeName := strings.ToUpper(info.TypeName)
+		if strings.Contains(typeName, "ARRAY") {
+			// Try to extract array bounds from TypeName
+			arrayCount := parseArrayCountFromTypeName(typeName)
+			if arrayCount > 1 {
+				count = arrayCount
+			}
+		}
+	}
+	return count
+}
+
 // parseArrayCountFromTypeName extracts array element count from TwinCAT type names.
 // Examples: "ARRAY [0..4] OF STRING" -> 5, "ARRAY [1..10] OF INT" -> 10
 func parseArrayCountFromTypeName(typeName string) int {
@@ -1183,7 +1348,7 @@ func (c *Client) acquireHandle(name string) (uint32, error) {
 	binary.LittleEndian.PutUint32(req[12:16], uint32(len(nameBytes)))
 	copy(req[16:], nameBytes)
 
-	resp, err := c.conn.sendRequest(c.targetNetId, c.targetPort, CmdReadWrite, req)
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdReadWrite, req)
 	if err != nil {
 		return 0, err
 	}
@@ -1202,6 +1367,45 @@ func (c *Client) acquireHandle(name string) (uint32, error) {
 	return handle, nil
 }
 
+// cacheHandle stores a handle just acquired for name on entry. If another
+// caller cached a handle first, the new one is released and the cached one
+// is used. If a symbol version change dropped entry meanwhile, the new
+// handle is released and ErrSymbolHandleInvalidated returned, since nothing
+// would release it later.
+func (c *Client) cacheHandle(name string, entry *SymbolEntry, handle uint32) error {
+	c.symbolsMu.Lock()
+	current := c.symbols[name] == entry
+	stored := current && entry.Handle == 0
+	if stored {
+		entry.Handle = handle
+	}
+	c.symbolsMu.Unlock()
+
+	if !stored {
+		c.mu.Lock()
+		_ = c.releaseHandleUnsafe(handle)
+		c.mu.Unlock()
+	}
+	if !current {
+		return ErrSymbolHandleInvalidated
+	}
+	return nil
+}
+
+// entryHandleUnsafe returns the cached handle of entry (caller must hold
+// c.mu). Handles are released under c.mu, so the handle stays valid until
+// the caller's request is sent. A zero handle means the symbol version
+// changed after the entry was looked up.
+func (c *Client) entryHandleUnsafe(entry *SymbolEntry) (uint32, error) {
+	c.symbolsMu.RLock()
+	handle := entry.Handle
+	c.symbolsMu.RUnlock()
+	if handle == 0 {
+		return 0, ErrSymbolHandleInvalidated
+	}
+	return handle, nil
+}
+
 // releaseHandleUnsafe releases a symbol handle (caller must hold c.mu).
 func (c *Client) releaseHandleUnsafe(handle uint32) error {
 	if c.conn == nil {
@@ -1218,7 +1422,7 @@ func (c *Client) releaseHandleUnsafe(handle uint32) error {
 
 	fullReq := append(req, handleBytes...)
 
-	_, err := c.conn.sendRequest(c.targetNetId, c.targetPort, CmdWrite, fullReq)
+	_, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdWrite, fullReq)
 	return err
 }
 
@@ -1251,43 +1455,34 @@ func (c *Client) AllTags() ([]TagInfo, error) {
 		return nil, fmt.Errorf("not connected")
 	}
 
-	// Read upload info: symbol count and size
-	req := make([]byte, 12)
-	binary.LittleEndian.PutUint32(req[0:4], IndexGroupSymbolUploadInfo2)
-	binary.LittleEndian.PutUint32(req[4:8], 0)
-	binary.LittleEndian.PutUint32(req[8:12], 24) // Read 24 bytes of info
-
-	resp, err := c.conn.sendRequest(c.targetNetId, c.targetPort, CmdRead, req)
+	// Read upload info: symbol and data type counts and sizes
+	symbolCount, symbolSize, typeCount, typeSize, err := c.readUploadInfoUnsafe()
 	if err != nil {
-		return nil, fmt.Errorf("read upload info: %w", err)
-	}
-
-	if len(resp) < 16 {
-		return nil, fmt.Errorf("upload info response too short: %d bytes", len(resp))
-	}
-
-	result := binary.LittleEndian.Uint32(resp[0:4])
-	if result != 0 {
-		return nil, &AdsError{Code: result}
+		return nil, err
 	}
 
-	// Info structure: [SymbolCount 4] [SymbolSize 4] [DataTypeCount 4] [DataTypeSize 4] ...
-	symbolCount := binary.LittleEndian.Uint32(resp[8:12])
-	symbolSize := binary.LittleEndian.Uint32(resp[12:16])
-
 	logging.DebugLog("ADS", "Symbol upload info: %d symbols, %d bytes", symbolCount, symbolSize)
 
 	if symbolCount == 0 {
 		return nil, nil
 	}
 
+	// Upload data types so STRUCT/FB symbols can be listed and decoded.
+	// Failure is not fatal: only primitive symbols are listed then.
+	if err := c.loadDataTypesUnsafe(typeCount, typeSize); err != nil {
+		logging.DebugLog("ADS", "AllTags: data type upload failed: %v", err)
+	}
+	c.symbolsMu.RLock()
+	types := c.dataTypes
+	c.symbolsMu.RUnlock()
+
 	// Upload symbol table
 	req2 := make([]byte, 12)
 	binary.LittleEndian.PutUint32(req2[0:4], IndexGroupSymbolUpload)
 	binary.LittleEndian.PutUint32(req2[4:8], 0)
 	binary.LittleEndian.PutUint32(req2[8:12], symbolSize)
 
-	resp2, err := c.conn.sendRequest(c.targetNetId, c.targetPort, CmdRead, req2)
+	resp2, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdRead, req2)
 	if err != nil {
 		return nil, fmt.Errorf("upload symbols: %w", err)
 	}
@@ -1296,7 +1491,7 @@ func (c *Client) AllTags() ([]TagInfo, error) {
 		return nil, fmt.Errorf("symbol upload response too short")
 	}
 
-	result = binary.LittleEndian.Uint32(resp2[0:4])
+	result := binary.LittleEndian.Uint32(resp2[0:4])
 	if result != 0 {
 		return nil, &AdsError{Code: result}
 	}
@@ -1322,7 +1517,7 @@ func (c *Client) AllTags() ([]TagInfo, error) {
 		}
 
 		info, err := parseSymbolInfo(symbolData[offset : offset+entryLen])
-		if err == nil && info.IsPrimitive() {
+		if err == nil && (info.IsPrimitive() || isDecodableType(types, info.TypeName)) {
 			tags = append(tags, *info)
 
 			// Cache symbol
@@ -1338,7 +1533,7 @@ func (c *Client) AllTags() ([]TagInfo, error) {
 	c.symbolsLoaded = true
 	c.symbolsMu.Unlock()
 
-	logging.DebugLog("ADS", "AllTags discovered %d primitive symbols from %d total", len(tags), symbolCount)
+	logging.DebugLog("ADS", "AllTags discovered %d symbols from %d total", len(tags), symbolCount)
 
 	return tags, nil
 }
@@ -0,0 +1,90 @@
+package ads
+
+import (
+	"context"
+
+	"github.com/yatesdr/plcio/internal/ctxerr"
+)
+
+// BindContext ties the client's socket I/O to ctx until the returned release
+// function is called. While bound, each request's deadline is the earlier of
+// the client timeout and the ctx deadline, and cancelling ctx aborts a blocked
+// send or wait for a response. Responses are matched by invoke ID, so an
+// abandoned wait leaves the connection usable; only a send interrupted part
+// way drops the connection, after which the caller must Reconnect.
+//
+// Bindings are serialized: a second BindContext waits until the first is
+// released, or returns ctx.Err() if ctx ends first. Do not call the *Context methods while holding a binding.
+func (c *Client) BindContext(ctx context.Context) (release func(), err error) {
+	if c == nil || ctx == nil {
+		return func() {}, nil
+	}
+	if err := c.ctxMu.Lock(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.ctx = ctx
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		c.ctx = nil
+		c.mu.Unlock()
+		c.ctxMu.Unlock()
+	}, nil
+}
+
+// ReadContext is like Read but honors ctx cancellation and deadline.
+func (c *Client) ReadContext(ctx context.Context, symbolNames ...string) ([]*TagValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	values, err := c.Read(symbolNames...)
+	return values, ctxerr.Wrap(ctx, err)
+}
+
+// WriteContext is like Write but honors ctx cancellation and deadline.
+func (c *Client) WriteContext(ctx context.Context, symbolName string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return ctxerr.Wrap(ctx, c.Write(symbolName, value))
+}
+
+// WriteManyContext is like WriteMany but honors ctx cancellation and deadline.
+func (c *Client) WriteManyContext(ctx context.Context, values map[string]interface{}) (map[string]error, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	results, err := c.WriteMany(values)
+	return results, ctxerr.Wrap(ctx, err)
+}
+
+// AllTagsContext is like AllTags but honors ctx cancellation and deadline,
+// which matters for the symbol upload on large projects.
+func (c *Client) AllTagsContext(ctx context.Context) ([]TagInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	tags, err := c.AllTags()
+	return tags, ctxerr.Wrap(ctx, err)
+}
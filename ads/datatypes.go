@@ -0,0 +1,587 @@
+package ads
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// Data type entry flags (ADSDATATYPEFLAG_*) that affect parsing.
+const (
+	dataTypeFlagBitValues   uint32 = 0x0020 // Offset and size are in bits
+	dataTypeFlagTypeGuid    uint32 = 0x0080 // 16-byte type GUID follows sub-items
+	dataTypeFlagCopyMask    uint32 = 0x0200 // Copy mask (Size bytes) follows
+	dataTypeFlagMethodInfos uint32 = 0x0800 // Method descriptions follow
+	dataTypeFlagAttributes  uint32 = 0x1000 // Attributes follow
+	dataTypeFlagEnumInfos   uint32 = 0x2000 // Enum values follow
+)
+
+// maxDecodeDepth bounds recursion through nested and self-referencing types.
+const maxDecodeDepth = 16
+
+// DataType describes a TwinCAT data type from the data type upload (0xF00E),
+// or one member of a STRUCT/FB.
+type DataType struct {
+	Name       string      // Type name (member name for members)
+	TypeName   string      // Base type: alias target, array element, or member type
+	Comment    string      // Type comment
+	Size       uint32      // Size in bytes (bits for bit-packed members)
+	Offset     uint32      // Offset within the parent (members only)
+	AdsType    uint32      // ADST_* code of the base type
+	Flags      uint32      // ADSDATATYPEFLAG_* bits
+	ArrayDims  []ArrayDim  // Array dimensions (empty if not an array)
+	Members    []*DataType // STRUCT/FB members in declaration order
+	EnumValues []EnumValue // ENUM values (empty if not an enum)
+}
+
+// ArrayDim is one dimension of an ARRAY type.
+type ArrayDim struct {
+	LowerBound int32
+	Elements   uint32
+}
+
+// EnumValue is one named value of an ENUM type.
+type EnumValue struct {
+	Name  string
+	Value int64
+}
+
+// IsStruct returns true for STRUCT and FB types.
+func (d *DataType) IsStruct() bool {
+	return len(d.Members) > 0
+}
+
+// IsEnum returns true for ENUM types.
+func (d *DataType) IsEnum() bool {
+	return len(d.EnumValues) > 0
+}
+
+// ElementCount returns the total element count across all array dimensions
+// (1 for non-arrays).
+func (d *DataType) ElementCount() int {
+	count := 1
+	for _, dim := range d.ArrayDims {
+		count *= int(dim.Elements)
+	}
+	return count
+}
+
+// parseDataTypeEntry parses one AdsDatatypeEntry, recursing into sub-items.
+// Returns the entry and its length in bytes.
+//
+// Format: [EntryLength 4] [Version 4] [HashValue 4] [TypeHashValue 4]
+// [Size 4] [Offset 4] [DataType 4] [Flags 4] [NameLength 2] [TypeLength 2]
+// [CommentLength 2] [ArrayDim 2] [SubItems 2] [Name] [Type] [Comment]
+// [ArrayInfo 8*ArrayDim] [SubItem entries] then optional sections per Flags.
+func parseDataTypeEntry(data []byte) (*DataType, uint32, error) {
+	if len(data) < 42 {
+		return nil, 0, fmt.Errorf("data type entry too short: %d bytes", len(data))
+	}
+	entryLen := binary.LittleEndian.Uint32(data[0:4])
+	if entryLen < 42 || int(entryLen) > len(data) {
+		return nil, 0, fmt.Errorf("invalid data type entry length %d", entryLen)
+	}
+	d := data[:entryLen]
+
+	dt := &DataType{
+		Size:    binary.LittleEndian.Uint32(d[16:20]),
+		Offset:  binary.LittleEndian.Uint32(d[20:24]),
+		AdsType: binary.LittleEndian.Uint32(d[24:28]),
+		Flags:   binary.LittleEndian.Uint32(d[28:32]),
+	}
+	nameLen := int(binary.LittleEndian.Uint16(d[32:34]))
+	typeLen := int(binary.LittleEndian.Uint16(d[34:36]))
+	commentLen := int(binary.LittleEndian.Uint16(d[36:38]))
+	arrayDim := int(binary.LittleEndian.Uint16(d[38:40]))
+	subItems := int(binary.LittleEndian.Uint16(d[40:42]))
+
+	offset := 42
+	readString := func(n int) (string, bool) {
+		if offset+n+1 > len(d) {
+			return "", false
+		}
+		s := string(d[offset : offset+n])
+		offset += n + 1 // +1 for null terminator
+		return s, true
+	}
+
+	var ok bool
+	if dt.Name, ok = readString(nameLen); !ok {
+		return nil, 0, fmt.Errorf("data type name truncated")
+	}
+	if dt.TypeName, ok = readString(typeLen); !ok {
+		return nil, 0, fmt.Errorf("data type %q: type name truncated", dt.Name)
+	}
+	if dt.Comment, ok = readString(commentLen); !ok {
+		return nil, 0, fmt.Errorf("data type %q: comment truncated", dt.Name)
+	}
+
+	for i := 0; i < arrayDim; i++ {
+		if offset+8 > len(d) {
+			return nil, 0, fmt.Errorf("data type %q: array info truncated", dt.Name)
+		}
+		dt.ArrayDims = append(dt.ArrayDims, ArrayDim{
+			LowerBound: int32(binary.LittleEndian.Uint32(d[offset : offset+4])),
+			Elements:   binary.LittleEndian.Uint32(d[offset+4 : offset+8]),
+		})
+		offset += 8
+	}
+
+	for i := 0; i < subItems; i++ {
+		member, n, err := parseDataTypeEntry(d[offset:])
+		if err != nil {
+			return nil, 0, fmt.Errorf("data type %q member %d: %w", dt.Name, i, err)
+		}
+		dt.Members = append(dt.Members, member)
+		offset += int(n)
+	}
+
+	// Optional sections. Only enum values are kept; the rest are skipped.
+	// A truncated optional section is not an error since the entry length
+	// already bounds the entry.
+	if dt.Flags&dataTypeFlagTypeGuid != 0 {
+		offset += 16
+	}
+	if dt.Flags&dataTypeFlagCopyMask != 0 {
+		offset += int(dt.Size)
+	}
+	if dt.Flags&dataTypeFlagMethodInfos != 0 && offset+2 <= len(d) {
+		count := int(binary.LittleEndian.Uint16(d[offset:]))
+		offset += 2
+		for i := 0; i < count && offset+4 <= len(d); i++ {
+			offset += int(binary.LittleEndian.Uint32(d[offset:]))
+		}
+	}
+	if dt.Flags&dataTypeFlagAttributes != 0 && offset+2 <= len(d) {
+		count := int(binary.LittleEndian.Uint16(d[offset:]))
+		offset += 2
+		for i := 0; i < count && offset+2 <= len(d); i++ {
+			offset += 2 + int(d[offset]) + 1 + int(d[offset+1]) + 1
+		}
+	}
+	if dt.Flags&dataTypeFlagEnumInfos != 0 && offset+2 <= len(d) {
+		count := int(binary.LittleEndian.Uint16(d[offset:]))
+		offset += 2
+		for i := 0; i < count && offset+1 <= len(d); i++ {
+			n := int(d[offset])
+			end := offset + 1 + n + 1 + int(dt.Size)
+			if end > len(d) {
+				break
+			}
+			name := string(d[offset+1 : offset+1+n])
+			value := enumInt(d[offset+1+n+1:end], dt.AdsType)
+			dt.EnumValues = append(dt.EnumValues, EnumValue{Name: name, Value: value})
+			offset = end
+		}
+	}
+
+	return dt, entryLen, nil
+}
+
+// enumInt interprets little-endian enum value bytes as an integer, signed
+// or unsigned according to the ADST base type.
+func enumInt(b []byte, adsType uint32) int64 {
+	var u uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		u = u<<8 | uint64(b[i])
+	}
+	switch mapAdsType(adsType) {
+	case TypeSByte:
+		return int64(int8(u))
+	case TypeInt16:
+		return int64(int16(u))
+	case TypeInt32:
+		return int64(int32(u))
+	}
+	return int64(u)
+}
+
+// parseDataTypes parses the data type upload into a map keyed by upper-case
+// type name (TwinCAT type names are case-insensitive).
+func parseDataTypes(data []byte, count uint32) map[string]*DataType {
+	types := make(map[string]*DataType, count)
+	offset := 0
+	for i := uint32(0); i < count && offset < len(data); i++ {
+		dt, n, err := parseDataTypeEntry(data[offset:])
+		if err != nil {
+			logging.DebugLog("ADS", "Data type upload: stopping at entry %d: %v", i, err)
+			break
+		}
+		types[strings.ToUpper(dt.Name)] = dt
+		offset += int(n)
+	}
+	return types
+}
+
+// loadDataTypesUnsafe uploads the data type table (caller must hold c.mu).
+func (c *Client) loadDataTypesUnsafe(count, size uint32) error {
+	if count == 0 || size == 0 {
+		c.symbolsMu.Lock()
+		c.dataTypes = make(map[string]*DataType)
+		c.symbolsMu.Unlock()
+		return nil
+	}
+
+	req := make([]byte, 12)
+	binary.LittleEndian.PutUint32(req[0:4], IndexGroupDataTypeUpload)
+	binary.LittleEndian.PutUint32(req[4:8], 0)
+	binary.LittleEndian.PutUint32(req[8:12], size)
+
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdRead, req)
+	if err != nil {
+		return fmt.Errorf("upload data types: %w", err)
+	}
+
+	if len(resp) < 8 {
+		return fmt.Errorf("data type upload response too short")
+	}
+
+	result := binary.LittleEndian.Uint32(resp[0:4])
+	if result != 0 {
+		return &AdsError{Code: result}
+	}
+
+	dataLen := binary.LittleEndian.Uint32(resp[4:8])
+	typeData := resp[8:]
+	if uint32(len(typeData)) < dataLen {
+		return fmt.Errorf("data type data truncated: expected %d, got %d", dataLen, len(typeData))
+	}
+
+	types := parseDataTypes(typeData[:dataLen], count)
+	logging.DebugLog("ADS", "Data type upload: %d of %d types parsed", len(types), count)
+
+	c.symbolsMu.Lock()
+	c.dataTypes = types
+	c.symbolsMu.Unlock()
+	return nil
+}
+
+// readUploadInfoUnsafe reads symbol and data type counts and sizes
+// (caller must hold c.mu).
+func (c *Client) readUploadInfoUnsafe() (symbolCount, symbolSize, typeCount, typeSize uint32, err error) {
+	req := make([]byte, 12)
+	binary.LittleEndian.PutUint32(req[0:4], IndexGroupSymbolUploadInfo2)
+	binary.LittleEndian.PutUint32(req[4:8], 0)
+	binary.LittleEndian.PutUint32(req[8:12], 24) // Read 24 bytes of info
+
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdRead, req)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("read upload info: %w", err)
+	}
+
+	if len(resp) < 16 {
+		return 0, 0, 0, 0, fmt.Errorf("upload info response too short: %d bytes", len(resp))
+	}
+
+	result := binary.LittleEndian.Uint32(resp[0:4])
+	if result != 0 {
+		return 0, 0, 0, 0, &AdsError{Code: result}
+	}
+
+	// Info structure: [SymbolCount 4] [SymbolSize 4] [DataTypeCount 4] [DataTypeSize 4] ...
+	symbolCount = binary.LittleEndian.Uint32(resp[8:12])
+	symbolSize = binary.LittleEndian.Uint32(resp[12:16])
+	if len(resp) >= 24 {
+		typeCount = binary.LittleEndian.Uint32(resp[16:20])
+		typeSize = binary.LittleEndian.Uint32(resp[20:24])
+	}
+	return symbolCount, symbolSize, typeCount, typeSize, nil
+}
+
+// DataTypes uploads (once) and returns the PLC's data type table, keyed by
+// upper-case type name.
+func (c *Client) DataTypes() (map[string]*DataType, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("DataTypes: nil client")
+	}
+
+	c.symbolsMu.RLock()
+	types := c.dataTypes
+	c.symbolsMu.RUnlock()
+	if types != nil {
+		return types, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	_, _, typeCount, typeSize, err := c.readUploadInfoUnsafe()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.loadDataTypesUnsafe(typeCount, typeSize); err != nil {
+		return nil, err
+	}
+
+	c.symbolsMu.RLock()
+	defer c.symbolsMu.RUnlock()
+	return c.dataTypes, nil
+}
+
+// GetDataType returns the data type with the given name, uploading the data
+// type table on first use. Returns nil if the type is not in the table
+// (e.g. elementary types).
+func (c *Client) GetDataType(typeName string) (*DataType, error) {
+	types, err := c.DataTypes()
+	if err != nil {
+		return nil, err
+	}
+	return types[strings.ToUpper(strings.TrimSpace(typeName))], nil
+}
+
+// DecodeValue decodes raw bytes of the named TwinCAT type. STRUCT and FB
+// values become map[string]interface{} keyed by member name, arrays become
+// []interface{}, enums become their value name, and aliases are decoded as
+// their base type. Elementary types decode like TagValue.GoValue.
+func (c *Client) DecodeValue(typeName string, data []byte) (interface{}, error) {
+	types, err := c.DataTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data types: %w", err)
+	}
+	return decodeTypedValue(types, typeName, data, 0)
+}
+
+// DecodeStruct decodes raw bytes of a STRUCT or FB type into a map with
+// member names as keys. Nested structures are recursively decoded into
+// nested maps.
+func (c *Client) DecodeStruct(typeName string, data []byte) (map[string]interface{}, error) {
+	value, err := c.DecodeValue(typeName, data)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("type %q is not a structure", typeName)
+	}
+	return m, nil
+}
+
+// isDecodableType reports whether typeName needs the data type table to be
+// decoded: a user-defined type, or an array of one.
+func isDecodableType(types map[string]*DataType, typeName string) bool {
+	name := strings.ToUpper(strings.TrimSpace(typeName))
+	if _, ok := types[name]; ok {
+		return true
+	}
+	if dims, elem, ok := parseArrayTypeName(name); ok && len(dims) > 0 {
+		_, ok := types[elem]
+		return ok
+	}
+	return false
+}
+
+// decodeTypedValue decodes data as the named type using the type table.
+func decodeTypedValue(types map[string]*DataType, typeName string, data []byte, depth int) (interface{}, error) {
+	if depth > maxDecodeDepth {
+		return nil, fmt.Errorf("type %q nested too deeply", typeName)
+	}
+	name := strings.ToUpper(strings.TrimSpace(typeName))
+
+	dt, ok := types[name]
+	if !ok {
+		// Arrays are not always listed as data types; parse the name.
+		if dims, elem, ok := parseArrayTypeName(name); ok {
+			return decodeArray(types, elem, dims, 0, data, depth)
+		}
+		return decodeElementary(name, 0, data), nil
+	}
+
+	return decodeDataType(types, dt, data, depth)
+}
+
+// decodeDataType decodes data as dt, which is a type table entry or a member.
+func decodeDataType(types map[string]*DataType, dt *DataType, data []byte, depth int) (interface{}, error) {
+	switch {
+	case len(dt.ArrayDims) > 0:
+		return decodeArray(types, dt.TypeName, dt.ArrayDims, dt.AdsType, data, depth)
+
+	case dt.IsStruct():
+		result := make(map[string]interface{}, len(dt.Members))
+		for _, m := range dt.Members {
+			if m.Name == "" {
+				continue
+			}
+			memberData, ok := memberBytes(m, data)
+			if !ok {
+				continue
+			}
+			if len(m.ArrayDims) == 0 && m.Flags&dataTypeFlagBitValues != 0 {
+				result[m.Name] = len(memberData) > 0 && memberData[0] != 0
+				continue
+			}
+			value, err := decodeMember(types, m, memberData, depth+1)
+			if err != nil {
+				logging.DebugLog("ADS", "Failed to decode member %q: %v", m.Name, err)
+				continue
+			}
+			result[m.Name] = value
+		}
+		return result, nil
+
+	case dt.IsEnum():
+		value := enumInt(data[:min(len(data), int(dt.Size))], dt.AdsType)
+		for _, ev := range dt.EnumValues {
+			if ev.Value == value {
+				return ev.Name, nil
+			}
+		}
+		return value, nil
+
+	case dt.TypeName != "" && !strings.EqualFold(dt.TypeName, dt.Name):
+		// Alias: decode as the base type
+		return decodeTypedValue(types, dt.TypeName, data, depth+1)
+	}
+
+	return decodeElementary(dt.Name, dt.AdsType, data), nil
+}
+
+// decodeMember decodes a STRUCT member. Unlike a type table entry, a
+// member's Name is the member name and TypeName is its type.
+func decodeMember(types map[string]*DataType, m *DataType, data []byte, depth int) (interface{}, error) {
+	if len(m.ArrayDims) > 0 {
+		return decodeArray(types, m.TypeName, m.ArrayDims, m.AdsType, data, depth)
+	}
+	if _, ok := types[strings.ToUpper(m.TypeName)]; !ok {
+		if _, _, isArray := parseArrayTypeName(strings.ToUpper(m.TypeName)); !isArray {
+			return decodeElementary(m.TypeName, m.AdsType, data), nil
+		}
+	}
+	return decodeTypedValue(types, m.TypeName, data, depth)
+}
+
+// memberBytes returns the bytes of a member within its parent's data. For
+// bit-packed members it returns a single byte holding the bit value.
+func memberBytes(m *DataType, data []byte) ([]byte, bool) {
+	if m.Flags&dataTypeFlagBitValues != 0 {
+		byteOff := int(m.Offset / 8)
+		if byteOff >= len(data) {
+			return nil, false
+		}
+		return []byte{(data[byteOff] >> (m.Offset % 8)) & 1}, true
+	}
+	start := int(m.Offset)
+	end := start + int(m.Size)
+	if start >= len(data) {
+		return nil, false
+	}
+	if end > len(data) {
+		end = len(data)
+	}
+	return data[start:end], true
+}
+
+// decodeArray decodes a flat array of elemType. Multi-dimensional arrays are
+// returned flattened in row-major order.
+func decodeArray(types map[string]*DataType, elemType string, dims []ArrayDim, adsType uint32, data []byte, depth int) (interface{}, error) {
+	count := 1
+	for _, dim := range dims {
+		count *= int(dim.Elements)
+	}
+	if count <= 0 {
+		return []interface{}{}, nil
+	}
+	// Members may carry the full "ARRAY [..] OF T" name alongside ArrayDims
+	if _, elem, ok := parseArrayTypeName(strings.ToUpper(strings.TrimSpace(elemType))); ok {
+		elemType = elem
+	}
+	elemSize := len(data) / count
+	if elemSize == 0 {
+		return nil, fmt.Errorf("array of %d %s does not fit in %d bytes", count, elemType, len(data))
+	}
+
+	results := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		elemData := data[i*elemSize : (i+1)*elemSize]
+		var value interface{}
+		var err error
+		if _, ok := types[strings.ToUpper(elemType)]; ok {
+			value, err = decodeTypedValue(types, elemType, elemData, depth+1)
+		} else {
+			value = decodeElementary(elemType, adsType, elemData)
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, value)
+	}
+	return results, nil
+}
+
+// decodeElementary decodes an elementary type by name, falling back to the
+// ADST code, and finally to raw bytes.
+func decodeElementary(typeName string, adsType uint32, data []byte) interface{} {
+	typeCode := mapTypeFromName(typeName, uint32(len(data)))
+	if typeCode == TypeUnknown && adsType != 0 {
+		typeCode = mapAdsType(adsType)
+	}
+	v := &TagValue{DataType: typeCode, Bytes: data, Count: 1}
+	return v.parseScalar(typeCode)
+}
+
+// parseArrayTypeName parses "ARRAY [0..4] OF INT" or "ARRAY [1..2,0..3] OF ST_X".
+// The name must already be upper case.
+func parseArrayTypeName(name string) ([]ArrayDim, string, bool) {
+	if !strings.HasPrefix(name, "ARRAY") {
+		return nil, "", false
+	}
+	open := strings.Index(name, "[")
+	closeIdx := strings.Index(name, "]")
+	of := strings.Index(name, " OF ")
+	if open < 0 || closeIdx < open || of < closeIdx {
+		return nil, "", false
+	}
+
+	var dims []ArrayDim
+	for _, part := range strings.Split(name[open+1:closeIdx], ",") {
+		bounds := strings.Split(part, "..")
+		if len(bounds) != 2 {
+			return nil, "", false
+		}
+		lower, err1 := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		upper, err2 := strconv.Atoi(strings.TrimSpace(bounds[1]))
+		if err1 != nil || err2 != nil || upper < lower {
+			return nil, "", false
+		}
+		dims = append(dims, ArrayDim{LowerBound: int32(lower), Elements: uint32(upper - lower + 1)})
+	}
+	return dims, strings.TrimSpace(name[of+4:]), true
+}
+
+// GoValueDecoded returns the decoded Go value, using the client's data type
+// table for STRUCT, FB, ENUM, alias and ARRAY OF STRUCT symbols. Structures
+// decode to map[string]interface{}, so TagSelection ignore lists apply to
+// their members. Other values decode as GoValue.
+func (v *TagValue) GoValueDecoded(client *Client) interface{} {
+	if v == nil || v.Error != nil {
+		return nil
+	}
+	if client == nil {
+		return v.GoValue()
+	}
+
+	client.symbolsMu.RLock()
+	entry := client.symbols[v.Name]
+	client.symbolsMu.RUnlock()
+	if entry == nil || entry.Info.TypeName == "" {
+		return v.GoValue()
+	}
+
+	types, err := client.DataTypes()
+	if err != nil || !isDecodableType(types, entry.Info.TypeName) {
+		return v.GoValue()
+	}
+
+	decoded, err := decodeTypedValue(types, entry.Info.TypeName, v.Bytes, 0)
+	if err != nil {
+		logging.DebugLog("ADS", "GoValueDecoded: failed to decode %q (%s): %v", v.Name, entry.Info.TypeName, err)
+		return v.GoValue()
+	}
+	return decoded
+}
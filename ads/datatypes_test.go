@@ -0,0 +1,144 @@
+package ads
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// testDataType describes a data type entry for buildDataTypeEntry.
+type testDataType struct {
+	name, typeName string
+	size, offset   uint32
+	adsType, flags uint32
+	dims           []ArrayDim
+	members        []testDataType
+	enums          []EnumValue
+}
+
+// buildDataTypeEntry encodes an AdsDatatypeEntry as uploaded from 0xF00E.
+func buildDataTypeEntry(d testDataType) []byte {
+	flags := d.flags
+	if len(d.enums) > 0 {
+		flags |= dataTypeFlagEnumInfos
+	}
+
+	buf := make([]byte, 42)
+	binary.LittleEndian.PutUint32(buf[16:20], d.size)
+	binary.LittleEndian.PutUint32(buf[20:24], d.offset)
+	binary.LittleEndian.PutUint32(buf[24:28], d.adsType)
+	binary.LittleEndian.PutUint32(buf[28:32], flags)
+	binary.LittleEndian.PutUint16(buf[32:34], uint16(len(d.name)))
+	binary.LittleEndian.PutUint16(buf[34:36], uint16(len(d.typeName)))
+	binary.LittleEndian.PutUint16(buf[38:40], uint16(len(d.dims)))
+	binary.LittleEndian.PutUint16(buf[40:42], uint16(len(d.members)))
+
+	buf = append(buf, d.name...)
+	buf = append(buf, 0)
+	buf = append(buf, d.typeName...)
+	buf = append(buf, 0, 0) // type terminator, empty comment
+	for _, dim := range d.dims {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(dim.LowerBound))
+		buf = binary.LittleEndian.AppendUint32(buf, dim.Elements)
+	}
+	for _, m := range d.members {
+		buf = append(buf, buildDataTypeEntry(m)...)
+	}
+	if len(d.enums) > 0 {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(len(d.enums)))
+		for _, e := range d.enums {
+			buf = append(buf, byte(len(e.Name)))
+			buf = append(buf, e.Name...)
+			buf = append(buf, 0)
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(e.Value))
+		}
+	}
+
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(buf)))
+	return buf
+}
+
+func TestDecodeStructFromDataTypes(t *testing.T) {
+	const (
+		adstInt16 = 2
+		adstReal  = 4
+		adstBit   = 33
+	)
+
+	var upload []byte
+	upload = append(upload, buildDataTypeEntry(testDataType{
+		name: "E_Mode", typeName: "INT", size: 2, adsType: adstInt16,
+		enums: []EnumValue{{Name: "Idle", Value: 0}, {Name: "Running", Value: 1}},
+	})...)
+	upload = append(upload, buildDataTypeEntry(testDataType{
+		name: "T_Speed", typeName: "REAL", size: 4, adsType: adstReal,
+	})...)
+	upload = append(upload, buildDataTypeEntry(testDataType{
+		name: "ST_Axis", size: 8,
+		members: []testDataType{
+			{name: "Speed", typeName: "T_Speed", size: 4, offset: 0, adsType: adstReal},
+			{name: "Mode", typeName: "E_Mode", size: 2, offset: 4, adsType: adstInt16},
+			{name: "Enabled", typeName: "BIT", size: 1, offset: 48, adsType: adstBit, flags: dataTypeFlagBitValues},
+			{name: "Homed", typeName: "BIT", size: 1, offset: 49, adsType: adstBit, flags: dataTypeFlagBitValues},
+		},
+	})...)
+	upload = append(upload, buildDataTypeEntry(testDataType{
+		name: "ST_Machine", size: 20,
+		members: []testDataType{
+			{name: "Count", typeName: "DINT", size: 4, offset: 0},
+			{name: "Axes", typeName: "ARRAY [1..2] OF ST_Axis", size: 16, offset: 4,
+				dims: []ArrayDim{{LowerBound: 1, Elements: 2}}},
+		},
+	})...)
+	// Trailing bytes must not be mistaken for an entry
+	upload = append(upload, 0, 0, 0)
+
+	types := parseDataTypes(upload, 4)
+	if len(types) != 4 {
+		t.Fatalf("expected 4 data types, got %d", len(types))
+	}
+	if names := types["E_MODE"].EnumValues; len(names) != 2 || names[1].Name != "Running" {
+		t.Fatalf("enum values not parsed: %+v", names)
+	}
+
+	axis := func(speed float32, mode uint16, bits byte) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint32(b[0:4], math.Float32bits(speed))
+		binary.LittleEndian.PutUint16(b[4:6], mode)
+		b[6] = bits
+		return b
+	}
+	data := binary.LittleEndian.AppendUint32(nil, 7)
+	data = append(data, axis(1.5, 1, 0x01)...)
+	data = append(data, axis(2.5, 0, 0x02)...)
+
+	got, err := decodeTypedValue(types, "ST_Machine", data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"Count": int64(7),
+		"Axes": []interface{}{
+			map[string]interface{}{"Speed": float64(1.5), "Mode": "Running", "Enabled": true, "Homed": false},
+			map[string]interface{}{"Speed": float64(2.5), "Mode": "Idle", "Enabled": false, "Homed": true},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded value mismatch:\n got %#v\nwant %#v", got, want)
+	}
+}
+
+func TestParseArrayTypeName(t *testing.T) {
+	dims, elem, ok := parseArrayTypeName("ARRAY [0..4,1..2] OF ST_AXIS")
+	if !ok || elem != "ST_AXIS" {
+		t.Fatalf("unexpected result: %v %q %v", dims, elem, ok)
+	}
+	want := []ArrayDim{{LowerBound: 0, Elements: 5}, {LowerBound: 1, Elements: 2}}
+	if !reflect.DeepEqual(dims, want) {
+		t.Errorf("expected %v, got %v", want, dims)
+	}
+	if _, _, ok := parseArrayTypeName("INT"); ok {
+		t.Errorf("expected INT not to parse as an array")
+	}
+}
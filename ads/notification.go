@@ -0,0 +1,449 @@
+package ads
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// NotificationMode selects when the PLC sends device notifications
+// (ADS transmission mode).
+type NotificationMode uint32
+
+const (
+	NotifyCyclic   NotificationMode = 3 // ADSTRANS_SERVERCYCLE: send every cycle time
+	NotifyOnChange NotificationMode = 4 // ADSTRANS_SERVERONCHA: send when the value changed, checked every cycle time
+)
+
+// notificationBuffer is the capacity of a Subscription's value channel.
+const notificationBuffer = 64
+
+// maxUnclaimed bounds the samples held for handles not yet registered.
+const maxUnclaimed = 256
+
+// Subscription is an active ADS device notification for one symbol.
+// Samples are delivered on Values() as timestamped TagValues. The channel is
+// buffered; if the consumer falls behind, new samples are dropped and counted
+// in Dropped rather than stalling the connection's reader.
+//
+// When the connection drops, a TagValue with Error set to ErrConnectionLost
+// is delivered. Client.Reconnect re-registers all open subscriptions.
+type Subscription struct {
+	client    *Client
+	symbol    string
+	info      TagInfo
+	mode      NotificationMode
+	cycleTime time.Duration
+	maxDelay  time.Duration
+	ch        chan *TagValue
+
+	handle   uint32 // Guarded by client.subsMu; 0 while not registered
+	active   bool   // Guarded by client.subsMu
+	internal bool   // Used by the client itself (symbol version watch)
+
+	mu      sync.Mutex // Guards ch against close, closed, and dropped
+	closed  bool
+	dropped uint64
+}
+
+// notificationSample is a sample received before its handle was registered.
+type notificationSample struct {
+	timestamp time.Time
+	data      []byte
+}
+
+// Subscribe registers an ADS device notification for a symbol. In
+// NotifyOnChange mode the PLC checks the value every cycleTime and sends it
+// when it changed; in NotifyCyclic mode it sends it every cycleTime. maxDelay
+// lets the PLC batch samples for up to that long before sending (0 = send
+// immediately).
+//
+// Call Close on the returned Subscription to delete the notification.
+func (c *Client) Subscribe(symbol string, mode NotificationMode, cycleTime, maxDelay time.Duration) (*Subscription, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("Subscribe: nil client")
+	}
+
+	entry, err := c.getSymbolEntry(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("Subscribe: %w", err)
+	}
+
+	sub := &Subscription{
+		client:    c,
+		symbol:    symbol,
+		info:      entry.Info,
+		mode:      mode,
+		cycleTime: cycleTime,
+		maxDelay:  maxDelay,
+		ch:        make(chan *TagValue, notificationBuffer),
+		active:    true,
+	}
+
+	c.subsMu.Lock()
+	c.subs = append(c.subs, sub)
+	c.subsMu.Unlock()
+
+	if err := c.registerSubscription(sub); err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("Subscribe: %w", err)
+	}
+
+	logging.DebugLog("ADS", "Subscribed to %s (mode=%d, cycle=%v, maxDelay=%v)", symbol, mode, cycleTime, maxDelay)
+	return sub, nil
+}
+
+// Values returns the channel on which samples are delivered.
+// It is closed when the subscription or the client is closed.
+func (s *Subscription) Values() <-chan *TagValue {
+	return s.ch
+}
+
+// Symbol returns the subscribed symbol name.
+func (s *Subscription) Symbol() string {
+	return s.symbol
+}
+
+// Dropped returns the number of samples discarded because the channel was full.
+func (s *Subscription) Dropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Close deletes the device notification and closes the Values channel.
+func (s *Subscription) Close() error {
+	c := s.client
+
+	c.subsMu.Lock()
+	if !s.active {
+		c.subsMu.Unlock()
+		return nil
+	}
+	handle := s.handle
+	c.removeSubscriptionLocked(s)
+	c.subsMu.Unlock()
+
+	var err error
+	if handle != 0 {
+		c.mu.Lock()
+		if c.connected && c.conn != nil {
+			err = c.deleteNotificationUnsafe(handle)
+		}
+		c.mu.Unlock()
+	}
+
+	s.closeChannel()
+	logging.DebugLog("ADS", "Unsubscribed from %s", s.symbol)
+	return err
+}
+
+// deliver queues a value without blocking.
+func (s *Subscription) deliver(v *TagValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- v:
+	default:
+		s.dropped++
+		logging.DebugLog("ADS", "Notification for %s dropped (channel full)", s.symbol)
+	}
+}
+
+func (s *Subscription) closeChannel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// newValue builds a TagValue from a notification sample.
+func (s *Subscription) newValue(data []byte, timestamp time.Time) *TagValue {
+	return &TagValue{
+		Name:      s.symbol,
+		DataType:  s.info.TypeCode,
+		Bytes:     data,
+		Count:     symbolElementCount(&s.info, uint32(len(data))),
+		Timestamp: timestamp,
+	}
+}
+
+// removeSubscriptionLocked drops sub from the client (caller must hold c.subsMu).
+func (c *Client) removeSubscriptionLocked(sub *Subscription) {
+	sub.active = false
+	if sub.handle != 0 {
+		delete(c.notifyHandles, sub.handle)
+		sub.handle = 0
+	}
+	for i, s := range c.subs {
+		if s == sub {
+			c.subs = append(c.subs[:i], c.subs[i+1:]...)
+			break
+		}
+	}
+}
+
+// registerSubscription adds the device notification for sub on the current
+// connection and routes its samples to it.
+func (c *Client) registerSubscription(sub *Subscription) error {
+	handle, err := c.addNotification(&sub.info, sub.mode, sub.cycleTime, sub.maxDelay)
+	if err != nil {
+		return err
+	}
+
+	c.subsMu.Lock()
+	if !sub.active {
+		// Closed while the request was in flight.
+		c.subsMu.Unlock()
+		c.mu.Lock()
+		_ = c.deleteNotificationUnsafe(handle)
+		c.mu.Unlock()
+		return nil
+	}
+	defer c.subsMu.Unlock()
+
+	if c.notifyHandles == nil {
+		c.notifyHandles = make(map[uint32]*Subscription)
+	}
+	sub.handle = handle
+	c.notifyHandles[handle] = sub
+
+	// The first sample can arrive before the response has been processed.
+	if sample, ok := c.unclaimed[handle]; ok {
+		delete(c.unclaimed, handle)
+		sub.deliver(sub.newValue(sample.data, sample.timestamp))
+	}
+	return nil
+}
+
+// resubscribe re-registers all open subscriptions after a reconnect. A
+// subscription that cannot be registered receives an error value and is
+// retried on the next reconnect.
+func (c *Client) resubscribe() {
+	c.subsMu.Lock()
+	subs := append([]*Subscription(nil), c.subs...)
+	for _, sub := range subs {
+		sub.handle = 0
+	}
+	c.notifyHandles = nil
+	c.unclaimed = nil
+	c.subsMu.Unlock()
+
+	for _, sub := range subs {
+		if err := c.registerSubscription(sub); err != nil {
+			logging.DebugError("ADS", fmt.Sprintf("re-register notification for %s", sub.symbol), err)
+			sub.deliver(&TagValue{Name: sub.symbol, DataType: sub.info.TypeCode, Error: fmt.Errorf("re-register notification: %w", err)})
+		}
+	}
+
+	if len(subs) > 0 {
+		logging.DebugLog("ADS", "Re-registered %d notifications", len(subs))
+	}
+}
+
+// closeSubscriptionsUnsafe deletes all notifications and closes their
+// channels (caller must hold c.mu).
+func (c *Client) closeSubscriptionsUnsafe() {
+	c.subsMu.Lock()
+	subs := c.subs
+	c.subs = nil
+	c.notifyHandles = nil
+	c.unclaimed = nil
+	handles := make([]uint32, 0, len(subs))
+	for _, sub := range subs {
+		if sub.handle != 0 {
+			handles = append(handles, sub.handle)
+		}
+		sub.active = false
+		sub.handle = 0
+	}
+	c.subsMu.Unlock()
+
+	for _, handle := range handles {
+		_ = c.deleteNotificationUnsafe(handle)
+	}
+	for _, sub := range subs {
+		sub.closeChannel()
+	}
+}
+
+// startConnection installs the notification and close callbacks on conn and
+// starts its reader.
+func (c *Client) startConnection(conn *adsConnection) {
+	c.attachConnection(conn)
+	conn.start()
+}
+
+// attachConnection registers the client's notification and close callbacks
+// for its target port on conn. Returns false if another client sharing conn
+// already addresses that port.
+func (c *Client) attachConnection(conn *adsConnection) bool {
+	h := &portHandler{
+		onNotification: c.dispatchNotification,
+		onClosed: func(err error) {
+			c.connectionClosed(conn, err)
+		},
+	}
+	if !conn.addPort(c.targetPort, h) {
+		return false
+	}
+	c.handler = h
+	return true
+}
+
+// connectionClosed marks the client disconnected when its current connection
+// drops and tells subscribers that samples have stopped.
+func (c *Client) connectionClosed(conn *adsConnection, err error) {
+	c.mu.Lock()
+	if c.conn != conn {
+		// Closed deliberately by Close or Reconnect.
+		c.mu.Unlock()
+		return
+	}
+	c.connected = false
+	c.mu.Unlock()
+
+	logging.DebugDisconnect("ADS", c.targetNetId.String(), err.Error())
+
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	for _, sub := range c.subs {
+		sub.deliver(&TagValue{Name: sub.symbol, DataType: sub.info.TypeCode, Error: ErrConnectionLost})
+	}
+}
+
+// dispatchNotification decodes a device notification and delivers each
+// sample to its subscription. It runs on the connection's reader goroutine.
+//
+// Data: [Length 4][Stamps 4] then per stamp [Timestamp 8][Samples 4] and per
+// sample [Handle 4][Size 4][Data n].
+func (c *Client) dispatchNotification(data []byte) {
+	if len(data) < 8 {
+		return
+	}
+	stamps := binary.LittleEndian.Uint32(data[4:8])
+	offset := 8
+
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	for s := uint32(0); s < stamps; s++ {
+		if offset+12 > len(data) {
+			return
+		}
+		timestamp := fileTimeToTime(binary.LittleEndian.Uint64(data[offset : offset+8]))
+		samples := binary.LittleEndian.Uint32(data[offset+8 : offset+12])
+		offset += 12
+
+		for i := uint32(0); i < samples; i++ {
+			if offset+8 > len(data) {
+				return
+			}
+			handle := binary.LittleEndian.Uint32(data[offset : offset+4])
+			size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+			offset += 8
+			if size < 0 || offset+size > len(data) {
+				return
+			}
+			sample := make([]byte, size)
+			copy(sample, data[offset:offset+size])
+			offset += size
+
+			if sub, ok := c.notifyHandles[handle]; ok {
+				sub.deliver(sub.newValue(sample, timestamp))
+				continue
+			}
+			if c.unclaimed == nil {
+				c.unclaimed = make(map[uint32]notificationSample)
+			}
+			if len(c.unclaimed) >= maxUnclaimed {
+				// Samples for deleted handles; forget them.
+				c.unclaimed = make(map[uint32]notificationSample)
+			}
+			c.unclaimed[handle] = notificationSample{timestamp: timestamp, data: sample}
+		}
+	}
+}
+
+// addNotification sends AddDeviceNotification and returns the handle.
+func (c *Client) addNotification(info *TagInfo, mode NotificationMode, cycleTime, maxDelay time.Duration) (uint32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return 0, fmt.Errorf("not connected")
+	}
+
+	// Request: [IndexGroup 4] [IndexOffset 4] [Length 4] [TransMode 4]
+	// [MaxDelay 4] [CycleTime 4] [Reserved 16]. Times are in 100ns units.
+	req := make([]byte, 40)
+	binary.LittleEndian.PutUint32(req[0:4], info.IndexGroup)
+	binary.LittleEndian.PutUint32(req[4:8], info.IndexOffset)
+	binary.LittleEndian.PutUint32(req[8:12], info.Size)
+	binary.LittleEndian.PutUint32(req[12:16], uint32(mode))
+	binary.LittleEndian.PutUint32(req[16:20], uint32(maxDelay/100))
+	binary.LittleEndian.PutUint32(req[20:24], uint32(cycleTime/100))
+
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdAddDeviceNotify, req)
+	if err != nil {
+		if isConnectionError(err) {
+			c.connected = false
+		}
+		return 0, err
+	}
+
+	// Response: [Result 4] [Handle 4]
+	if len(resp) < 8 {
+		return 0, fmt.Errorf("response too short: %d bytes", len(resp))
+	}
+
+	result := binary.LittleEndian.Uint32(resp[0:4])
+	if result != 0 {
+		return 0, &AdsError{Code: result}
+	}
+
+	return binary.LittleEndian.Uint32(resp[4:8]), nil
+}
+
+// deleteNotificationUnsafe sends DeleteDeviceNotification (caller must hold c.mu).
+func (c *Client) deleteNotificationUnsafe(handle uint32) error {
+	if c.conn == nil {
+		return nil
+	}
+
+	req := make([]byte, 4)
+	binary.LittleEndian.PutUint32(req, handle)
+
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdDeleteDeviceNotify, req)
+	if err != nil {
+		return err
+	}
+
+	// Response: [Result 4]
+	if len(resp) < 4 {
+		return fmt.Errorf("response too short: %d bytes", len(resp))
+	}
+	if result := binary.LittleEndian.Uint32(resp[0:4]); result != 0 {
+		return &AdsError{Code: result}
+	}
+	return nil
+}
+
+// fileTimeToTime converts a Windows FILETIME (100ns intervals since
+// 1601-01-01 UTC) to a time.Time.
+func fileTimeToTime(ft uint64) time.Time {
+	const epochDiff = 116444736000000000 // 1601-01-01 to 1970-01-01 in 100ns
+	if ft < epochDiff {
+		return time.Time{}
+	}
+	ticks := ft - epochDiff
+	return time.Unix(int64(ticks/10000000), int64(ticks%10000000)*100).UTC()
+}
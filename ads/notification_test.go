@@ -0,0 +1,132 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeFrame is a request received by the fake PLC.
+type fakeFrame struct {
+	port     uint16
+	cmd      uint16
+	invokeId uint32
+	data     []byte
+}
+
+func readFakeFrame(r io.Reader) (fakeFrame, error) {
+	hdr := make([]byte, 6)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return fakeFrame{}, err
+	}
+	ams := make([]byte, binary.LittleEndian.Uint32(hdr[2:6]))
+	if _, err := io.ReadFull(r, ams); err != nil {
+		return fakeFrame{}, err
+	}
+	return fakeFrame{
+		port:     binary.LittleEndian.Uint16(ams[6:8]),
+		cmd:      binary.LittleEndian.Uint16(ams[16:18]),
+		invokeId: binary.LittleEndian.Uint32(ams[28:32]),
+		data:     ams[32:],
+	}, nil
+}
+
+func writeFakeFrame(w io.Writer, cmd, flags uint16, invokeId uint32, data []byte) error {
+	return writeFakeFrameFrom(w, 0, cmd, flags, invokeId, data)
+}
+
+// writeFakeFrameFrom writes a frame sent by the given AMS port.
+func writeFakeFrameFrom(w io.Writer, port, cmd, flags uint16, invokeId uint32, data []byte) error {
+	buf := make([]byte, 38+len(data))
+	binary.LittleEndian.PutUint32(buf[2:6], uint32(32+len(data)))
+	binary.LittleEndian.PutUint16(buf[20:22], port)
+	binary.LittleEndian.PutUint16(buf[22:24], cmd)
+	binary.LittleEndian.PutUint16(buf[24:26], flags)
+	binary.LittleEndian.PutUint32(buf[26:30], uint32(len(data)))
+	binary.LittleEndian.PutUint32(buf[34:38], invokeId)
+	copy(buf[38:], data)
+	_, err := w.Write(buf)
+	return err
+}
+
+func TestSubscribeDeliversNotifications(t *testing.T) {
+	local, plc := net.Pipe()
+	defer plc.Close()
+
+	const handle = 7
+	stamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fileTime := uint64(stamp.UnixNano()/100) + 116444736000000000
+
+	deleted := make(chan uint32, 1)
+	go func() {
+		for {
+			req, err := readFakeFrame(plc)
+			if err != nil {
+				return
+			}
+			switch req.cmd {
+			case CmdAddDeviceNotify:
+				resp := make([]byte, 8)
+				binary.LittleEndian.PutUint32(resp[4:8], handle)
+				writeFakeFrame(plc, req.cmd, StateFlagResponse, req.invokeId, resp)
+
+				// [Length][Stamps=1][Timestamp][Samples=1][Handle][Size=2][Data]
+				note := make([]byte, 8+12+8+2)
+				binary.LittleEndian.PutUint32(note[0:4], uint32(len(note)-4))
+				binary.LittleEndian.PutUint32(note[4:8], 1)
+				binary.LittleEndian.PutUint64(note[8:16], fileTime)
+				binary.LittleEndian.PutUint32(note[16:20], 1)
+				binary.LittleEndian.PutUint32(note[20:24], handle)
+				binary.LittleEndian.PutUint32(note[24:28], 2)
+				binary.LittleEndian.PutUint16(note[28:30], 42)
+				writeFakeFrame(plc, CmdDeviceNotification, StateFlagRequest, 0, note)
+			case CmdDeleteDeviceNotify:
+				deleted <- binary.LittleEndian.Uint32(req.data)
+				writeFakeFrame(plc, req.cmd, StateFlagResponse, req.invokeId, make([]byte, 4))
+			}
+		}
+	}()
+
+	c := &Client{
+		symbols: map[string]*SymbolEntry{
+			"MAIN.Count": {Info: TagInfo{Name: "MAIN.Count", TypeCode: TypeInt16, Size: 2, IndexGroup: 0x4040, IndexOffset: 8}},
+		},
+		connected: true,
+	}
+	conn := newAdsConnection(local, AmsNetId{}, 32800, 2*time.Second)
+	c.conn = conn
+	c.startConnection(conn)
+	defer c.Close()
+
+	sub, err := c.Subscribe("MAIN.Count", NotifyOnChange, 100*time.Millisecond, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case v := <-sub.Values():
+		if v.Error != nil {
+			t.Fatalf("unexpected error: %v", v.Error)
+		}
+		if got := v.GoValue(); got != int64(42) {
+			t.Errorf("expected 42, got %v", got)
+		}
+		if !v.Timestamp.Equal(stamp) {
+			t.Errorf("expected timestamp %v, got %v", stamp, v.Timestamp)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no notification delivered")
+	}
+
+	if err := sub.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := <-deleted; got != handle {
+		t.Errorf("expected delete of handle %d, got %d", handle, got)
+	}
+	if _, ok := <-sub.Values(); ok {
+		t.Errorf("expected Values channel to be closed")
+	}
+}
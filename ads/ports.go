@@ -0,0 +1,150 @@
+package ads
+
+import (
+	"fmt"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// OpenPort returns a client for another AMS port of the same target (e.g.
+// PortTC3PLC2 for a second PLC runtime, or PortSystemService) that shares
+// this client's TCP connection. The port client has its own symbol cache,
+// handles and subscriptions, and is closed when this client is closed.
+// Reconnecting either client re-establishes the shared connection for both.
+//
+// A port can only be opened once per connection; open it again after
+// closing the previous port client.
+func (c *Client) OpenPort(port uint16) (*Client, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("OpenPort: nil client")
+	}
+	if c.parent != nil {
+		return c.parent.OpenPort(port)
+	}
+
+	c.mu.Lock()
+	if c.conn == nil || !c.connected {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("not connected")
+	}
+
+	pc := &Client{
+		conn:         c.conn,
+		address:      c.address,
+		targetNetId:  c.targetNetId,
+		targetPort:   port,
+		localNetId:   c.localNetId,
+		localPort:    c.localPort,
+		sourceNetId:  c.sourceNetId,
+		parent:       c,
+		symbols:      make(map[string]*SymbolEntry),
+		connected:    true,
+		timeout:      c.timeout,
+		allowControl: c.allowControl,
+	}
+	if !pc.attachConnection(c.conn) {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("OpenPort: port %d is already open on this connection", port)
+	}
+	if c.ports == nil {
+		c.ports = make(map[uint16]*Client)
+	}
+	c.ports[port] = pc
+	c.mu.Unlock()
+
+	info, err := pc.readDeviceInfo()
+	if err != nil {
+		pc.Close()
+		return nil, fmt.Errorf("OpenPort: failed to read device info from port %d: %w", port, err)
+	}
+	pc.deviceInfo = info
+
+	if err := pc.watchSymbolVersion(); err != nil {
+		logging.DebugLog("ADS", "Symbol version watch unavailable on port %d: %v", port, err)
+	}
+
+	logging.DebugLog("ADS", "Opened port %d on %s: device=%s", port, c.targetNetId.String(), info.String())
+	return pc, nil
+}
+
+// openPorts returns the port clients sharing this client's connection.
+func (c *Client) openPorts() []*Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ports := make([]*Client, 0, len(c.ports))
+	for _, pc := range c.ports {
+		ports = append(ports, pc)
+	}
+	return ports
+}
+
+// detachUnsafe unregisters a port client from the shared connection and
+// from its parent (caller must hold c.mu).
+func (c *Client) detachUnsafe() {
+	if c.conn != nil && c.handler != nil {
+		c.conn.removePort(c.targetPort, c.handler)
+	}
+	c.conn = nil
+	c.handler = nil
+
+	parent := c.parent
+	parent.mu.Lock()
+	if parent.ports[c.targetPort] == c {
+		delete(parent.ports, c.targetPort)
+	}
+	parent.mu.Unlock()
+}
+
+// adoptConnection moves a port client onto its parent's connection after a
+// reconnect, dropping handles from the old connection and re-registering
+// subscriptions.
+func (c *Client) adoptConnection(conn *adsConnection) {
+	c.mu.Lock()
+	if c.conn == conn {
+		c.connected = true
+		c.mu.Unlock()
+		return
+	}
+	if c.conn != nil && c.handler != nil {
+		c.conn.removePort(c.targetPort, c.handler)
+	}
+	c.conn = conn
+	c.attachConnection(conn)
+	c.connected = true
+
+	c.symbolsMu.Lock()
+	for _, entry := range c.symbols {
+		entry.Handle = 0
+	}
+	c.symbolsMu.Unlock()
+	c.mu.Unlock()
+
+	c.resubscribe()
+}
+
+// reconnectPort reconnects a port client through its parent.
+func (c *Client) reconnectPort() error {
+	c.mu.Lock()
+	if c.connected {
+		c.mu.Unlock()
+		return nil
+	}
+	closed := c.conn == nil
+	c.mu.Unlock()
+	if closed {
+		return fmt.Errorf("port %d is closed", c.targetPort)
+	}
+
+	if err := c.parent.Reconnect(); err != nil {
+		return err
+	}
+
+	c.parent.mu.Lock()
+	conn := c.parent.conn
+	c.parent.mu.Unlock()
+	if conn == nil {
+		return fmt.Errorf("reconnect: parent not connected")
+	}
+	c.adoptConnection(conn)
+	return nil
+}
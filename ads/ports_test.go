@@ -0,0 +1,116 @@
+package ads
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestOpenPortSharesConnection(t *testing.T) {
+	local, plc := net.Pipe()
+	defer plc.Close()
+
+	// Each port numbers its notification handles independently, so both
+	// subscriptions below get handle 1.
+	const handle = 1
+	go func() {
+		for {
+			req, err := readFakeFrame(plc)
+			if err != nil {
+				return
+			}
+			switch req.cmd {
+			case CmdReadDeviceInfo:
+				resp := make([]byte, 24)
+				copy(resp[8:], "PLC")
+				writeFakeFrameFrom(plc, req.port, req.cmd, StateFlagResponse, req.invokeId, resp)
+			case CmdAddDeviceNotify:
+				resp := make([]byte, 8)
+				if binary.LittleEndian.Uint32(req.data[0:4]) == IndexGroupSymbolVersion {
+					binary.LittleEndian.PutUint32(resp[0:4], 0x0701) // Service not supported
+					writeFakeFrameFrom(plc, req.port, req.cmd, StateFlagResponse, req.invokeId, resp[:4])
+					continue
+				}
+				binary.LittleEndian.PutUint32(resp[4:8], handle)
+				writeFakeFrameFrom(plc, req.port, req.cmd, StateFlagResponse, req.invokeId, resp)
+
+				// Sample value is the sending port number
+				note := make([]byte, 8+12+8+2)
+				binary.LittleEndian.PutUint32(note[0:4], uint32(len(note)-4))
+				binary.LittleEndian.PutUint32(note[4:8], 1)
+				binary.LittleEndian.PutUint32(note[16:20], 1)
+				binary.LittleEndian.PutUint32(note[20:24], handle)
+				binary.LittleEndian.PutUint32(note[24:28], 2)
+				binary.LittleEndian.PutUint16(note[28:30], req.port)
+				writeFakeFrameFrom(plc, req.port, CmdDeviceNotification, StateFlagRequest, 0, note)
+			default:
+				writeFakeFrameFrom(plc, req.port, req.cmd, StateFlagResponse, req.invokeId, make([]byte, 4))
+			}
+		}
+	}()
+
+	counter := TagInfo{Name: "MAIN.Count", TypeCode: TypeInt16, Size: 2, IndexGroup: 0x4040}
+	c := &Client{
+		targetPort: PortTC3PLC1,
+		symbols:    map[string]*SymbolEntry{"MAIN.Count": {Info: counter}},
+		connected:  true,
+	}
+	conn := newAdsConnection(local, AmsNetId{}, 32800, 2*time.Second)
+	c.conn = conn
+	c.startConnection(conn)
+	defer c.Close()
+
+	pc, err := c.OpenPort(PortTC3PLC2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.OpenPort(PortTC3PLC2); err == nil {
+		t.Errorf("expected error opening port %d twice", PortTC3PLC2)
+	}
+	pc.symbols["MAIN.Count"] = &SymbolEntry{Info: counter}
+
+	for _, client := range []*Client{c, pc} {
+		sub, err := client.Subscribe("MAIN.Count", NotifyOnChange, 100*time.Millisecond, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case v := <-sub.Values():
+			if got := v.GoValue(); got != int64(client.targetPort) {
+				t.Errorf("port %d: expected sample from its own port, got %v", client.targetPort, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("port %d: no notification delivered", client.targetPort)
+		}
+	}
+
+	c.Close()
+	if pc.IsConnected() {
+		t.Errorf("expected port client to be closed with its parent")
+	}
+}
+
+func TestParseAddRouteResponse(t *testing.T) {
+	resp := make([]byte, 24)
+	binary.LittleEndian.PutUint32(resp[0:4], 0x71146603)
+	binary.LittleEndian.PutUint32(resp[4:8], 9)
+	binary.LittleEndian.PutUint32(resp[8:12], udpServiceAddRoute|udpServiceResponse)
+	binary.LittleEndian.PutUint32(resp[20:24], 1)
+	resp = binary.LittleEndian.AppendUint16(resp, routeTagStatus)
+	resp = binary.LittleEndian.AppendUint16(resp, 4)
+	resp = binary.LittleEndian.AppendUint32(resp, 0x0704)
+
+	if _, ok := parseAddRouteResponse(resp, 8); ok {
+		t.Errorf("expected response for another invoke ID to be ignored")
+	}
+	status, ok := parseAddRouteResponse(resp, 9)
+	if !ok || status != 0x0704 {
+		t.Errorf("expected status 0x0704, got 0x%X (ok=%v)", status, ok)
+	}
+
+	req := buildAddRouteRequest(9, RouteConfig{Name: "client", NetId: AmsNetId{10, 0, 0, 5, 1, 1}, Host: "10.0.0.5"})
+	if binary.LittleEndian.Uint32(req[8:12]) != udpServiceAddRoute || binary.LittleEndian.Uint32(req[20:24]) != 5 {
+		t.Errorf("unexpected request header % X", req[:24])
+	}
+}
@@ -3,11 +3,15 @@
 package ads
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
 	"net"
+	"os"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/yatesdr/plcio/logging"
 )
@@ -103,23 +107,118 @@ func nextInvokeId() uint32 {
 }
 
 // adsConnection handles the low-level TCP connection for ADS communication.
+// A background reader demultiplexes incoming frames: responses are matched
+// to their request by invoke ID, and device notifications (0x0008), which the
+// PLC sends unsolicited, are passed to the handler registered for the AMS
+// port that sent them. Several clients addressing different ports of the
+// target may share one connection (see Client.OpenPort); frame writes are
+// serialized, and each client serializes its own requests with its mutex.
 type adsConnection struct {
 	conn       net.Conn
 	localNetId AmsNetId
 	localPort  uint16
+	timeout    time.Duration // Per-request deadline (0 = none)
+	writeMu    sync.Mutex
+
+	// Per-port callbacks of the clients sharing the connection, keyed by
+	// target port. Each ADS server numbers its notification handles
+	// independently, so notifications are routed by their source port.
+	portsMu sync.Mutex
+	ports   map[uint16]*portHandler
+
+	pendingMu sync.Mutex
+	pending   map[uint32]chan amsFrame
+	done      chan struct{} // Closed when the reader exits
+	readErr   error         // Set before done is closed
 }
 
-// newAdsConnection creates a new ADS connection.
-func newAdsConnection(conn net.Conn, localNetId AmsNetId, localPort uint16) *adsConnection {
+// amsFrame is a received AMS header and its data.
+type amsFrame struct {
+	header amsHeader
+	data   []byte
+}
+
+// portHandler receives the unsolicited traffic for one target port.
+type portHandler struct {
+	// onNotification is called on the reader goroutine with the data of each
+	// device notification. It must not block or issue requests.
+	onNotification func(data []byte)
+	// onClosed is called once when the reader exits, after pending requests
+	// have been failed.
+	onClosed func(err error)
+}
+
+// newAdsConnection creates a new ADS connection. The reader is not started
+// until start is called, so handlers can be installed first.
+func newAdsConnection(conn net.Conn, localNetId AmsNetId, localPort uint16, timeout time.Duration) *adsConnection {
 	return &adsConnection{
 		conn:       conn,
 		localNetId: localNetId,
 		localPort:  localPort,
+		timeout:    timeout,
+		ports:      make(map[uint16]*portHandler),
+		pending:    make(map[uint32]chan amsFrame),
+		done:       make(chan struct{}),
 	}
 }
 
-// sendRequest sends an ADS request and returns the response data.
-func (c *adsConnection) sendRequest(targetNetId AmsNetId, targetPort uint16, cmdId uint16, data []byte) ([]byte, error) {
+// addPort registers the handler for a target port. It returns false if
+// another handler already owns the port.
+func (c *adsConnection) addPort(port uint16, h *portHandler) bool {
+	c.portsMu.Lock()
+	defer c.portsMu.Unlock()
+	if existing, ok := c.ports[port]; ok && existing != h {
+		return false
+	}
+	c.ports[port] = h
+	return true
+}
+
+// removePort unregisters h if it still owns the port.
+func (c *adsConnection) removePort(port uint16, h *portHandler) {
+	c.portsMu.Lock()
+	defer c.portsMu.Unlock()
+	if c.ports[port] == h {
+		delete(c.ports, port)
+	}
+}
+
+// start launches the background reader.
+func (c *adsConnection) start() {
+	go c.readLoop()
+}
+
+// ioDeadline returns the deadline for the next request: the connection
+// timeout, shortened to ctx's deadline if that is sooner. A zero time means
+// no deadline.
+func (c *adsConnection) ioDeadline(ctx context.Context) time.Time {
+	var deadline time.Time
+	if c.timeout > 0 {
+		deadline = time.Now().Add(c.timeout)
+	}
+	if ctx != nil {
+		if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+	}
+	return deadline
+}
+
+// withContextErr attaches ctx's error to an I/O failure caused by
+// cancellation so callers can match it with errors.Is.
+func withContextErr(ctx context.Context, err error) error {
+	if ctx != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ctx.Err(), err)
+	}
+	return err
+}
+
+// sendRequest sends an ADS request and returns the response data. ctx is the
+// calling client's bound context (see Client.BindContext) and may be nil.
+func (c *adsConnection) sendRequest(ctx context.Context, targetNetId AmsNetId, targetPort uint16, cmdId uint16, data []byte) ([]byte, error) {
+	if ctx != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	invokeId := nextInvokeId()
 
 	// Build AMS header
@@ -160,38 +259,152 @@ func (c *adsConnection) sendRequest(targetNetId AmsNetId, targetPort uint16, cmd
 		copy(buf[38:], data)
 	}
 
+	// Register before writing so a fast response is not dropped.
+	respCh := make(chan amsFrame, 1)
+	c.pendingMu.Lock()
+	c.pending[invokeId] = respCh
+	c.pendingMu.Unlock()
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, invokeId)
+		c.pendingMu.Unlock()
+	}()
+
 	logging.DebugTX("ADS", buf)
 
-	_, err := c.conn.Write(buf)
-	if err != nil {
+	deadline := c.ioDeadline(ctx)
+	if err := c.write(ctx, buf, deadline); err != nil {
 		logging.DebugError("ADS", "sendRequest write", err)
-		return nil, fmt.Errorf("write request: %w", err)
+		return nil, fmt.Errorf("write request: %w", withContextErr(ctx, err))
 	}
 
-	// Read response
-	return c.readResponse(invokeId)
+	// Wait for the reader to deliver the matching response. A request that
+	// times out or is cancelled here leaves the stream intact; a late
+	// response is simply discarded by the reader.
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var ctxDone <-chan struct{}
+	if ctx != nil {
+		ctxDone = ctx.Done()
+	}
+
+	select {
+	case frame := <-respCh:
+		// Check for AMS-level error
+		if frame.header.ErrorCode != 0 {
+			return nil, &AdsError{Code: frame.header.ErrorCode}
+		}
+		return frame.data, nil
+	case <-c.done:
+		return nil, c.readErr
+	case <-timeout:
+		logging.DebugLog("ADS", "Timeout waiting for response to invoke ID %d", invokeId)
+		return nil, fmt.Errorf("read response: %w", os.ErrDeadlineExceeded)
+	case <-ctxDone:
+		return nil, fmt.Errorf("read response: %w", ctx.Err())
+	}
 }
 
-// readResponse reads an ADS response from the connection.
-func (c *adsConnection) readResponse(expectedInvokeId uint32) ([]byte, error) {
+// write sends a complete frame. A write interrupted part way leaves the
+// stream unusable, so the connection is closed on any failure.
+func (c *adsConnection) write(ctx context.Context, buf []byte, deadline time.Time) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_ = c.conn.SetWriteDeadline(deadline)
+	defer c.conn.SetWriteDeadline(time.Time{})
+	if ctx != nil && ctx.Done() != nil {
+		conn := c.conn
+		stop := context.AfterFunc(ctx, func() {
+			_ = conn.SetWriteDeadline(time.Unix(1, 0))
+		})
+		defer stop()
+	}
+
+	if _, err := c.conn.Write(buf); err != nil {
+		c.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// readLoop reads frames until the connection fails or is closed, routing
+// responses to waiting requests and notifications to the sending port's
+// handler.
+func (c *adsConnection) readLoop() {
+	var err error
+	for {
+		var frame amsFrame
+		frame, err = c.readFrame()
+		if err != nil {
+			break
+		}
+
+		if frame.header.CommandId == CmdDeviceNotification && frame.header.StateFlags&0x0001 == 0 {
+			c.portsMu.Lock()
+			h := c.ports[frame.header.SourcePort]
+			c.portsMu.Unlock()
+			if h != nil && h.onNotification != nil {
+				h.onNotification(frame.data)
+			} else {
+				logging.DebugLog("ADS", "Discarding notification from unregistered port %d", frame.header.SourcePort)
+			}
+			continue
+		}
+
+		c.pendingMu.Lock()
+		respCh, ok := c.pending[frame.header.InvokeId]
+		c.pendingMu.Unlock()
+		if !ok {
+			logging.DebugLog("ADS", "Discarding response with unknown invoke ID %d (cmd 0x%04X)",
+				frame.header.InvokeId, frame.header.CommandId)
+			continue
+		}
+		select {
+		case respCh <- frame:
+		default: // Duplicate response; the first one is already queued
+		}
+	}
+
+	logging.DebugError("ADS", "reader stopped", err)
+	c.readErr = fmt.Errorf("connection lost: %w", err)
+	close(c.done)
+
+	c.portsMu.Lock()
+	handlers := make([]*portHandler, 0, len(c.ports))
+	for _, h := range c.ports {
+		handlers = append(handlers, h)
+	}
+	c.portsMu.Unlock()
+	for _, h := range handlers {
+		if h.onClosed != nil {
+			h.onClosed(c.readErr)
+		}
+	}
+}
+
+// readFrame reads one AMS/TCP frame from the connection.
+func (c *adsConnection) readFrame() (amsFrame, error) {
 	// Read TCP header (6 bytes)
 	tcpBuf := make([]byte, 6)
 	if _, err := io.ReadFull(c.conn, tcpBuf); err != nil {
-		logging.DebugError("ADS", "readResponse read TCP header", err)
-		return nil, fmt.Errorf("read TCP header: %w", err)
+		return amsFrame{}, fmt.Errorf("read TCP header: %w", err)
 	}
 
 	length := binary.LittleEndian.Uint32(tcpBuf[2:6])
 	if length < 32 {
 		logging.DebugLog("ADS", "Invalid AMS length: %d", length)
-		return nil, fmt.Errorf("invalid AMS length: %d", length)
+		return amsFrame{}, fmt.Errorf("invalid AMS length: %d", length)
 	}
 
 	// Read AMS header + data
 	amsBuf := make([]byte, length)
 	if _, err := io.ReadFull(c.conn, amsBuf); err != nil {
-		logging.DebugError("ADS", "readResponse read AMS data", err)
-		return nil, fmt.Errorf("read AMS data: %w", err)
+		return amsFrame{}, fmt.Errorf("read AMS data: %w", err)
 	}
 
 	// Log complete received packet (TCP header + AMS data)
@@ -199,32 +412,21 @@ func (c *adsConnection) readResponse(expectedInvokeId uint32) ([]byte, error) {
 	logging.DebugRX("ADS", fullPacket)
 
 	// Parse AMS header
-	var respHdr amsHeader
-	copy(respHdr.TargetNetId[:], amsBuf[0:6])
-	respHdr.TargetPort = binary.LittleEndian.Uint16(amsBuf[6:8])
-	copy(respHdr.SourceNetId[:], amsBuf[8:14])
-	respHdr.SourcePort = binary.LittleEndian.Uint16(amsBuf[14:16])
-	respHdr.CommandId = binary.LittleEndian.Uint16(amsBuf[16:18])
-	respHdr.StateFlags = binary.LittleEndian.Uint16(amsBuf[18:20])
-	respHdr.DataLength = binary.LittleEndian.Uint32(amsBuf[20:24])
-	respHdr.ErrorCode = binary.LittleEndian.Uint32(amsBuf[24:28])
-	respHdr.InvokeId = binary.LittleEndian.Uint32(amsBuf[28:32])
-
-	// Verify invoke ID
-	if respHdr.InvokeId != expectedInvokeId {
-		return nil, fmt.Errorf("invoke ID mismatch: expected %d, got %d", expectedInvokeId, respHdr.InvokeId)
-	}
-
-	// Check for AMS-level error
-	if respHdr.ErrorCode != 0 {
-		return nil, &AdsError{Code: respHdr.ErrorCode}
-	}
-
-	// Return data portion
-	return amsBuf[32:], nil
+	var hdr amsHeader
+	copy(hdr.TargetNetId[:], amsBuf[0:6])
+	hdr.TargetPort = binary.LittleEndian.Uint16(amsBuf[6:8])
+	copy(hdr.SourceNetId[:], amsBuf[8:14])
+	hdr.SourcePort = binary.LittleEndian.Uint16(amsBuf[14:16])
+	hdr.CommandId = binary.LittleEndian.Uint16(amsBuf[16:18])
+	hdr.StateFlags = binary.LittleEndian.Uint16(amsBuf[18:20])
+	hdr.DataLength = binary.LittleEndian.Uint32(amsBuf[20:24])
+	hdr.ErrorCode = binary.LittleEndian.Uint32(amsBuf[24:28])
+	hdr.InvokeId = binary.LittleEndian.Uint32(amsBuf[28:32])
+
+	return amsFrame{header: hdr, data: amsBuf[32:]}, nil
 }
 
-// close closes the underlying TCP connection.
+// close closes the underlying TCP connection, which also stops the reader.
 func (c *adsConnection) close() error {
 	if c.conn != nil {
 		return c.conn.Close()
@@ -0,0 +1,181 @@
+package ads
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/yatesdr/plcio/internal/ctxerr"
+	"github.com/yatesdr/plcio/logging"
+)
+
+// UDP service IDs (same port and framing as discovery)
+const (
+	udpServiceAddRoute    uint32 = 0x00000006
+	udpServiceResponse    uint32 = 0x80000000
+	udpAddRouteSourcePort uint16 = PortSystemService
+)
+
+// UDP add-route tag IDs
+const (
+	routeTagStatus   uint16 = 0x0001
+	routeTagPassword uint16 = 0x0002
+	routeTagHost     uint16 = 0x0005
+	routeTagNetId    uint16 = 0x0007
+	routeTagName     uint16 = 0x000C
+	routeTagUsername uint16 = 0x000D
+)
+
+// RouteConfig describes a static route to add on a TwinCAT target so it
+// accepts ADS connections from this machine.
+type RouteConfig struct {
+	Name     string   // Route name shown on the target (default: Host)
+	NetId    AmsNetId // AMS Net ID of this machine (default: derived from Host)
+	Host     string   // Address the target uses to reach this machine (default: local IP toward the target)
+	Username string   // Target OS user (e.g. "Administrator")
+	Password string   // Target OS password
+}
+
+// AddRoute registers a static route on the TwinCAT target at address using
+// the UDP add-route service (port 48899), which is what TwinCAT's "Add
+// Route" dialog does. The target's OS credentials are required. Adding a
+// route that already exists updates it.
+func AddRoute(address string, route RouteConfig, timeout time.Duration) error {
+	return AddRouteContext(context.Background(), address, route, timeout)
+}
+
+// AddRouteContext is like AddRoute but aborts when ctx is cancelled or its
+// deadline passes.
+func AddRouteContext(ctx context.Context, address string, route RouteConfig, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	udpAddr := net.JoinHostPort(address, strconv.Itoa(DiscoveryUDPPort))
+	d := net.Dialer{Timeout: timeout}
+	conn, err := d.DialContext(ctx, "udp4", udpAddr)
+	if err != nil {
+		return fmt.Errorf("add route: %w", err)
+	}
+	defer conn.Close()
+
+	// Fill in defaults from the local address used to reach the target
+	if route.Host == "" {
+		if localAddr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+			route.Host = localAddr.IP.String()
+		}
+	}
+	if route.NetId.IsZero() {
+		route.NetId, err = AmsNetIdFromIP(route.Host)
+		if err != nil {
+			return fmt.Errorf("add route: cannot derive AMS Net ID from %q: %w", route.Host, err)
+		}
+	}
+	if route.Name == "" {
+		route.Name = route.Host
+	}
+
+	invokeId := nextInvokeId()
+	packet := buildAddRouteRequest(invokeId, route)
+
+	deadline := time.Now().Add(timeout)
+	if dl, ok := ctx.Deadline(); ok && dl.Before(deadline) {
+		deadline = dl
+	}
+	_ = conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	logging.DebugLog("ADS", "Adding route %q (%s, %s) on %s", route.Name, route.NetId.String(), route.Host, address)
+	logging.DebugTX("ADS", packet)
+
+	if _, err := conn.Write(packet); err != nil {
+		return fmt.Errorf("add route: %w", ctxerr.Wrap(ctx, err))
+	}
+
+	buf := make([]byte, 1024)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return fmt.Errorf("add route: no response: %w", ctxerr.Wrap(ctx, err))
+		}
+		logging.DebugRX("ADS", buf[:n])
+
+		status, ok := parseAddRouteResponse(buf[:n], invokeId)
+		if !ok {
+			continue // Not our response
+		}
+		if status != 0 {
+			return fmt.Errorf("add route rejected: %w", &AdsError{Code: status})
+		}
+		return nil
+	}
+}
+
+// buildAddRouteRequest builds a UDP add-route request.
+//
+// Format: [Magic 4] [InvokeId 4] [Service 4] [SourceNetId 6] [SourcePort 2]
+// [TagCount 4] then tags of [Tag 2] [Length 2] [Data n]; strings are
+// null-terminated.
+func buildAddRouteRequest(invokeId uint32, route RouteConfig) []byte {
+	packet := make([]byte, 24)
+	binary.LittleEndian.PutUint32(packet[0:4], 0x71146603) // Magic (little-endian)
+	binary.LittleEndian.PutUint32(packet[4:8], invokeId)
+	binary.LittleEndian.PutUint32(packet[8:12], udpServiceAddRoute)
+	copy(packet[12:18], route.NetId[:])
+	binary.LittleEndian.PutUint16(packet[18:20], udpAddRouteSourcePort)
+	binary.LittleEndian.PutUint32(packet[20:24], 5)
+
+	addTag := func(tag uint16, data []byte) {
+		packet = binary.LittleEndian.AppendUint16(packet, tag)
+		packet = binary.LittleEndian.AppendUint16(packet, uint16(len(data)))
+		packet = append(packet, data...)
+	}
+	cstr := func(s string) []byte {
+		return append([]byte(s), 0)
+	}
+
+	addTag(routeTagName, cstr(route.Name))
+	addTag(routeTagNetId, route.NetId[:])
+	addTag(routeTagUsername, cstr(route.Username))
+	addTag(routeTagPassword, cstr(route.Password))
+	addTag(routeTagHost, cstr(route.Host))
+
+	return packet
+}
+
+// parseAddRouteResponse returns the status of an add-route response. ok is
+// false if data is not a response to the request with invokeId.
+func parseAddRouteResponse(data []byte, invokeId uint32) (status uint32, ok bool) {
+	if len(data) < 24 {
+		return 0, false
+	}
+	if binary.LittleEndian.Uint32(data[0:4]) != 0x71146603 ||
+		binary.LittleEndian.Uint32(data[4:8]) != invokeId ||
+		binary.LittleEndian.Uint32(data[8:12]) != udpServiceAddRoute|udpServiceResponse {
+		return 0, false
+	}
+
+	count := binary.LittleEndian.Uint32(data[20:24])
+	offset := 24
+	for i := uint32(0); i < count && offset+4 <= len(data); i++ {
+		tag := binary.LittleEndian.Uint16(data[offset : offset+2])
+		length := int(binary.LittleEndian.Uint16(data[offset+2 : offset+4]))
+		offset += 4
+		if offset+length > len(data) {
+			break
+		}
+		if tag == routeTagStatus && length >= 4 {
+			return binary.LittleEndian.Uint32(data[offset : offset+4]), true
+		}
+		offset += length
+	}
+
+	// No status tag: the route was accepted
+	return 0, true
+}
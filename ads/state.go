@@ -0,0 +1,177 @@
+package ads
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrControlNotAllowed is returned by WriteControl unless the client was
+// connected with WithAllowControl.
+var ErrControlNotAllowed = errors.New("ads: WriteControl not enabled (use WithAllowControl)")
+
+// AdsState is the ADS state of a device (ADSSTATE_*).
+type AdsState uint16
+
+// ADS states
+const (
+	StateInvalid      AdsState = 0
+	StateIdle         AdsState = 1
+	StateReset        AdsState = 2
+	StateInit         AdsState = 3
+	StateStart        AdsState = 4
+	StateRun          AdsState = 5
+	StateStop         AdsState = 6
+	StateSaveConfig   AdsState = 7
+	StateLoadConfig   AdsState = 8
+	StatePowerFailure AdsState = 9
+	StatePowerGood    AdsState = 10
+	StateError        AdsState = 11
+	StateShutdown     AdsState = 12
+	StateSuspend      AdsState = 13
+	StateResume       AdsState = 14
+	StateConfig       AdsState = 15
+	StateReconfig     AdsState = 16
+	StateStopping     AdsState = 17
+	StateIncompatible AdsState = 18
+	StateException    AdsState = 19
+)
+
+var adsStateNames = map[AdsState]string{
+	StateInvalid:      "INVALID",
+	StateIdle:         "IDLE",
+	StateReset:        "RESET",
+	StateInit:         "INIT",
+	StateStart:        "START",
+	StateRun:          "RUN",
+	StateStop:         "STOP",
+	StateSaveConfig:   "SAVECFG",
+	StateLoadConfig:   "LOADCFG",
+	StatePowerFailure: "POWERFAILURE",
+	StatePowerGood:    "POWERGOOD",
+	StateError:        "ERROR",
+	StateShutdown:     "SHUTDOWN",
+	StateSuspend:      "SUSPEND",
+	StateResume:       "RESUME",
+	StateConfig:       "CONFIG",
+	StateReconfig:     "RECONFIG",
+	StateStopping:     "STOPPING",
+	StateIncompatible: "INCOMPATIBLE",
+	StateException:    "EXCEPTION",
+}
+
+// String returns the TwinCAT name of the state (e.g. "RUN", "CONFIG").
+func (s AdsState) String() string {
+	if name, ok := adsStateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", uint16(s))
+}
+
+// State is the result of an ADS ReadState request.
+type State struct {
+	AdsState    AdsState // ADS state of the target port
+	DeviceState uint16   // Device-specific state
+}
+
+// String returns a human-readable state description.
+func (s State) String() string {
+	return fmt.Sprintf("%s (device state %d)", s.AdsState, s.DeviceState)
+}
+
+// ReadState reads the ADS state of the PLC runtime on the target port.
+// RUN means the runtime is executing; STOP means it is loaded but halted.
+func (c *Client) ReadState() (*State, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("ReadState: nil client")
+	}
+	return c.readState(c.targetPort)
+}
+
+// ReadSystemState reads the TwinCAT system state from the system service
+// port (10000). RUN means TwinCAT is in run mode; CONFIG means it is in
+// config mode and no PLC runtime is executing.
+func (c *Client) ReadSystemState() (*State, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("ReadSystemState: nil client")
+	}
+	return c.readState(PortSystemService)
+}
+
+// readState sends ReadState to the given AMS port.
+func (c *Client) readState(port uint16) (*State, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, port, CmdReadState, nil)
+	if err != nil {
+		if isConnectionError(err) {
+			c.connected = false
+		}
+		return nil, err
+	}
+
+	// Response: [Result 4] [AdsState 2] [DeviceState 2]
+	if len(resp) < 8 {
+		return nil, fmt.Errorf("response too short: %d bytes", len(resp))
+	}
+
+	result := binary.LittleEndian.Uint32(resp[0:4])
+	if result != 0 {
+		return nil, &AdsError{Code: result}
+	}
+
+	return &State{
+		AdsState:    AdsState(binary.LittleEndian.Uint16(resp[4:6])),
+		DeviceState: binary.LittleEndian.Uint16(resp[6:8]),
+	}, nil
+}
+
+// WriteControl requests a state transition of the PLC runtime on the target
+// port, e.g. StateRun to start it, StateStop to stop it, or StateReset to
+// reset it. deviceState is device-specific and usually 0. Requires
+// WithAllowControl.
+func (c *Client) WriteControl(state AdsState, deviceState uint16) error {
+	if c == nil || c.conn == nil {
+		return fmt.Errorf("WriteControl: nil client")
+	}
+	if !c.allowControl {
+		return ErrControlNotAllowed
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	// Request: [AdsState 2] [DeviceState 2] [Length 4] [Data n]
+	req := make([]byte, 8)
+	binary.LittleEndian.PutUint16(req[0:2], uint16(state))
+	binary.LittleEndian.PutUint16(req[2:4], deviceState)
+
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdWriteControl, req)
+	if err != nil {
+		if isConnectionError(err) {
+			c.connected = false
+		}
+		return err
+	}
+
+	// Response: [Result 4]
+	if len(resp) < 4 {
+		return fmt.Errorf("response too short: %d bytes", len(resp))
+	}
+
+	result := binary.LittleEndian.Uint32(resp[0:4])
+	if result != 0 {
+		return &AdsError{Code: result}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package ads
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestReadStateAndWriteControlGuard(t *testing.T) {
+	local, plc := net.Pipe()
+	defer plc.Close()
+
+	go func() {
+		for {
+			req, err := readFakeFrame(plc)
+			if err != nil {
+				return
+			}
+			if req.cmd == CmdReadState {
+				resp := make([]byte, 8)
+				binary.LittleEndian.PutUint16(resp[4:6], uint16(StateRun))
+				binary.LittleEndian.PutUint16(resp[6:8], 3)
+				writeFakeFrame(plc, req.cmd, StateFlagResponse, req.invokeId, resp)
+			}
+		}
+	}()
+
+	c := &Client{connected: true}
+	conn := newAdsConnection(local, AmsNetId{}, 32800, 2*time.Second)
+	c.conn = conn
+	c.startConnection(conn)
+	defer c.Close()
+
+	state, err := c.ReadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.AdsState != StateRun || state.DeviceState != 3 {
+		t.Errorf("unexpected state: %v", state)
+	}
+	if got := state.AdsState.String(); got != "RUN" {
+		t.Errorf("expected RUN, got %q", got)
+	}
+
+	if err := c.WriteControl(StateStop, 0); !errors.Is(err, ErrControlNotAllowed) {
+		t.Errorf("expected ErrControlNotAllowed, got %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package ads
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// maxSumItems is the most sub-requests TwinCAT accepts in one sum command.
+const maxSumItems = 500
+
+// sumWriteItem is one encoded value in a WriteMany batch.
+type sumWriteItem struct {
+	name   string
+	entry  *SymbolEntry
+	handle uint32 // entry.Handle, copied under symbolsMu when the request is built
+	data   []byte
+}
+
+// WriteMany writes several symbols using ADS SumUp requests: one SumUp
+// ReadWrite acquires any missing handles and one SumUp Write writes all
+// values, instead of separate round trips per symbol.
+//
+// The returned map has an entry for every symbol in values: nil on success,
+// otherwise the error for that symbol. The error return is non-nil only when
+// the connection was lost. If the PLC rejects sum commands (e.g. older
+// TwinCAT 2 runtimes), the symbols are written one at a time.
+func (c *Client) WriteMany(values map[string]interface{}) (map[string]error, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("WriteMany: nil client")
+	}
+
+	results := make(map[string]error, len(values))
+	if len(values) == 0 {
+		return results, nil
+	}
+
+	// Sort for a deterministic request order
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]*sumWriteItem, 0, len(names))
+	for _, name := range names {
+		entry, err := c.getSymbolEntry(name)
+		if err != nil {
+			results[name] = err
+			continue
+		}
+		if !entry.Info.IsWritable() {
+			results[name] = fmt.Errorf("symbol %q is read-only", name)
+			continue
+		}
+		data, err := encodeSymbolValue(&entry.Info, values[name])
+		if err != nil {
+			results[name] = fmt.Errorf("encode value: %w", err)
+			continue
+		}
+		items = append(items, &sumWriteItem{name: name, entry: entry, data: data})
+	}
+
+	logging.DebugLog("ADS", "WriteMany %d symbols (batched)", len(items))
+
+	for start := 0; start < len(items); start += maxSumItems {
+		chunk := items[start:min(start+maxSumItems, len(items))]
+		err := c.writeSumChunk(chunk, results)
+		if err == nil {
+			continue
+		}
+		if isConnectionError(err) {
+			logging.DebugDisconnect("ADS", c.targetNetId.String(), fmt.Sprintf("batch write failed: %v", err))
+			c.mu.Lock()
+			c.connected = false
+			c.mu.Unlock()
+			for _, item := range chunk {
+				results[item.name] = err
+			}
+			continue
+		}
+
+		// Sum commands not supported: fall back to individual writes
+		logging.DebugLog("ADS", "Batch write failed, falling back to individual writes: %v", err)
+		for _, item := range chunk {
+			results[item.name] = c.Write(item.name, values[item.name])
+		}
+	}
+
+	return results, c.connErrorIfDown()
+}
+
+// writeSumChunk acquires missing handles and writes one chunk of items,
+// recording per-item results once the chunk has been sent. A returned error
+// means the chunk as a whole failed and no results were recorded for it,
+// including handle failures, so the caller can retry or fail every item.
+func (c *Client) writeSumChunk(items []*sumWriteItem, results map[string]error) error {
+	var needHandles []*sumWriteItem
+	c.symbolsMu.RLock()
+	for _, item := range items {
+		if item.entry.Handle == 0 {
+			needHandles = append(needHandles, item)
+		}
+	}
+	c.symbolsMu.RUnlock()
+
+	failed := make(map[*sumWriteItem]error)
+	if len(needHandles) > 0 {
+		names := make([]string, len(needHandles))
+		for i, item := range needHandles {
+			names[i] = item.name
+		}
+		handles, errs, err := c.acquireHandles(names)
+		if err != nil {
+			return err
+		}
+		// Same checks as cacheHandle: a handle for a dropped entry or one
+		// another caller already cached is released again
+		var release []uint32
+		c.symbolsMu.Lock()
+		for i, item := range needHandles {
+			switch {
+			case errs[i] != nil:
+				failed[item] = errs[i]
+			case c.symbols[item.name] != item.entry:
+				failed[item] = ErrSymbolHandleInvalidated
+				release = append(release, handles[i])
+			case item.entry.Handle != 0:
+				release = append(release, handles[i])
+			default:
+				item.entry.Handle = handles[i]
+			}
+		}
+		c.symbolsMu.Unlock()
+
+		if len(release) > 0 {
+			c.mu.Lock()
+			for _, handle := range release {
+				_ = c.releaseHandleUnsafe(handle)
+			}
+			c.mu.Unlock()
+		}
+	}
+
+	writable := make([]*sumWriteItem, 0, len(items))
+	for _, item := range items {
+		if _, ok := failed[item]; !ok {
+			writable = append(writable, item)
+		}
+	}
+	if len(writable) > 0 {
+		errs, err := c.sumWrite(writable)
+		if err != nil {
+			return err
+		}
+		for i, item := range writable {
+			results[item.name] = errs[i]
+		}
+	}
+	for item, err := range failed {
+		results[item.name] = err
+	}
+	return nil
+}
+
+// acquireHandles gets handles for several symbol names in one SumUp
+// ReadWrite request. Returns a handle or an error per name.
+func (c *Client) acquireHandles(names []string) ([]uint32, []error, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil, nil, fmt.Errorf("not connected")
+	}
+
+	count := len(names)
+
+	// WriteData: for each name [IndexGroup 4][IndexOffset 4][ReadLength 4][WriteLength 4],
+	// followed by all null-terminated names
+	writeData := make([]byte, count*16)
+	for i, name := range names {
+		offset := i * 16
+		binary.LittleEndian.PutUint32(writeData[offset:offset+4], IndexGroupSymbolHandleByName)
+		binary.LittleEndian.PutUint32(writeData[offset+4:offset+8], 0)
+		binary.LittleEndian.PutUint32(writeData[offset+8:offset+12], 4) // Handle
+		binary.LittleEndian.PutUint32(writeData[offset+12:offset+16], uint32(len(name)+1))
+	}
+	for _, name := range names {
+		writeData = append(writeData, name...)
+		writeData = append(writeData, 0)
+	}
+
+	// SumUp ReadWrite response: [Result1 4][Length1 4]...[ResultN 4][LengthN 4][Data1]...[DataN]
+	readLen := uint32(count) * (8 + 4)
+
+	reqData := make([]byte, 16+len(writeData))
+	binary.LittleEndian.PutUint32(reqData[0:4], IndexGroupSumUpReadWrite)
+	binary.LittleEndian.PutUint32(reqData[4:8], uint32(count))
+	binary.LittleEndian.PutUint32(reqData[8:12], readLen)
+	binary.LittleEndian.PutUint32(reqData[12:16], uint32(len(writeData)))
+	copy(reqData[16:], writeData)
+
+	logging.DebugLog("ADS", "SumUp handle acquisition: %d symbols", count)
+
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdReadWrite, reqData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Parse response: [Result 4][ReadLength 4][SubResults...][Data...]
+	if len(resp) < 8 {
+		return nil, nil, fmt.Errorf("handle acquisition response too short: %d bytes", len(resp))
+	}
+
+	result := binary.LittleEndian.Uint32(resp[0:4])
+	if result != 0 {
+		return nil, nil, &AdsError{Code: result}
+	}
+
+	if len(resp) < 8+count*8 {
+		return nil, nil, fmt.Errorf("handle acquisition response truncated: %d bytes", len(resp))
+	}
+
+	handles := make([]uint32, count)
+	errs := make([]error, count)
+	dataOffset := 8 + count*8
+	for i := 0; i < count; i++ {
+		subResult := binary.LittleEndian.Uint32(resp[8+i*8 : 12+i*8])
+		length := int(binary.LittleEndian.Uint32(resp[12+i*8 : 16+i*8]))
+
+		switch {
+		case subResult != 0:
+			errs[i] = &AdsError{Code: subResult}
+		case length < 4 || dataOffset+4 > len(resp):
+			errs[i] = fmt.Errorf("handle data truncated")
+		default:
+			handles[i] = binary.LittleEndian.Uint32(resp[dataOffset : dataOffset+4])
+		}
+		dataOffset += length
+	}
+
+	return handles, errs, nil
+}
+
+// sumWrite writes several values by handle in one SumUp Write request.
+// Returns an error per item.
+func (c *Client) sumWrite(all []*sumWriteItem) ([]error, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	// Copy the handles while holding c.mu, so none is released before the
+	// request is sent. Items whose handle was dropped are not sent.
+	errs := make([]error, len(all))
+	items := make([]*sumWriteItem, 0, len(all))
+	sent := make([]int, 0, len(all))
+	c.symbolsMu.RLock()
+	for i, item := range all {
+		item.handle = item.entry.Handle
+		if item.handle == 0 {
+			errs[i] = ErrSymbolHandleInvalidated
+			continue
+		}
+		items = append(items, item)
+		sent = append(sent, i)
+	}
+	c.symbolsMu.RUnlock()
+	if len(items) == 0 {
+		return errs, nil
+	}
+
+	count := len(items)
+
+	// WriteData: for each item [IndexGroup 4][IndexOffset 4][Length 4],
+	// followed by all values
+	writeData := make([]byte, count*12)
+	for i, item := range items {
+		offset := i * 12
+		binary.LittleEndian.PutUint32(writeData[offset:offset+4], IndexGroupSymbolValueByHandle)
+		binary.LittleEndian.PutUint32(writeData[offset+4:offset+8], item.handle)
+		binary.LittleEndian.PutUint32(writeData[offset+8:offset+12], uint32(len(item.data)))
+	}
+	for _, item := range items {
+		writeData = append(writeData, item.data...)
+	}
+
+	// SumUp Write response: [Result1 4]...[ResultN 4]
+	readLen := uint32(count) * 4
+
+	reqData := make([]byte, 16+len(writeData))
+	binary.LittleEndian.PutUint32(reqData[0:4], IndexGroupSumUpWrite)
+	binary.LittleEndian.PutUint32(reqData[4:8], uint32(count))
+	binary.LittleEndian.PutUint32(reqData[8:12], readLen)
+	binary.LittleEndian.PutUint32(reqData[12:16], uint32(len(writeData)))
+	copy(reqData[16:], writeData)
+
+	logging.DebugLog("ADS", "SumUp Write: %d symbols, writeLen=%d", count, len(writeData))
+
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdReadWrite, reqData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response: [Result 4][ReadLength 4][SubResults...]
+	if len(resp) < 8 {
+		return nil, fmt.Errorf("batch write response too short: %d bytes", len(resp))
+	}
+
+	result := binary.LittleEndian.Uint32(resp[0:4])
+	if result != 0 {
+		return nil, &AdsError{Code: result}
+	}
+
+	if len(resp) < 8+count*4 {
+		return nil, fmt.Errorf("batch write response truncated: %d bytes", len(resp))
+	}
+
+	for i := 0; i < count; i++ {
+		if subResult := binary.LittleEndian.Uint32(resp[8+i*4 : 12+i*4]); subResult != 0 {
+			errs[sent[i]] = &AdsError{Code: subResult}
+		}
+	}
+
+	return errs, nil
+}
@@ -0,0 +1,137 @@
+package ads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWriteManyUsesSumCommands(t *testing.T) {
+	local, plc := net.Pipe()
+	defer plc.Close()
+
+	const errSymbolNotFound = 0x710
+	written := make(map[uint32][]byte)
+	var sumRequests []uint32
+
+	go func() {
+		for {
+			req, err := readFakeFrame(plc)
+			if err != nil {
+				return
+			}
+			if req.cmd != CmdReadWrite {
+				writeFakeFrame(plc, req.cmd, StateFlagResponse, req.invokeId, make([]byte, 4))
+				continue
+			}
+			group := binary.LittleEndian.Uint32(req.data[0:4])
+			count := int(binary.LittleEndian.Uint32(req.data[4:8]))
+			sub := req.data[16:]
+			sumRequests = append(sumRequests, group)
+
+			resp := make([]byte, 8)
+			switch group {
+			case IndexGroupSumUpReadWrite:
+				// Names follow the headers; hand out handles 100, 101, ...
+				names := bytes.Split(bytes.TrimSuffix(sub[count*16:], []byte{0}), []byte{0})
+				var data []byte
+				for i, name := range names {
+					if string(name) == "MAIN.Missing" {
+						resp = binary.LittleEndian.AppendUint32(resp, errSymbolNotFound)
+						resp = binary.LittleEndian.AppendUint32(resp, 0)
+						continue
+					}
+					resp = binary.LittleEndian.AppendUint32(resp, 0)
+					resp = binary.LittleEndian.AppendUint32(resp, 4)
+					data = binary.LittleEndian.AppendUint32(data, uint32(100+i))
+				}
+				resp = append(resp, data...)
+			case IndexGroupSumUpWrite:
+				offset := count * 12
+				for i := 0; i < count; i++ {
+					handle := binary.LittleEndian.Uint32(sub[i*12+4:])
+					size := int(binary.LittleEndian.Uint32(sub[i*12+8:]))
+					written[handle] = sub[offset : offset+size]
+					offset += size
+					resp = binary.LittleEndian.AppendUint32(resp, 0)
+				}
+			}
+			binary.LittleEndian.PutUint32(resp[4:8], uint32(len(resp)-8))
+			writeFakeFrame(plc, req.cmd, StateFlagResponse, req.invokeId, resp)
+		}
+	}()
+
+	c := &Client{
+		symbols: map[string]*SymbolEntry{
+			"MAIN.A":       {Info: TagInfo{Name: "MAIN.A", TypeCode: TypeInt16, Size: 2}},
+			"MAIN.B":       {Info: TagInfo{Name: "MAIN.B", TypeCode: TypeReal, Size: 4}},
+			"MAIN.Const":   {Info: TagInfo{Name: "MAIN.Const", TypeCode: TypeInt16, Size: 2, Flags: SymFlagReadOnly}},
+			"MAIN.Missing": {Info: TagInfo{Name: "MAIN.Missing", TypeCode: TypeInt16, Size: 2}},
+		},
+		connected: true,
+	}
+	conn := newAdsConnection(local, AmsNetId{}, 32800, 2*time.Second)
+	c.conn = conn
+	c.startConnection(conn)
+	defer c.Close()
+
+	results, err := c.WriteMany(map[string]interface{}{
+		"MAIN.A":       int16(42),
+		"MAIN.B":       float32(1.5),
+		"MAIN.Const":   int16(1),
+		"MAIN.Missing": int16(2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	if results["MAIN.A"] != nil || results["MAIN.B"] != nil {
+		t.Errorf("unexpected errors: A=%v B=%v", results["MAIN.A"], results["MAIN.B"])
+	}
+	if results["MAIN.Const"] == nil {
+		t.Errorf("expected read-only error for MAIN.Const")
+	}
+	var adsErr *AdsError
+	if !errors.As(results["MAIN.Missing"], &adsErr) || adsErr.Code != errSymbolNotFound {
+		t.Errorf("expected symbol-not-found for MAIN.Missing, got %v", results["MAIN.Missing"])
+	}
+
+	// Sorted order: MAIN.A, MAIN.B, MAIN.Missing get handles 100, 101, 102
+	if len(sumRequests) != 2 || sumRequests[0] != IndexGroupSumUpReadWrite || sumRequests[1] != IndexGroupSumUpWrite {
+		t.Fatalf("expected handle acquisition then sum write, got %x", sumRequests)
+	}
+	if got := binary.LittleEndian.Uint16(written[100]); got != 42 {
+		t.Errorf("MAIN.A: expected 42, got %d", got)
+	}
+	if len(written[101]) != 4 {
+		t.Errorf("MAIN.B: expected 4 bytes, got %d", len(written[101]))
+	}
+	if _, ok := written[102]; ok {
+		t.Errorf("MAIN.Missing should not have been written")
+	}
+}
+
+func TestSumWriteSkipsInvalidatedHandles(t *testing.T) {
+	local, plc := net.Pipe()
+	defer plc.Close()
+	defer local.Close()
+
+	// The symbol version changed after the items were built: no request
+	// may go out with the released handle.
+	entry := &SymbolEntry{Info: TagInfo{Name: "MAIN.A", TypeCode: TypeInt16, Size: 2}}
+	c := &Client{conn: newAdsConnection(local, AmsNetId{}, 32800, time.Second)}
+
+	errs, err := c.sumWrite([]*sumWriteItem{{name: "MAIN.A", entry: entry, data: []byte{1, 0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrSymbolHandleInvalidated) {
+		t.Errorf("expected ErrSymbolHandleInvalidated, got %v", errs)
+	}
+}
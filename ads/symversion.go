@@ -0,0 +1,207 @@
+package ads
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// symbolVersionCycle is how often the PLC checks the symbol version for the
+// change notification.
+const symbolVersionCycle = time.Second
+
+// ErrSymbolHandleInvalidated is returned by a read or write whose symbol
+// handle was released by a symbol version change while it was in flight.
+// Retrying looks the symbol up again.
+var ErrSymbolHandleInvalidated = errors.New("ads: symbol handle invalidated by symbol version change")
+
+// symbolVersionInfo describes the symbol version byte (index group 0xF008)
+// so it can be watched like a symbol.
+var symbolVersionInfo = TagInfo{
+	Name:       "SymbolVersion",
+	TypeCode:   TypeByte,
+	TypeName:   "BYTE",
+	Size:       1,
+	IndexGroup: IndexGroupSymbolVersion,
+}
+
+// WithSymbolVersionHandler registers a callback invoked after the PLC's symbol
+// version changes (online change or re-download) and the client has dropped
+// its cached symbols and handles. Use it to refresh tag lists, e.g. by calling
+// AllTags again. The callback runs on its own goroutine and may call the
+// client.
+func WithSymbolVersionHandler(fn func(oldVersion, newVersion uint8)) Option {
+	return func(o *options) {
+		o.onSymbolVersion = fn
+	}
+}
+
+// ReadSymbolVersion reads the PLC's symbol version counter, which TwinCAT
+// increments whenever the symbol table changes.
+func (c *Client) ReadSymbolVersion() (uint8, error) {
+	if c == nil || c.conn == nil {
+		return 0, fmt.Errorf("ReadSymbolVersion: nil client")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return 0, fmt.Errorf("not connected")
+	}
+
+	// Build read request: [IndexGroup 4] [IndexOffset 4] [Length 4]
+	req := make([]byte, 12)
+	binary.LittleEndian.PutUint32(req[0:4], IndexGroupSymbolVersion)
+	binary.LittleEndian.PutUint32(req[4:8], 0)
+	binary.LittleEndian.PutUint32(req[8:12], 1)
+
+	resp, err := c.conn.sendRequest(c.ctx, c.targetNetId, c.targetPort, CmdRead, req)
+	if err != nil {
+		return 0, err
+	}
+
+	// Response: [Result 4] [Length 4] [Version 1]
+	if len(resp) < 9 {
+		return 0, fmt.Errorf("response too short: %d bytes", len(resp))
+	}
+
+	result := binary.LittleEndian.Uint32(resp[0:4])
+	if result != 0 {
+		return 0, &AdsError{Code: result}
+	}
+
+	return resp[8], nil
+}
+
+// watchSymbolVersion registers an on-change notification for the symbol
+// version. Like any subscription it is re-registered by Reconnect, and the
+// first sample on the new connection reveals a change made while offline.
+func (c *Client) watchSymbolVersion() error {
+	sub := &Subscription{
+		client:    c,
+		symbol:    symbolVersionInfo.Name,
+		info:      symbolVersionInfo,
+		mode:      NotifyOnChange,
+		cycleTime: symbolVersionCycle,
+		ch:        make(chan *TagValue, 4),
+		active:    true,
+		internal:  true,
+	}
+
+	c.subsMu.Lock()
+	c.subs = append(c.subs, sub)
+	c.subsMu.Unlock()
+
+	if err := c.registerSubscription(sub); err != nil {
+		sub.Close()
+		return err
+	}
+
+	go func() {
+		for v := range sub.Values() {
+			if v.Error == nil && len(v.Bytes) > 0 {
+				c.symbolVersionChanged(v.Bytes[0])
+			}
+		}
+	}()
+	return nil
+}
+
+// SymbolVersion returns the last symbol version seen by the client and
+// whether one has been seen yet.
+func (c *Client) SymbolVersion() (uint8, bool) {
+	c.symbolsMu.RLock()
+	defer c.symbolsMu.RUnlock()
+	return c.symbolVersion, c.symbolVersionKnown
+}
+
+// symbolVersionChanged records a symbol version sample. When it differs from
+// the previous one, cached symbol entries, data types and handles are invalidated,
+// subscriptions are moved to the symbols' new locations, and the handler is
+// called.
+func (c *Client) symbolVersionChanged(version uint8) {
+	c.symbolsMu.Lock()
+	oldVersion, known := c.symbolVersion, c.symbolVersionKnown
+	c.symbolVersion = version
+	c.symbolVersionKnown = true
+	if !known || oldVersion == version {
+		c.symbolsMu.Unlock()
+		return
+	}
+
+	// Zero the handles before releasing them so requests still holding a
+	// dropped entry cannot use a handle the PLC may reassign
+	var handles []uint32
+	for _, entry := range c.symbols {
+		if entry.Handle != 0 {
+			handles = append(handles, entry.Handle)
+			entry.Handle = 0
+		}
+	}
+	c.symbols = make(map[string]*SymbolEntry)
+	c.symbolsLoaded = false
+	c.dataTypes = nil
+	c.symbolsMu.Unlock()
+
+	logging.DebugLog("ADS", "Symbol version changed %d -> %d, dropped symbol cache and %d handles",
+		oldVersion, version, len(handles))
+
+	c.mu.Lock()
+	for _, handle := range handles {
+		_ = c.releaseHandleUnsafe(handle)
+	}
+	c.mu.Unlock()
+
+	c.refreshSubscriptions()
+
+	if c.onSymbolVersion != nil {
+		c.onSymbolVersion(oldVersion, version)
+	}
+}
+
+// refreshSubscriptions re-resolves subscribed symbols after a symbol version
+// change and re-registers those whose location or size moved.
+func (c *Client) refreshSubscriptions() {
+	c.subsMu.Lock()
+	subs := make([]*Subscription, 0, len(c.subs))
+	for _, sub := range c.subs {
+		if !sub.internal {
+			subs = append(subs, sub)
+		}
+	}
+	c.subsMu.Unlock()
+
+	for _, sub := range subs {
+		entry, err := c.getSymbolEntry(sub.symbol)
+		if err != nil {
+			sub.deliver(&TagValue{Name: sub.symbol, DataType: sub.info.TypeCode, Error: fmt.Errorf("resolve after symbol change: %w", err)})
+			continue
+		}
+		if entry.Info.IndexGroup == sub.info.IndexGroup && entry.Info.IndexOffset == sub.info.IndexOffset &&
+			entry.Info.Size == sub.info.Size && entry.Info.TypeCode == sub.info.TypeCode {
+			continue
+		}
+
+		c.subsMu.Lock()
+		oldHandle := sub.handle
+		if oldHandle != 0 {
+			delete(c.notifyHandles, oldHandle)
+			sub.handle = 0
+		}
+		sub.info = entry.Info
+		c.subsMu.Unlock()
+
+		if oldHandle != 0 {
+			c.mu.Lock()
+			_ = c.deleteNotificationUnsafe(oldHandle)
+			c.mu.Unlock()
+		}
+		if err := c.registerSubscription(sub); err != nil {
+			sub.deliver(&TagValue{Name: sub.symbol, DataType: sub.info.TypeCode, Error: fmt.Errorf("re-register notification: %w", err)})
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package ads
+
+import "testing"
+
+func TestSymbolVersionChangeInvalidatesCache(t *testing.T) {
+	var calls [][2]uint8
+	entry := &SymbolEntry{Info: TagInfo{Name: "MAIN.Speed", IndexOffset: 8}, Handle: 5}
+	c := &Client{
+		symbols:       map[string]*SymbolEntry{"MAIN.Speed": entry},
+		symbolsLoaded: true,
+		onSymbolVersion: func(oldVersion, newVersion uint8) {
+			calls = append(calls, [2]uint8{oldVersion, newVersion})
+		},
+	}
+
+	// The first sample only establishes the baseline.
+	c.symbolVersionChanged(3)
+	c.symbolVersionChanged(3)
+	if len(c.symbols) != 1 || len(calls) != 0 {
+		t.Fatalf("cache invalidated without a version change")
+	}
+
+	c.symbolVersionChanged(4)
+	if len(c.symbols) != 0 || c.symbolsLoaded {
+		t.Errorf("expected symbol cache to be cleared")
+	}
+	if entry.Handle != 0 {
+		t.Errorf("dropped entry still holds released handle %d", entry.Handle)
+	}
+	if _, err := c.entryHandleUnsafe(entry); err != ErrSymbolHandleInvalidated {
+		t.Errorf("expected ErrSymbolHandleInvalidated, got %v", err)
+	}
+	if len(calls) != 1 || calls[0] != [2]uint8{3, 4} {
+		t.Errorf("expected one handler call 3 -> 4, got %v", calls)
+	}
+	if v, ok := c.SymbolVersion(); !ok || v != 4 {
+		t.Errorf("expected symbol version 4, got %d (known=%v)", v, ok)
+	}
+}
+
+func TestCacheHandleChecksEntry(t *testing.T) {
+	entry := &SymbolEntry{Info: TagInfo{Name: "MAIN.Speed"}}
+	c := &Client{symbols: map[string]*SymbolEntry{"MAIN.Speed": entry}}
+
+	if err := c.cacheHandle("MAIN.Speed", entry, 5); err != nil || entry.Handle != 5 {
+		t.Fatalf("cacheHandle: handle %d, err %v", entry.Handle, err)
+	}
+	// A second caller's handle must not overwrite the cached one.
+	if err := c.cacheHandle("MAIN.Speed", entry, 6); err != nil || entry.Handle != 5 {
+		t.Errorf("second cacheHandle: handle %d, err %v", entry.Handle, err)
+	}
+
+	// A handle acquired for an entry dropped meanwhile is not stored.
+	dropped := &SymbolEntry{Info: TagInfo{Name: "MAIN.Level"}}
+	if err := c.cacheHandle("MAIN.Level", dropped, 7); err != ErrSymbolHandleInvalidated {
+		t.Errorf("expected ErrSymbolHandleInvalidated, got %v", err)
+	}
+	if dropped.Handle != 0 {
+		t.Errorf("dropped entry got handle %d", dropped.Handle)
+	}
+}
@@ -3,16 +3,18 @@ package ads
 import (
 	"encoding/binary"
 	"math"
+	"time"
 )
 
 // TagValue holds the result of a tag read operation.
 // This structure is designed to be compatible with the warlink plcman package.
 type TagValue struct {
-	Name     string // Symbol name
-	DataType uint16 // ADS type code
-	Bytes    []byte // Raw value bytes (little-endian, native x86/TwinCAT format)
-	Count    int    // Number of elements (1 for scalar, >1 for array)
-	Error    error  // Per-tag error (nil if successful)
+	Name      string    // Symbol name
+	DataType  uint16    // ADS type code
+	Bytes     []byte    // Raw value bytes (little-endian, native x86/TwinCAT format)
+	Count     int       // Number of elements (1 for scalar, >1 for array)
+	Error     error     // Per-tag error (nil if successful)
+	Timestamp time.Time // PLC sample time for notification values (zero for reads)
 }
 
 // GoValue returns the decoded Go value from the raw bytes.
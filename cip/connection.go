@@ -70,8 +70,36 @@ type ForwardOpenConfig struct {
 	// Vendor/serial for connection tracking
 	VendorID         uint16
 	OriginatorSerial uint32
+
+	// Class 1 (implicit I/O) parameters. Zero values keep the Class 3
+	// explicit messaging defaults the Logix and Omron drivers rely on.
+	OTRPI             time.Duration // Requested packet interval O->T (default ~2.1s)
+	TORPI             time.Duration // Requested packet interval T->O (default ~2.1s)
+	OTNetworkParams   uint16        // Connection type/priority/fixed bits, size bits ignored (default 0x4200)
+	TONetworkParams   uint16        // As OTNetworkParams for T->O
+	TransportTrigger  byte          // Transport class and trigger (default 0xA3, Class 3 server)
+	TimeoutMultiplier byte          // Timeout as 4 << n times the RPI (default 3, i.e. 32x)
 }
 
+// Network connection parameter flags (16-bit layout; the large Forward Open
+// shifts them into the high word).
+const (
+	NetParamsFixedSize         uint16 = 0x0000
+	NetParamsVariableSize      uint16 = 0x0200
+	NetParamsPriorityLow       uint16 = 0x0000
+	NetParamsPriorityHigh      uint16 = 0x0400
+	NetParamsPriorityScheduled uint16 = 0x0800
+	NetParamsMulticast         uint16 = 0x2000
+	NetParamsPointToPoint      uint16 = 0x4000
+	NetParamsRedundantOwner    uint16 = 0x8000
+)
+
+// Transport class/trigger values for ForwardOpenConfig.TransportTrigger.
+const (
+	TransportClass1Cyclic byte = 0x01 // Class 1, cyclic trigger, client
+	TransportClass3Server byte = 0xA3 // Class 3, application trigger, server
+)
+
 // DefaultForwardOpenConfig returns a config with sensible defaults for Logix.
 func DefaultForwardOpenConfig() ForwardOpenConfig {
 	return ForwardOpenConfig{
@@ -112,16 +140,37 @@ func buildForwardOpenInternal(cfg ForwardOpenConfig, large bool) ([]byte, uint16
 
 	otRPI := uint32(0x00201234)  // ~2.1 seconds
 	toRPI := uint32(0x00204001)  // ~2.1 seconds
-	connParamsBase := uint16(0x4200)
+	if cfg.OTRPI > 0 {
+		otRPI = uint32(cfg.OTRPI / time.Microsecond)
+	}
+	if cfg.TORPI > 0 {
+		toRPI = uint32(cfg.TORPI / time.Microsecond)
+	}
+	otParamsBase := uint16(0x4200)
+	toParamsBase := uint16(0x4200)
+	if cfg.OTNetworkParams != 0 {
+		otParamsBase = cfg.OTNetworkParams &^ 0x01FF
+	}
+	if cfg.TONetworkParams != 0 {
+		toParamsBase = cfg.TONetworkParams &^ 0x01FF
+	}
+	transport := TransportClass3Server
+	if cfg.TransportTrigger != 0 {
+		transport = cfg.TransportTrigger
+	}
+	multiplier := uint32(0x03)
+	if cfg.TimeoutMultiplier != 0 {
+		multiplier = uint32(cfg.TimeoutMultiplier)
+	}
 
 	// Connection parameters: 0x4200 + size for standard, (0x4200 << 16) + size for large
 	var otParams, toParams uint32
 	if large {
-		otParams = (uint32(connParamsBase) << 16) | uint32(cfg.OTConnectionSize)
-		toParams = (uint32(connParamsBase) << 16) | uint32(cfg.TOConnectionSize)
+		otParams = (uint32(otParamsBase) << 16) | uint32(cfg.OTConnectionSize)
+		toParams = (uint32(toParamsBase) << 16) | uint32(cfg.TOConnectionSize)
 	} else {
-		otParams = uint32(connParamsBase) | uint32(cfg.OTConnectionSize)
-		toParams = uint32(connParamsBase) | uint32(cfg.TOConnectionSize)
+		otParams = uint32(otParamsBase) | uint32(cfg.OTConnectionSize)
+		toParams = uint32(toParamsBase) | uint32(cfg.TOConnectionSize)
 	}
 
 	// Determine service code (pylogix: 0x54 for ≤511, 0x5B for >511)
@@ -169,7 +218,7 @@ func buildForwardOpenInternal(cfg ForwardOpenConfig, large bool) ([]byte, uint16
 
 	// Connection Timeout Multiplier (4 bytes) - pylogix packs as I (includes 3 reserved)
 	// Value 0x03 = multiplier 3
-	data = binary.LittleEndian.AppendUint32(data, 0x03)
+	data = binary.LittleEndian.AppendUint32(data, multiplier)
 
 	// O->T RPI (4 bytes)
 	data = binary.LittleEndian.AppendUint32(data, otRPI)
@@ -192,7 +241,7 @@ func buildForwardOpenInternal(cfg ForwardOpenConfig, large bool) ([]byte, uint16
 	}
 
 	// Transport Type/Trigger (1 byte) - 0xA3
-	data = append(data, 0xA3)
+	data = append(data, transport)
 
 	// Connection Path Size (1 byte, in words)
 	pathSizeWords := byte(len(cfg.ConnectionPath) / 2)
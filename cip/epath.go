@@ -72,6 +72,14 @@ func (b *PathBuilder) Instance32(id uint32) *PathBuilder {
 	return b.add(logicalSegment(CipLogicalTypeInstanceId, CipLogicalFormat32bit, binary.LittleEndian.AppendUint32(nil, id), b.padded))
 }
 
+func (b *PathBuilder) ConnectionPoint(id byte) *PathBuilder {
+	return b.add(logicalSegment(CipLogicalTypeConnectionPoint, CipLogicalFormat8bit, []byte{id}, b.padded))
+}
+
+func (b *PathBuilder) ConnectionPoint16(id uint16) *PathBuilder {
+	return b.add(logicalSegment(CipLogicalTypeConnectionPoint, CipLogicalFormat16bit, binary.LittleEndian.AppendUint16(nil, id), b.padded))
+}
+
 func (b *PathBuilder) Attribute(id byte) *PathBuilder {
 	return b.add(logicalSegment(CipLogicalTypeAttributeId, CipLogicalFormat8bit, []byte{id}, b.padded))
 }
@@ -209,14 +217,16 @@ func splitTagPath(tag string) []tagPart {
 				j++
 			}
 			if j > i+1 {
-				indexStr := tag[i+1 : j]
-				var idx uint32
-				for _, c := range indexStr {
-					if c >= '0' && c <= '9' {
-						idx = idx*10 + uint32(c-'0')
+				// Multi-dimensional indices ("[2,3,0]") get one member segment each
+				for _, indexStr := range strings.Split(tag[i+1:j], ",") {
+					var idx uint32
+					for _, c := range indexStr {
+						if c >= '0' && c <= '9' {
+							idx = idx*10 + uint32(c-'0')
+						}
 					}
+					parts = append(parts, tagPart{index: idx, isIndex: true})
 				}
-				parts = append(parts, tagPart{index: idx, isIndex: true})
 			}
 			i = j // Skip past the ']'
 		case ']':
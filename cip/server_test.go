@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"testing"
+	"time"
 )
 
 func TestForwardOpenRoundTripStandard(t *testing.T) {
@@ -55,6 +56,50 @@ func TestForwardOpenRoundTripLarge(t *testing.T) {
 	}
 }
 
+func TestForwardOpenClass1Params(t *testing.T) {
+	cfg := DefaultForwardOpenConfig()
+	cfg.ConnectionPath = []byte{0x20, 0x04, 0x24, 0x80, 0x2C, 0x66, 0x2C, 0x65}
+	cfg.OTConnectionSize = 10
+	cfg.TOConnectionSize = 18
+	cfg.OTRPI = 20 * time.Millisecond
+	cfg.TORPI = 50 * time.Millisecond
+	cfg.OTNetworkParams = NetParamsPointToPoint | NetParamsPriorityScheduled
+	cfg.TONetworkParams = NetParamsMulticast | NetParamsPriorityScheduled
+	cfg.TransportTrigger = TransportClass1Cyclic
+	cfg.TimeoutMultiplier = 2
+
+	reqBytes, _, err := BuildForwardOpenRequestSmall(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseForwardOpenRequest(reqBytes[2+int(reqBytes[1])*2:], false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OTRPI != 20000 || r.TORPI != 50000 {
+		t.Errorf("RPIs = %d/%d us, want 20000/50000", r.OTRPI, r.TORPI)
+	}
+	if r.OTParams != 0x480A || r.TOParams != 0x2812 {
+		t.Errorf("params = 0x%04X/0x%04X, want 0x480A/0x2812", r.OTParams, r.TOParams)
+	}
+	if r.TransportTrigger != 0x01 || r.TimeoutMultiplier != 2 {
+		t.Errorf("transport 0x%02X multiplier %d", r.TransportTrigger, r.TimeoutMultiplier)
+	}
+
+	// Large format moves the flags into the high word
+	reqBytes, _, err = BuildForwardOpenRequest(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = ParseForwardOpenRequest(reqBytes[2+int(reqBytes[1])*2:], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OTParams != 0x4800000A {
+		t.Errorf("large O->T params = 0x%08X, want 0x4800000A", r.OTParams)
+	}
+}
+
 func TestForwardOpenSuccessResponse(t *testing.T) {
 	s := ForwardOpenSuccess{
 		OTConnectionID:   0x11111111,
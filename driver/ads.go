@@ -1,6 +1,7 @@
 package driver
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/yatesdr/plcio/ads"
@@ -25,6 +26,12 @@ func NewADSAdapter(cfg *PLCConfig) (*ADSAdapter, error) {
 
 // Connect establishes connection to the TwinCAT PLC.
 func (a *ADSAdapter) Connect() error {
+	return a.ConnectContext(context.Background())
+}
+
+// ConnectContext establishes connection to the TwinCAT PLC, aborting the dial and
+// handshake if ctx is cancelled or its deadline passes.
+func (a *ADSAdapter) ConnectContext(ctx context.Context) error {
 	opts := []ads.Option{}
 
 	if a.config.Timeout > 0 {
@@ -36,8 +43,20 @@ func (a *ADSAdapter) Connect() error {
 	if a.config.AmsPort > 0 {
 		opts = append(opts, ads.WithAmsPort(a.config.AmsPort))
 	}
+	if a.config.AmsSourceNetId != "" {
+		opts = append(opts, ads.WithSourceNetId(a.config.AmsSourceNetId))
+	}
+	if a.config.AmsRouteUsername != "" {
+		opts = append(opts, ads.WithRoute(ads.RouteConfig{
+			Username: a.config.AmsRouteUsername,
+			Password: a.config.AmsRoutePassword,
+		}))
+	}
+	if a.config.AllowControl {
+		opts = append(opts, ads.WithAllowControl())
+	}
 
-	client, err := ads.Connect(a.config.Address, opts...)
+	client, err := ads.ConnectContext(ctx, a.config.Address, opts...)
 	if err != nil {
 		return fmt.Errorf("ads connect: %w", err)
 	}
@@ -84,14 +103,23 @@ func (a *ADSAdapter) GetDeviceInfo() (*DeviceInfo, error) {
 		return nil, err
 	}
 
-	return &DeviceInfo{
+	devInfo := &DeviceInfo{
 		Family:       FamilyBeckhoff,
 		Vendor:       "Beckhoff",
 		Model:        info.DeviceName,
 		Version:      fmt.Sprintf("%d.%d.%d", info.MajorVersion, info.MinorVersion, info.BuildVersion),
 		SerialNumber: "",
 		Description:  "TwinCAT PLC",
-	}, nil
+	}
+
+	// Run state of the PLC runtime; CONFIG mode means no runtime is executing.
+	if sys, err := a.client.ReadSystemState(); err == nil && sys.AdsState != ads.StateRun {
+		devInfo.RunState = sys.AdsState.String()
+	} else if state, err := a.client.ReadState(); err == nil {
+		devInfo.RunState = state.AdsState.String()
+	}
+
+	return devInfo, nil
 }
 
 // SupportsDiscovery returns true since TwinCAT supports symbol discovery.
@@ -158,7 +186,8 @@ func (a *ADSAdapter) Read(requests []TagRequest) ([]*TagValue, error) {
 			continue
 		}
 
-		goValue := v.GoValue()
+		// Use decoded value for structures when possible
+		goValue := v.GoValueDecoded(a.client)
 
 		result[i] = &TagValue{
 			Name:        v.Name,
@@ -183,6 +212,65 @@ func (a *ADSAdapter) Write(tag string, value interface{}) error {
 	return a.client.Write(tag, value)
 }
 
+// WriteMany writes several tags using ADS SumUp requests. A tag written more
+// than once is written again in a later request, keeping the given order.
+func (a *ADSAdapter) WriteMany(writes []TagWrite) ([]error, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	return writeManyByName(writes, a.client.WriteMany)
+}
+
+// ReadContext is like Read but aborts in-flight requests when ctx ends.
+func (a *ADSAdapter) ReadContext(ctx context.Context, requests []TagRequest) ([]*TagValue, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var values []*TagValue
+	err := withContext(ctx, a.client, func() (err error) {
+		values, err = a.Read(requests)
+		return err
+	})
+	return values, err
+}
+
+// WriteContext is like Write but aborts the in-flight request when ctx ends.
+func (a *ADSAdapter) WriteContext(ctx context.Context, tag string, value interface{}) error {
+	if a.client == nil {
+		return fmt.Errorf("not connected")
+	}
+	return withContext(ctx, a.client, func() error {
+		return a.Write(tag, value)
+	})
+}
+
+// WriteManyContext is like WriteMany but aborts in-flight requests when ctx ends.
+func (a *ADSAdapter) WriteManyContext(ctx context.Context, writes []TagWrite) ([]error, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var errs []error
+	err := withContext(ctx, a.client, func() (err error) {
+		errs, err = a.WriteMany(writes)
+		return err
+	})
+	return errs, err
+}
+
+// AllTagsContext is like AllTags but aborts in-flight requests when ctx ends.
+func (a *ADSAdapter) AllTagsContext(ctx context.Context) ([]TagInfo, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var tags []TagInfo
+	err := withContext(ctx, a.client, func() (err error) {
+		tags, err = a.AllTags()
+		return err
+	})
+	return tags, err
+}
+
 // Keepalive is a no-op for ADS (TCP keepalive handles connection maintenance).
 func (a *ADSAdapter) Keepalive() error {
 	return nil
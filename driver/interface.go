@@ -1,5 +1,6 @@
 package driver
 
+import "context"
 
 // Driver is the unified interface for all PLC communications.
 // Each PLC family has an adapter that implements this interface.
@@ -27,3 +28,29 @@ type Driver interface {
 	Keepalive() error
 	IsConnectionError(err error) bool
 }
+
+// ContextDriver is implemented by drivers whose blocking operations can be
+// cancelled. Cancellation and deadlines are propagated into the transport's
+// socket deadlines, so a stuck fragmented read or protocol handshake returns
+// as soon as ctx ends rather than after PLCConfig.Timeout. If cancellation
+// interrupts an exchange the connection is dropped, IsConnectionError reports
+// true for the returned error, and the caller should reconnect.
+//
+// All adapters returned by Create implement ContextDriver.
+type ContextDriver interface {
+	Driver
+
+	ConnectContext(ctx context.Context) error
+	ReadContext(ctx context.Context, requests []TagRequest) ([]*TagValue, error)
+	WriteContext(ctx context.Context, tag string, value interface{}) error
+	AllTagsContext(ctx context.Context) ([]TagInfo, error)
+}
+
+// BatchWriter is implemented by drivers that can write several tags in fewer
+// round trips than one Write per tag. The returned slice holds one error per
+// write in request order (nil on success); the error return is non-nil only
+// when the batch as a whole failed, e.g. on connection loss.
+type BatchWriter interface {
+	WriteMany(writes []TagWrite) ([]error, error)
+	WriteManyContext(ctx context.Context, writes []TagWrite) ([]error, error)
+}
@@ -1,7 +1,9 @@
 package driver
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/yatesdr/plcio/cip"
 	"github.com/yatesdr/plcio/logix"
@@ -28,6 +30,12 @@ func NewLogixAdapter(cfg *PLCConfig) (*LogixAdapter, error) {
 
 // Connect establishes connection to the Logix PLC.
 func (a *LogixAdapter) Connect() error {
+	return a.ConnectContext(context.Background())
+}
+
+// ConnectContext establishes connection to the Logix PLC, aborting the dial and
+// handshake if ctx is cancelled or its deadline passes.
+func (a *LogixAdapter) ConnectContext(ctx context.Context) error {
 	opts := []logix.Option{}
 
 	if a.config.Timeout > 0 {
@@ -46,7 +54,7 @@ func (a *LogixAdapter) Connect() error {
 		opts = append(opts, logix.WithSlot(a.config.Slot))
 	}
 
-	client, err := logix.Connect(a.config.Address, opts...)
+	client, err := logix.ConnectContext(ctx, a.config.Address, opts...)
 	if err != nil {
 		return fmt.Errorf("logix connect: %w", err)
 	}
@@ -96,14 +104,36 @@ func (a *LogixAdapter) GetDeviceInfo() (*DeviceInfo, error) {
 		return nil, err
 	}
 
-	return &DeviceInfo{
+	devInfo := &DeviceInfo{
 		Family:       a.Family(),
 		Vendor:       identity.VendorName(),
 		Model:        identity.ProductName,
 		Version:      identity.Revision,
 		SerialNumber: fmt.Sprintf("%08X", identity.Serial),
 		Description:  identity.DeviceTypeName(),
-	}, nil
+	}
+
+	// Mode, faults and clock come from the CPU itself; identity may be the
+	// Ethernet module's in a ControlLogix chassis.
+	if status, err := a.client.ControllerStatus(); err == nil {
+		devInfo.RunState = status.RunState()
+		devInfo.KeySwitch = status.Keyswitch.String()
+		devInfo.MajorFault = status.MajorFault()
+		devInfo.MinorFault = status.MinorFault()
+		devInfo.Clock = status.Clock
+		devInfo.ClockDrift = status.ClockDrift()
+	}
+
+	return devInfo, nil
+}
+
+// ControllerStatus returns the controller's mode, keyswitch position, fault
+// flags and wall clock.
+func (a *LogixAdapter) ControllerStatus() (*logix.ControllerStatus, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	return a.client.ControllerStatus()
 }
 
 // SupportsDiscovery returns true since Logix PLCs support tag browsing.
@@ -207,6 +237,66 @@ func (a *LogixAdapter) Write(tag string, value interface{}) error {
 	return a.client.Write(tag, value)
 }
 
+// WriteMany writes several tags using CIP Multiple Service Packets. A tag
+// written more than once is written again in a later request, keeping the
+// given order.
+func (a *LogixAdapter) WriteMany(writes []TagWrite) ([]error, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	return writeManyByName(writes, a.client.WriteMany)
+}
+
+// ReadContext is like Read but aborts in-flight requests when ctx ends.
+func (a *LogixAdapter) ReadContext(ctx context.Context, requests []TagRequest) ([]*TagValue, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var values []*TagValue
+	err := withContext(ctx, a.client, func() (err error) {
+		values, err = a.Read(requests)
+		return err
+	})
+	return values, err
+}
+
+// WriteContext is like Write but aborts the in-flight request when ctx ends.
+func (a *LogixAdapter) WriteContext(ctx context.Context, tag string, value interface{}) error {
+	if a.client == nil {
+		return fmt.Errorf("not connected")
+	}
+	return withContext(ctx, a.client, func() error {
+		return a.Write(tag, value)
+	})
+}
+
+// WriteManyContext is like WriteMany but aborts in-flight requests when ctx ends.
+func (a *LogixAdapter) WriteManyContext(ctx context.Context, writes []TagWrite) ([]error, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var errs []error
+	err := withContext(ctx, a.client, func() (err error) {
+		errs, err = a.WriteMany(writes)
+		return err
+	})
+	return errs, err
+}
+
+// AllTagsContext is like AllTags but aborts in-flight requests when ctx ends.
+func (a *LogixAdapter) AllTagsContext(ctx context.Context) ([]TagInfo, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var tags []TagInfo
+	err := withContext(ctx, a.client, func() (err error) {
+		tags, err = a.AllTags()
+		return err
+	})
+	return tags, err
+}
+
 // Keepalive sends a keepalive message to maintain the connection.
 func (a *LogixAdapter) Keepalive() error {
 	if a.client == nil {
@@ -215,6 +305,18 @@ func (a *LogixAdapter) Keepalive() error {
 	return a.client.Keepalive()
 }
 
+// WatchChanges calls fn when a download or online edit changes the
+// controller's tags or data types, after the client has refreshed its tag
+// list and dropped its template cache. Checks run at most once per interval
+// during Keepalive calls; an interval of 0 stops watching. The
+// watch belongs to the connection, so set it again after reconnecting.
+func (a *LogixAdapter) WatchChanges(interval time.Duration, fn func(*logix.ChangeEvent)) error {
+	if a.client == nil {
+		return fmt.Errorf("not connected")
+	}
+	return a.client.WatchChanges(interval, fn)
+}
+
 // IsConnectionError returns true if the error indicates a connection problem.
 func (a *LogixAdapter) IsConnectionError(err error) bool {
 	return IsLikelyConnectionError(err)
@@ -1,6 +1,7 @@
 package driver
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -28,6 +29,12 @@ func NewOmronAdapter(cfg *PLCConfig) (*OmronAdapter, error) {
 
 // Connect establishes connection to the Omron PLC.
 func (a *OmronAdapter) Connect() error {
+	return a.ConnectContext(context.Background())
+}
+
+// ConnectContext establishes connection to the Omron PLC, aborting the dial and
+// handshake if ctx is cancelled or its deadline passes.
+func (a *OmronAdapter) ConnectContext(ctx context.Context) error {
 	opts := []omron.Option{}
 
 	protocol := a.protocol
@@ -55,7 +62,7 @@ func (a *OmronAdapter) Connect() error {
 		opts = append(opts, omron.WithUnit(a.config.FinsUnit))
 	}
 
-	client, err := omron.Connect(a.config.Address, opts...)
+	client, err := omron.ConnectContext(ctx, a.config.Address, opts...)
 	if err != nil {
 		return fmt.Errorf("omron connect: %w", err)
 	}
@@ -225,6 +232,42 @@ func (a *OmronAdapter) Write(tag string, value interface{}) error {
 	return a.client.WriteWithType(tag, value, typeHint)
 }
 
+// ReadContext is like Read but aborts in-flight requests when ctx ends.
+func (a *OmronAdapter) ReadContext(ctx context.Context, requests []TagRequest) ([]*TagValue, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var values []*TagValue
+	err := withContext(ctx, a.client, func() (err error) {
+		values, err = a.Read(requests)
+		return err
+	})
+	return values, err
+}
+
+// WriteContext is like Write but aborts the in-flight request when ctx ends.
+func (a *OmronAdapter) WriteContext(ctx context.Context, tag string, value interface{}) error {
+	if a.client == nil {
+		return fmt.Errorf("not connected")
+	}
+	return withContext(ctx, a.client, func() error {
+		return a.Write(tag, value)
+	})
+}
+
+// AllTagsContext is like AllTags but aborts in-flight requests when ctx ends.
+func (a *OmronAdapter) AllTagsContext(ctx context.Context) ([]TagInfo, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var tags []TagInfo
+	err := withContext(ctx, a.client, func() (err error) {
+		tags, err = a.AllTags()
+		return err
+	})
+	return tags, err
+}
+
 // Keepalive sends a keepalive to maintain the CIP connection.
 func (a *OmronAdapter) Keepalive() error {
 	if a.client != nil {
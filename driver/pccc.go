@@ -1,6 +1,7 @@
 package driver
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -26,6 +27,12 @@ func NewPCCCAdapter(cfg *PLCConfig) (*PCCCAdapter, error) {
 
 // Connect establishes connection to the SLC500/PLC-5/MicroLogix PLC.
 func (a *PCCCAdapter) Connect() error {
+	return a.ConnectContext(context.Background())
+}
+
+// ConnectContext establishes connection to the SLC500/PLC-5/MicroLogix PLC, aborting the dial and
+// handshake if ctx is cancelled or its deadline passes.
+func (a *PCCCAdapter) ConnectContext(ctx context.Context) error {
 	opts := []pccc.Option{}
 
 	if a.config.Timeout > 0 {
@@ -47,7 +54,7 @@ func (a *PCCCAdapter) Connect() error {
 		opts = append(opts, pccc.WithMicroLogix())
 	}
 
-	client, err := pccc.Connect(a.config.Address, opts...)
+	client, err := pccc.ConnectContext(ctx, a.config.Address, opts...)
 	if err != nil {
 		return fmt.Errorf("pccc connect: %w", err)
 	}
@@ -365,6 +372,42 @@ func (a *PCCCAdapter) Write(tag string, value interface{}) error {
 	return a.client.Write(tag, value)
 }
 
+// ReadContext is like Read but aborts in-flight requests when ctx ends.
+func (a *PCCCAdapter) ReadContext(ctx context.Context, requests []TagRequest) ([]*TagValue, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var values []*TagValue
+	err := withContext(ctx, a.client, func() (err error) {
+		values, err = a.Read(requests)
+		return err
+	})
+	return values, err
+}
+
+// WriteContext is like Write but aborts the in-flight request when ctx ends.
+func (a *PCCCAdapter) WriteContext(ctx context.Context, tag string, value interface{}) error {
+	if a.client == nil {
+		return fmt.Errorf("not connected")
+	}
+	return withContext(ctx, a.client, func() error {
+		return a.Write(tag, value)
+	})
+}
+
+// AllTagsContext is like AllTags but aborts in-flight requests when ctx ends.
+func (a *PCCCAdapter) AllTagsContext(ctx context.Context) ([]TagInfo, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var tags []TagInfo
+	err := withContext(ctx, a.client, func() (err error) {
+		tags, err = a.AllTags()
+		return err
+	})
+	return tags, err
+}
+
 // Keepalive sends a NOP to maintain the connection.
 func (a *PCCCAdapter) Keepalive() error {
 	if a.client == nil {
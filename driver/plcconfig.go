@@ -61,12 +61,37 @@ type PLCConfig struct {
 	Timeout            time.Duration  `yaml:"timeout,omitempty"`
 	Tags               []TagSelection `yaml:"tags,omitempty"`
 
+	// AllowControl permits run-state changes (start/stop) on families that
+	// support them. Off by default.
+	AllowControl bool `yaml:"allow_control,omitempty"`
+
 	// Logix/CIP-specific settings
 	ConnectionPath string `yaml:"connection_path,omitempty"` // Rockwell-style route, e.g. "1,0" or "1,1,2,192.168.100.1"
 
 	// Beckhoff/TwinCAT-specific settings
-	AmsNetId string `yaml:"ams_net_id,omitempty"`
-	AmsPort  uint16 `yaml:"ams_port,omitempty"`
+	AmsNetId       string `yaml:"ams_net_id,omitempty"`
+	AmsPort        uint16 `yaml:"ams_port,omitempty"`
+	AmsSourceNetId string `yaml:"ams_source_net_id,omitempty"` // Local AMS Net ID (default: local IP + ".1.1")
+
+	// Optional route registration on connect (UDP 48899 add-route), using the
+	// target's OS credentials. Leave empty if a route is already configured.
+	AmsRouteUsername string `yaml:"ams_route_username,omitempty"`
+	AmsRoutePassword string `yaml:"ams_route_password,omitempty"`
+
+	// Siemens S7-specific settings: sub-model for PLCs that need their own
+	// connection parameters, and explicit COTP TSAPs overriding rack/slot
+	S7Model S7Model `yaml:"s7_model,omitempty"`
+	S7TSAP  *S7TSAP `yaml:"s7_tsap,omitempty"`
+	// DB source exports that provide symbolic names (e.g. "DB_Motor.Speed")
+	// for S7 addresses
+	S7DBSources []S7DBSource `yaml:"s7_db_sources,omitempty"`
+	// Max unused bytes between S7 addresses merged into one read (0 = adjacent
+	// only, negative disables merging)
+	S7ReadGap int `yaml:"s7_read_gap,omitempty"`
+	// Also open an S7CommPlus session to browse and read optimized DBs by
+	// symbolic access. Only S7-1200 firmware before V4 supports the protocol
+	// version implemented; see S7Adapter.SymbolicError on other CPUs
+	S7Symbolic bool `yaml:"s7_symbolic,omitempty"`
 
 	// Omron-specific settings
 	Protocol    string `yaml:"protocol,omitempty"`
@@ -76,6 +101,29 @@ type PLCConfig struct {
 	FinsUnit    byte   `yaml:"fins_unit,omitempty"`
 }
 
+// S7Model selects a Siemens sub-model within FamilyS7.
+type S7Model string
+
+const (
+	S7ModelStandard S7Model = ""            // S7-300/400/1200/1500, addressed by rack and slot
+	S7ModelLOGO     S7Model = "logo"        // LOGO! 0BA7/0BA8 and later
+	S7Model200Smart S7Model = "s7-200smart" // S7-200 Smart
+)
+
+// S7TSAP is an explicit pair of COTP TSAPs, e.g. {Local: 0x0100, Remote:
+// 0x0200} for a LOGO! server connection with TSAP 02.00.
+type S7TSAP struct {
+	Local  uint16 `yaml:"local"`
+	Remote uint16 `yaml:"remote"`
+}
+
+// S7DBSource is a STEP 7 / TIA Portal data block source export (.db, .scl
+// or .awl).
+type S7DBSource struct {
+	File   string `yaml:"file"`
+	Number int    `yaml:"number,omitempty"` // DB number, for a source that names its block symbolically
+}
+
 // GetFamily returns the PLC family, defaulting to logix if not set.
 func (p *PLCConfig) GetFamily() PLCFamily {
 	if p.Family == "" {
@@ -145,16 +193,17 @@ func (p *PLCConfig) IsHealthCheckEnabled() bool {
 
 // TagSelection represents a tag selected for monitoring/republishing.
 type TagSelection struct {
-	Name          string   `yaml:"name"`
-	Alias         string   `yaml:"alias,omitempty"`
-	DataType      string   `yaml:"data_type,omitempty"`
-	Enabled       bool     `yaml:"enabled"`
-	Writable      bool     `yaml:"writable,omitempty"`
-	IgnoreChanges []string `yaml:"ignore_changes,omitempty"`
-	NoREST        bool     `yaml:"no_rest,omitempty"`
-	NoMQTT        bool     `yaml:"no_mqtt,omitempty"`
-	NoKafka       bool     `yaml:"no_kafka,omitempty"`
-	NoValkey      bool     `yaml:"no_valkey,omitempty"`
+	Name          string        `yaml:"name"`
+	Alias         string        `yaml:"alias,omitempty"`
+	DataType      string        `yaml:"data_type,omitempty"`
+	Enabled       bool          `yaml:"enabled"`
+	Writable      bool          `yaml:"writable,omitempty"`
+	IgnoreChanges []string      `yaml:"ignore_changes,omitempty"`
+	PollRate      time.Duration `yaml:"poll_rate,omitempty"` // Overrides PLCConfig.PollRate when non-zero
+	NoREST        bool          `yaml:"no_rest,omitempty"`
+	NoMQTT        bool          `yaml:"no_mqtt,omitempty"`
+	NoKafka       bool          `yaml:"no_kafka,omitempty"`
+	NoValkey      bool          `yaml:"no_valkey,omitempty"`
 }
 
 // PublishesToAny returns true if the tag publishes to at least one service.
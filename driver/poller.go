@@ -0,0 +1,364 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// DefaultPollRate is used when neither the tag nor the PLC sets a poll rate.
+const DefaultPollRate = time.Second
+
+// Change is delivered by a Poller when a tag's value or error state changes.
+type Change struct {
+	PLC      string       // PLCConfig.Name
+	Tag      TagSelection // Selection that produced the value
+	Value    *TagValue    // Current value (Value.Error set if the read failed)
+	Previous *TagValue    // Last delivered value, nil on the first read
+	Time     time.Time    // When the read completed
+}
+
+// PollerOption configures a Poller.
+type PollerOption func(*Poller)
+
+// WithPollerDriver uses an existing driver instead of creating one with Create.
+// The Poller takes ownership of the driver and closes it when Run returns.
+func WithPollerDriver(drv Driver) PollerOption {
+	return func(p *Poller) {
+		p.driver = drv
+	}
+}
+
+// WithPollerBackoff sets the reconnect delay bounds. The delay starts at min
+// and doubles after each failed attempt up to max.
+func WithPollerBackoff(min, max time.Duration) PollerOption {
+	return func(p *Poller) {
+		p.minBackoff = min
+		p.maxBackoff = max
+	}
+}
+
+// WithPollerBuffer sets the capacity of the changes channel.
+func WithPollerBuffer(n int) PollerOption {
+	return func(p *Poller) {
+		p.buffer = n
+	}
+}
+
+// WithConnectionHandler registers a callback invoked when the connection is
+// established, lost, or a reconnect attempt fails. It runs on the poll
+// goroutine and must not block.
+func WithConnectionHandler(fn func(connected bool, err error)) PollerOption {
+	return func(p *Poller) {
+		p.onConnection = fn
+	}
+}
+
+// Poller polls the enabled tags of a PLCConfig and delivers changes on a
+// channel. Tags are grouped by poll rate and each group is read as a single
+// batch. A change is reported when a tag's StableValue (its value with the
+// TagSelection's IgnoreChanges members removed) differs from the last one
+// delivered, or when its error state changes.
+//
+// Connection errors close the driver and trigger reconnects with exponential
+// backoff. Last delivered values survive a reconnect, so only real changes
+// are reported once polling resumes.
+type Poller struct {
+	config       *PLCConfig
+	driver       Driver
+	groups       []*pollGroup
+	changes      chan Change
+	buffer       int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	onConnection func(connected bool, err error)
+
+	connected bool
+	last      map[string]*TagValue
+}
+
+// pollGroup is a batch of tags sharing a poll rate.
+type pollGroup struct {
+	rate     time.Duration
+	tags     []TagSelection
+	requests []TagRequest
+	next     time.Time
+}
+
+// NewPoller creates a Poller for the enabled tags in cfg.
+// Polling does not start until Run is called.
+func NewPoller(cfg *PLCConfig, opts ...PollerOption) (*Poller, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("nil config")
+	}
+
+	p := &Poller{
+		config:     cfg,
+		buffer:     64,
+		minBackoff: time.Second,
+		maxBackoff: 30 * time.Second,
+		last:       make(map[string]*TagValue),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.minBackoff <= 0 {
+		p.minBackoff = time.Second
+	}
+	if p.maxBackoff < p.minBackoff {
+		p.maxBackoff = p.minBackoff
+	}
+
+	if p.driver == nil {
+		drv, err := Create(cfg)
+		if err != nil {
+			return nil, err
+		}
+		p.driver = drv
+	}
+
+	p.groups = groupByRate(cfg)
+	if len(p.groups) == 0 {
+		return nil, fmt.Errorf("no enabled tags")
+	}
+
+	p.changes = make(chan Change, p.buffer)
+	return p, nil
+}
+
+// groupByRate batches the enabled tags of cfg by effective poll rate,
+// fastest first.
+func groupByRate(cfg *PLCConfig) []*pollGroup {
+	defaultRate := cfg.PollRate
+	if defaultRate <= 0 {
+		defaultRate = DefaultPollRate
+	}
+
+	byRate := make(map[time.Duration]*pollGroup)
+	var groups []*pollGroup
+	for _, sel := range cfg.Tags {
+		if !sel.Enabled || sel.Name == "" {
+			continue
+		}
+		rate := sel.PollRate
+		if rate <= 0 {
+			rate = defaultRate
+		}
+		g, ok := byRate[rate]
+		if !ok {
+			g = &pollGroup{rate: rate}
+			byRate[rate] = g
+			groups = append(groups, g)
+		}
+		g.tags = append(g.tags, sel)
+		g.requests = append(g.requests, TagRequest{Name: sel.Name, TypeHint: sel.DataType})
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].rate < groups[j].rate })
+	return groups
+}
+
+// Changes returns the channel on which changes are delivered.
+// The channel is closed when Run returns.
+func (p *Poller) Changes() <-chan Change {
+	return p.changes
+}
+
+// Driver returns the driver used by the Poller.
+func (p *Poller) Driver() Driver {
+	return p.driver
+}
+
+// Run connects and polls until ctx is done, then closes the driver and the
+// changes channel and returns ctx.Err(). Run must only be called once.
+func (p *Poller) Run(ctx context.Context) error {
+	defer close(p.changes)
+	defer p.disconnect(nil)
+
+	for {
+		if !p.connected {
+			if err := p.connect(ctx); err != nil {
+				return err
+			}
+			now := time.Now()
+			for _, g := range p.groups {
+				g.next = now
+			}
+		}
+
+		g := p.nextDue()
+		if wait := time.Until(g.next); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		// Skip ticks missed while a slow read was in progress rather than
+		// firing them back to back.
+		g.next = g.next.Add(g.rate)
+		if now := time.Now(); g.next.Before(now) {
+			g.next = now.Add(g.rate)
+		}
+
+		if err := p.poll(ctx, g); err != nil {
+			return err
+		}
+	}
+}
+
+// nextDue returns the group with the earliest scheduled read.
+func (p *Poller) nextDue() *pollGroup {
+	due := p.groups[0]
+	for _, g := range p.groups[1:] {
+		if g.next.Before(due.next) {
+			due = g
+		}
+	}
+	return due
+}
+
+// connect retries until the driver connects or ctx is done.
+func (p *Poller) connect(ctx context.Context) error {
+	backoff := p.minBackoff
+	for {
+		var err error
+		if cd, ok := p.driver.(ContextDriver); ok {
+			err = cd.ConnectContext(ctx)
+		} else {
+			err = p.driver.Connect()
+		}
+		if err == nil {
+			p.connected = true
+			logging.DebugLog("poller", "%s: connected", p.config.Name)
+			p.notify(true, nil)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		logging.DebugLog("poller", "%s: connect failed, retrying in %v: %v", p.config.Name, backoff, err)
+		p.notify(false, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > p.maxBackoff {
+			backoff = p.maxBackoff
+		}
+	}
+}
+
+// disconnect closes the driver, reporting err if the connection was up.
+func (p *Poller) disconnect(err error) {
+	p.driver.Close()
+	if !p.connected {
+		return
+	}
+	p.connected = false
+	if err != nil {
+		logging.DebugLog("poller", "%s: connection lost: %v", p.config.Name, err)
+		p.notify(false, err)
+	}
+}
+
+func (p *Poller) notify(connected bool, err error) {
+	if p.onConnection != nil {
+		p.onConnection(connected, err)
+	}
+}
+
+// poll reads one group and delivers any changes. It only returns an error
+// when ctx is done.
+func (p *Poller) poll(ctx context.Context, g *pollGroup) error {
+	var values []*TagValue
+	var err error
+	if cd, ok := p.driver.(ContextDriver); ok {
+		values, err = cd.ReadContext(ctx, g.requests)
+	} else {
+		values, err = p.driver.Read(g.requests)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err == nil {
+		err = p.allFailed(values)
+	}
+	if err != nil && p.driver.IsConnectionError(err) {
+		p.disconnect(err)
+		return nil
+	}
+
+	now := time.Now()
+	for i, sel := range g.tags {
+		var v *TagValue
+		if err == nil && i < len(values) {
+			v = values[i]
+		}
+		if v == nil {
+			readErr := err
+			if readErr == nil {
+				readErr = fmt.Errorf("no value returned")
+			}
+			v = &TagValue{Name: sel.Name, Family: p.config.GetFamily().Driver(), Error: readErr}
+		} else if v.Error == nil {
+			v.SetIgnoreList(sel.IgnoreChanges)
+		}
+
+		prev := p.last[sel.Name]
+		if !valueChanged(prev, v) {
+			continue
+		}
+		p.last[sel.Name] = v
+
+		select {
+		case p.changes <- Change{PLC: p.config.Name, Tag: sel, Value: v, Previous: prev, Time: now}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// allFailed returns the shared error when every value in a batch failed with
+// a connection error, which some drivers report per tag instead of for the
+// whole read.
+func (p *Poller) allFailed(values []*TagValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+	for _, v := range values {
+		if v == nil || v.Error == nil || !p.driver.IsConnectionError(v.Error) {
+			return nil
+		}
+	}
+	return values[0].Error
+}
+
+// valueChanged reports whether cur should be delivered given the last
+// delivered value prev.
+func valueChanged(prev, cur *TagValue) bool {
+	if prev == nil {
+		return true
+	}
+	if (prev.Error != nil) != (cur.Error != nil) {
+		return true
+	}
+	if cur.Error != nil {
+		return prev.Error.Error() != cur.Error.Error()
+	}
+	return !reflect.DeepEqual(prev.StableValue, cur.StableValue)
+}
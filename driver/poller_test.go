@@ -0,0 +1,137 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDriver serves reads from a script of per-call results.
+type fakeDriver struct {
+	mu       sync.Mutex
+	reads    []func(reqs []TagRequest) ([]*TagValue, error)
+	connects int
+}
+
+func (f *fakeDriver) Connect() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connects++
+	return nil
+}
+func (f *fakeDriver) Close() error                        { return nil }
+func (f *fakeDriver) IsConnected() bool                   { return true }
+func (f *fakeDriver) Family() PLCFamily                   { return FamilyLogix }
+func (f *fakeDriver) ConnectionMode() string              { return "fake" }
+func (f *fakeDriver) GetDeviceInfo() (*DeviceInfo, error) { return &DeviceInfo{}, nil }
+func (f *fakeDriver) SupportsDiscovery() bool             { return false }
+func (f *fakeDriver) AllTags() ([]TagInfo, error)         { return nil, nil }
+func (f *fakeDriver) Programs() ([]string, error)         { return nil, nil }
+func (f *fakeDriver) Write(string, interface{}) error     { return nil }
+func (f *fakeDriver) Keepalive() error                    { return nil }
+func (f *fakeDriver) IsConnectionError(err error) bool    { return IsLikelyConnectionError(err) }
+
+func (f *fakeDriver) Read(reqs []TagRequest) ([]*TagValue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.reads) == 0 {
+		return nil, io.EOF
+	}
+	next := f.reads[0]
+	if len(f.reads) > 1 {
+		f.reads = f.reads[1:]
+	}
+	return next(reqs)
+}
+
+func readValue(v interface{}) func([]TagRequest) ([]*TagValue, error) {
+	return func(reqs []TagRequest) ([]*TagValue, error) {
+		return []*TagValue{{Name: reqs[0].Name, Value: v, StableValue: v}}, nil
+	}
+}
+
+func TestGroupByRate(t *testing.T) {
+	cfg := &PLCConfig{
+		PollRate: 500 * time.Millisecond,
+		Tags: []TagSelection{
+			{Name: "A", Enabled: true},
+			{Name: "B", Enabled: true, PollRate: 100 * time.Millisecond},
+			{Name: "C", Enabled: false},
+			{Name: "D", Enabled: true, DataType: "INT"},
+		},
+	}
+	groups := groupByRate(cfg)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].rate != 100*time.Millisecond || len(groups[0].tags) != 1 || groups[0].tags[0].Name != "B" {
+		t.Errorf("unexpected fast group: %+v", groups[0])
+	}
+	if groups[1].rate != 500*time.Millisecond || len(groups[1].requests) != 2 {
+		t.Fatalf("unexpected default group: %+v", groups[1])
+	}
+	if groups[1].requests[1] != (TagRequest{Name: "D", TypeHint: "INT"}) {
+		t.Errorf("unexpected request: %+v", groups[1].requests[1])
+	}
+}
+
+func TestPollerDeliversChangesAndReconnects(t *testing.T) {
+	udt := func(count int32, ts int64) map[string]interface{} {
+		return map[string]interface{}{"Count": count, "Timestamp": ts}
+	}
+	drv := &fakeDriver{reads: []func([]TagRequest) ([]*TagValue, error){
+		readValue(udt(1, 100)),
+		readValue(udt(1, 200)), // only an ignored member changed
+		func([]TagRequest) ([]*TagValue, error) { return nil, io.EOF },
+		readValue(udt(2, 300)),
+	}}
+	cfg := &PLCConfig{
+		Name:     "line1",
+		PollRate: time.Millisecond,
+		Tags:     []TagSelection{{Name: "Status", Enabled: true, IgnoreChanges: []string{"Timestamp"}}},
+	}
+
+	var events []bool
+	p, err := NewPoller(cfg,
+		WithPollerDriver(drv),
+		WithPollerBackoff(time.Millisecond, time.Millisecond),
+		WithConnectionHandler(func(connected bool, err error) { events = append(events, connected) }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	first := <-p.Changes()
+	if first.Previous != nil || first.PLC != "line1" {
+		t.Fatalf("unexpected first change: %+v", first)
+	}
+	second := <-p.Changes()
+	if got := second.Value.Value.(map[string]interface{})["Count"]; got != int32(2) {
+		t.Fatalf("expected Count 2 after reconnect, got %v", got)
+	}
+	if second.Previous != first.Value {
+		t.Errorf("expected Previous to be the last delivered value")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled from Run, got %v", err)
+	}
+	if _, ok := <-p.Changes(); ok {
+		t.Errorf("expected changes channel to be closed")
+	}
+	if drv.connects != 2 {
+		t.Errorf("expected 2 connects, got %d", drv.connects)
+	}
+	if len(events) < 3 || !events[0] || events[1] || !events[2] {
+		t.Errorf("unexpected connection events: %v", events)
+	}
+}
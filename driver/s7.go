@@ -1,16 +1,25 @@
 package driver
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/yatesdr/plcio/logging"
 	"github.com/yatesdr/plcio/s7"
+	"github.com/yatesdr/plcio/s7plus"
 )
 
 // S7Adapter wraps s7.Client to implement the Driver interface.
 type S7Adapter struct {
 	client *s7.Client
 	config *PLCConfig
+	layout *s7.Layout // Symbolic DB layout from config.S7DBSources; nil if none
+
+	// S7CommPlus session for optimized DBs when config.S7Symbolic is set;
+	// nil if disabled or the PLC does not support it
+	plus    *s7plus.Client
+	plusErr error // Why plus is nil although config.S7Symbolic is set
 }
 
 // NewS7Adapter creates a new S7Adapter from configuration.
@@ -19,33 +28,131 @@ func NewS7Adapter(cfg *PLCConfig) (*S7Adapter, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("nil config")
 	}
-	return &S7Adapter{
+	a := &S7Adapter{
 		config: cfg,
-	}, nil
+	}
+	switch cfg.S7Model {
+	case S7ModelStandard, S7ModelLOGO, S7Model200Smart:
+	default:
+		return nil, fmt.Errorf("unknown s7 model %q", cfg.S7Model)
+	}
+	if len(cfg.S7DBSources) > 0 {
+		layout, err := loadS7Layout(cfg.S7DBSources)
+		if err != nil {
+			return nil, fmt.Errorf("s7 layout: %w", err)
+		}
+		a.layout = layout
+	}
+	return a, nil
+}
+
+// loadS7Layout loads DB sources into a layout, assigning configured DB
+// numbers to blocks the sources name symbolically.
+func loadS7Layout(sources []S7DBSource) (*s7.Layout, error) {
+	layout := s7.NewLayout()
+	for _, src := range sources {
+		before := len(layout.Blocks())
+		if err := layout.LoadFile(src.File); err != nil {
+			return nil, err
+		}
+		if src.Number == 0 {
+			continue
+		}
+		added := layout.Blocks()[before:]
+		if len(added) != 1 {
+			return nil, fmt.Errorf("%s: number %d given but the source defines %d data blocks", src.File, src.Number, len(added))
+		}
+		if err := layout.SetNumber(added[0].Name, src.Number); err != nil {
+			return nil, fmt.Errorf("%s: %w", src.File, err)
+		}
+	}
+	return layout, nil
 }
 
 // Connect establishes connection to the S7 PLC.
 func (a *S7Adapter) Connect() error {
+	return a.ConnectContext(context.Background())
+}
+
+// ConnectContext establishes connection to the S7 PLC, aborting the dial and
+// handshake if ctx is cancelled or its deadline passes.
+func (a *S7Adapter) ConnectContext(ctx context.Context) error {
 	opts := []s7.Option{s7.WithRackSlot(0, int(a.config.Slot))}
+	if local, remote, ok := a.tsap(); ok {
+		opts = append(opts, s7.WithTSAP(local, remote))
+	}
 	if a.config.Timeout > 0 {
 		opts = append(opts, s7.WithTimeout(a.config.Timeout))
 	}
+	if a.config.AllowControl {
+		opts = append(opts, s7.WithAllowControl())
+	}
+	if a.config.S7ReadGap != 0 {
+		opts = append(opts, s7.WithReadGap(a.config.S7ReadGap))
+	}
+	if a.layout != nil {
+		opts = append(opts, s7.WithLayout(a.layout))
+	}
 
-	client, err := s7.Connect(a.config.Address, opts...)
+	client, err := s7.ConnectContext(ctx, a.config.Address, opts...)
 	if err != nil {
 		return fmt.Errorf("s7 connect: %w", err)
 	}
 
 	a.client = client
+
+	// Symbolic access is optional: without it, classic addressing still works.
+	// The reason it is off is kept for SymbolicError, ConnectionMode and reads.
+	a.plusErr = nil
+	if a.config.S7Symbolic {
+		var plusOpts []s7plus.Option
+		if a.config.Timeout > 0 {
+			plusOpts = append(plusOpts, s7plus.WithTimeout(a.config.Timeout))
+		}
+		plus, err := s7plus.ConnectContext(ctx, a.config.Address, plusOpts...)
+		if err != nil {
+			logging.DebugConnectError("S7Plus", a.config.Address, err)
+			a.plusErr = err
+		} else {
+			a.plus = plus
+		}
+	}
 	return nil
 }
 
+// SymbolicError returns why S7CommPlus symbolic access is off although
+// config.S7Symbolic is set, e.g. s7plus.ErrUnsupportedProtocol for an
+// S7-1500 or an S7-1200 with firmware V4 or later. It returns nil when the
+// session is open or symbolic access was not requested.
+func (a *S7Adapter) SymbolicError() error {
+	return a.plusErr
+}
+
+// tsap returns the TSAPs to connect with: the configured pair, else the
+// default of the sub-model. ok is false for models addressed by rack/slot.
+func (a *S7Adapter) tsap() (local, remote uint16, ok bool) {
+	if t := a.config.S7TSAP; t != nil {
+		return t.Local, t.Remote, true
+	}
+	switch a.config.S7Model {
+	case S7ModelLOGO:
+		return 0x0100, 0x0200, true
+	case S7Model200Smart:
+		return 0x0100, 0x0101, true
+	}
+	return 0, 0, false
+}
+
 // Close releases the connection.
 func (a *S7Adapter) Close() error {
 	if a.client != nil {
 		a.client.Close()
 		a.client = nil
 	}
+	if a.plus != nil {
+		a.plus.Close()
+		a.plus = nil
+	}
 	return nil
 }
 
@@ -64,7 +171,14 @@ func (a *S7Adapter) ConnectionMode() string {
 	if a.client == nil {
 		return "Not connected"
 	}
-	return a.client.ConnectionMode()
+	mode := a.client.ConnectionMode()
+	switch {
+	case a.plus != nil:
+		mode += ", S7CommPlus symbolic access"
+	case a.plusErr != nil:
+		mode += fmt.Sprintf(", S7CommPlus symbolic access off: %v", a.plusErr)
+	}
+	return mode
 }
 
 // GetDeviceInfo returns information about the connected PLC.
@@ -75,27 +189,94 @@ func (a *S7Adapter) GetDeviceInfo() (*DeviceInfo, error) {
 
 	info, err := a.client.GetCPUInfo()
 	if err != nil {
+		// LOGO! and S7-200 Smart do not serve the identification SZLs
+		switch a.config.S7Model {
+		case S7ModelLOGO:
+			return &DeviceInfo{Family: FamilyS7, Vendor: "Siemens", Model: "LOGO!"}, nil
+		case S7Model200Smart:
+			return &DeviceInfo{Family: FamilyS7, Vendor: "Siemens", Model: "S7-200 Smart"}, nil
+		}
 		return nil, err
 	}
 
-	return &DeviceInfo{
+	devInfo := &DeviceInfo{
 		Family:       FamilyS7,
 		Vendor:       "Siemens",
 		Model:        info.ModuleTypeName,
-		Version:      info.ASName,
+		Version:      info.FirmwareVersion,
 		SerialNumber: info.SerialNumber,
+		OrderNumber:  info.OrderCode,
 		Description:  info.ModuleName,
-	}, nil
+	}
+
+	if state, err := a.client.GetCPUState(); err == nil {
+		devInfo.RunState = state.String()
+	}
+
+	return devInfo, nil
 }
 
-// SupportsDiscovery returns false since S7 PLCs don't support tag browsing.
+// SupportsDiscovery returns true when DB sources are configured or an
+// S7CommPlus session is open. S7comm has no symbol browsing, so tags come
+// from the loaded DB layout and the S7CommPlus browse.
 func (a *S7Adapter) SupportsDiscovery() bool {
-	return false
+	return a.layout.Len() > 0 || a.plus != nil
 }
 
-// AllTags returns nil since S7 doesn't support tag discovery.
+// AllTags returns the variables of the data blocks loaded from
+// config.S7DBSources, followed by those browsed over S7CommPlus. It returns
+// nil if neither is available.
 func (a *S7Adapter) AllTags() ([]TagInfo, error) {
-	return nil, nil
+	if a.layout == nil && a.plus == nil {
+		return nil, nil
+	}
+
+	var tags []TagInfo
+	var blocks []*s7.DataBlock
+	if a.layout != nil {
+		blocks = a.layout.Blocks()
+	}
+	for _, db := range blocks {
+		if db.Number < 1 {
+			continue // Not addressable without a DB number
+		}
+		for _, f := range db.Fields() {
+			typeCode := f.DataType
+			if f.Count > 1 {
+				typeCode = s7.MakeArrayType(typeCode)
+			}
+			tags = append(tags, TagInfo{
+				Name:       db.Name + "." + f.Name,
+				TypeCode:   typeCode,
+				Dimensions: f.Dimensions,
+				TypeName:   f.TypeName,
+				Writable:   true,
+			})
+		}
+	}
+
+	if a.plus != nil {
+		symbols, err := a.plus.Browse()
+		if err != nil {
+			return tags, fmt.Errorf("s7plus browse: %w", err)
+		}
+		for _, sym := range symbols {
+			typeCode := s7plusTypeCode(sym.Softdatatype)
+			var dims []uint32
+			if sym.Count > 1 {
+				typeCode = s7.MakeArrayType(typeCode)
+				dims = []uint32{uint32(sym.Count)}
+			}
+			tags = append(tags, TagInfo{
+				Name:       sym.Name,
+				TypeCode:   typeCode,
+				Dimensions: dims,
+				TypeName:   sym.TypeName,
+				Writable:   false,
+			})
+		}
+	}
+	return tags, nil
 }
 
 // Programs returns nil since S7 doesn't have the concept of programs.
@@ -109,22 +290,58 @@ func (a *S7Adapter) Read(requests []TagRequest) ([]*TagValue, error) {
 		return nil, fmt.Errorf("not connected")
 	}
 
-	// Convert to s7.TagRequest
-	s7Requests := make([]s7.TagRequest, len(requests))
+	// Names that are not classic addresses go to the S7CommPlus session
+	var classic, symbolic, unavailable []int
 	for i, req := range requests {
-		s7Requests[i] = s7.TagRequest{
-			Address:  req.Name,
-			TypeHint: req.TypeHint,
+		switch {
+		case a.isSymbolic(req.Name):
+			symbolic = append(symbolic, i)
+		case a.plusErr != nil && a.notAddress(req.Name):
+			unavailable = append(unavailable, i)
+		default:
+			classic = append(classic, i)
 		}
 	}
 
-	values, err := a.client.ReadWithTypes(s7Requests)
-	if err != nil {
-		return nil, err
+	// Convert to s7.TagRequest
+	s7Requests := make([]s7.TagRequest, len(classic))
+	for n, i := range classic {
+		s7Requests[n] = s7.TagRequest{
+			Address:  requests[i].Name,
+			TypeHint: requests[i].TypeHint,
+		}
+	}
+
+	values := make([]*s7.TagValue, len(requests))
+	if len(classic) > 0 {
+		classicValues, err := a.client.ReadWithTypes(s7Requests)
+		if err != nil {
+			return nil, err
+		}
+		for n, i := range classic {
+			if n < len(classicValues) {
+				values[i] = classicValues[n]
+			}
+		}
 	}
 
 	result := make([]*TagValue, len(values))
+	for _, i := range unavailable {
+		result[i] = &TagValue{
+			Name:   requests[i].Name,
+			Family: "s7",
+			Error:  fmt.Errorf("%s: S7CommPlus symbolic access off: %w", requests[i].Name, a.plusErr),
+		}
+	}
+	if len(symbolic) > 0 {
+		if err := a.readSymbolic(requests, symbolic, result); err != nil {
+			return nil, err
+		}
+	}
 	for i, v := range values {
+		if result[i] != nil {
+			continue // Read over S7CommPlus
+		}
 		if v == nil {
 			result[i] = &TagValue{
 				Name:   requests[i].Name,
@@ -158,24 +375,208 @@ func (a *S7Adapter) Read(requests []TagRequest) ([]*TagValue, error) {
 	return result, nil
 }
 
+// readSymbolic reads requests[i] for each i in indexes over S7CommPlus and
+// stores the results in result.
+func (a *S7Adapter) readSymbolic(requests []TagRequest, indexes []int, result []*TagValue) error {
+	names := make([]string, len(indexes))
+	for n, i := range indexes {
+		names[n] = requests[i].Name
+	}
+
+	values, err := a.plus.Read(names...)
+	if err != nil {
+		return fmt.Errorf("s7plus read: %w", err)
+	}
+
+	for n, i := range indexes {
+		v := values[n]
+		count := 1
+		if sym, ok := a.plus.Lookup(v.Name); ok {
+			count = sym.Count
+		}
+		dataType := s7plusTypeCode(v.Softdatatype)
+		if count > 1 {
+			dataType = s7.MakeArrayType(dataType)
+		}
+		result[i] = &TagValue{
+			Name:        v.Name,
+			DataType:    dataType,
+			Family:      "s7",
+			Value:       v.Value,
+			StableValue: v.Value,
+			Count:       count,
+			Error:       v.Error,
+		}
+	}
+	return nil
+}
+
+// isSymbolic reports whether tag is read over S7CommPlus: an S7CommPlus
+// session is open and the tag is neither a classic address nor a name in
+// the DB layout.
+func (a *S7Adapter) isSymbolic(tag string) bool {
+	return a.plus != nil && a.notAddress(tag)
+}
+
+// notAddress reports whether tag is neither a classic address nor a name in
+// the DB layout.
+func (a *S7Adapter) notAddress(tag string) bool {
+	_, err := a.layout.ParseAddress(tag)
+	return err != nil
+}
+
+// s7plusTypeCode maps an S7CommPlus softdatatype to the s7 type code of the
+// same IEC type, or 0 if there is none.
+func s7plusTypeCode(softdatatype byte) uint16 {
+	switch softdatatype {
+	case s7plus.SoftBool:
+		return s7.TypeBool
+	case s7plus.SoftByte, s7plus.SoftUSInt:
+		return s7.TypeByte
+	case s7plus.SoftChar:
+		return s7.TypeChar
+	case s7plus.SoftSInt:
+		return s7.TypeSInt
+	case s7plus.SoftWord, s7plus.SoftUInt:
+		return s7.TypeWord
+	case s7plus.SoftInt:
+		return s7.TypeInt
+	case s7plus.SoftDWord, s7plus.SoftUDInt:
+		return s7.TypeDWord
+	case s7plus.SoftDInt:
+		return s7.TypeDInt
+	case s7plus.SoftReal:
+		return s7.TypeReal
+	case s7plus.SoftLWord, s7plus.SoftULInt:
+		return s7.TypeLWord
+	case s7plus.SoftLInt:
+		return s7.TypeLInt
+	case s7plus.SoftLReal:
+		return s7.TypeLReal
+	case s7plus.SoftDate:
+		return s7.TypeDate
+	case s7plus.SoftTime:
+		return s7.TypeTime
+	case s7plus.SoftTOD:
+		return s7.TypeTimeOfDay
+	case s7plus.SoftS5Time:
+		return s7.TypeS5Time
+	case s7plus.SoftDT:
+		return s7.TypeDateAndTime
+	case s7plus.SoftDTL:
+		return s7.TypeDTL
+	case s7plus.SoftString:
+		return s7.TypeString
+	case s7plus.SoftWChar:
+		return s7.TypeWChar
+	case s7plus.SoftWString:
+		return s7.TypeWString
+	default:
+		return 0
+	}
+}
+
 // Write writes a value to a tag.
 func (a *S7Adapter) Write(tag string, value interface{}) error {
 	if a.client == nil {
 		return fmt.Errorf("not connected")
 	}
+	if a.isSymbolic(tag) {
+		return fmt.Errorf("%s: S7CommPlus symbols are read-only", tag)
+	}
+
+	return a.client.WriteWithType(tag, value, a.typeHint(tag))
+}
+
+// WriteMany writes several tags, packing them into as few S7 Write Variable
+// jobs as the PDU size allows.
+func (a *S7Adapter) WriteMany(writes []TagWrite) ([]error, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	s7Writes := make([]s7.TagWrite, len(writes))
+	for i, w := range writes {
+		typeHint := w.TypeHint
+		if typeHint == "" {
+			typeHint = a.typeHint(w.Name)
+		}
+		s7Writes[i] = s7.TagWrite{
+			Address:  w.Name,
+			Value:    w.Value,
+			TypeHint: typeHint,
+		}
+	}
 
-	// Look up the tag's configured type
-	typeHint := ""
+	errs, err := a.client.WriteMany(s7Writes)
+	for i, w := range writes {
+		if i < len(errs) && a.isSymbolic(w.Name) {
+			errs[i] = fmt.Errorf("%s: S7CommPlus symbols are read-only", w.Name)
+		}
+	}
+	return errs, err
+}
+
+// typeHint returns the configured data type of a tag, or "" if the tag is
+// not in the config.
+func (a *S7Adapter) typeHint(tag string) string {
 	if a.config != nil {
 		for _, t := range a.config.Tags {
 			if strings.EqualFold(t.Name, tag) {
-				typeHint = t.DataType
-				break
+				return t.DataType
 			}
 		}
 	}
+	return ""
+}
 
-	return a.client.WriteWithType(tag, value, typeHint)
+// ReadContext is like Read but aborts in-flight requests when ctx ends.
+func (a *S7Adapter) ReadContext(ctx context.Context, requests []TagRequest) ([]*TagValue, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var values []*TagValue
+	err := withContext(ctx, a.client, func() (err error) {
+		values, err = a.Read(requests)
+		return err
+	})
+	return values, err
+}
+
+// WriteContext is like Write but aborts the in-flight request when ctx ends.
+func (a *S7Adapter) WriteContext(ctx context.Context, tag string, value interface{}) error {
+	if a.client == nil {
+		return fmt.Errorf("not connected")
+	}
+	return withContext(ctx, a.client, func() error {
+		return a.Write(tag, value)
+	})
+}
+
+// WriteManyContext is like WriteMany but aborts in-flight requests when ctx ends.
+func (a *S7Adapter) WriteManyContext(ctx context.Context, writes []TagWrite) ([]error, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var errs []error
+	err := withContext(ctx, a.client, func() (err error) {
+		errs, err = a.WriteMany(writes)
+		return err
+	})
+	return errs, err
+}
+
+// AllTagsContext is like AllTags but aborts in-flight requests when ctx ends.
+func (a *S7Adapter) AllTagsContext(ctx context.Context) ([]TagInfo, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	var tags []TagInfo
+	err := withContext(ctx, a.client, func() (err error) {
+		tags, err = a.AllTags()
+		return err
+	})
+	return tags, err
 }
 
 // Keepalive is a no-op for S7 (TCP connection is kept alive by OS).
@@ -0,0 +1,28 @@
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yatesdr/plcio/s7"
+	"github.com/yatesdr/plcio/s7plus"
+)
+
+func TestS7ReadReportsSymbolicAccessOff(t *testing.T) {
+	a := &S7Adapter{
+		client:  &s7.Client{},
+		config:  &PLCConfig{Family: FamilyS7, S7Symbolic: true},
+		plusErr: s7plus.ErrUnsupportedProtocol,
+	}
+
+	values, err := a.Read([]TagRequest{{Name: `"Motor".Speed`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(values[0].Error, s7plus.ErrUnsupportedProtocol) {
+		t.Errorf("error %v, want ErrUnsupportedProtocol", values[0].Error)
+	}
+	if !errors.Is(a.SymbolicError(), s7plus.ErrUnsupportedProtocol) {
+		t.Errorf("SymbolicError() = %v", a.SymbolicError())
+	}
+}
@@ -1,5 +1,6 @@
 package driver
 
+import "time"
 
 // TagValue is a unified wrapper that holds tag data from any PLC family.
 // It stores pre-computed Go values and type information for display.
@@ -30,6 +31,14 @@ type TagRequest struct {
 	TypeHint string // Optional type hint (e.g., "INT", "REAL", "DINT")
 }
 
+// TagWrite is one value in a batch write (see BatchWriter).
+// TypeHint is used for protocols that require type information (e.g., S7, Omron FINS).
+type TagWrite struct {
+	Name     string      // Tag name or address
+	Value    interface{} // Value to write
+	TypeHint string      // Optional type hint (e.g., "INT", "REAL", "DINT")
+}
+
 // TagInfo represents discovered tag metadata from PLCs that support tag browsing.
 type TagInfo struct {
 	Name       string   // Tag name
@@ -47,7 +56,14 @@ type DeviceInfo struct {
 	Model        string           // Device model
 	Version      string           // Firmware version
 	SerialNumber string           // Serial number
+	OrderNumber  string           // Vendor order/catalog number; empty if unknown
 	Description  string           // Additional description
+	RunState     string           // Controller run state (e.g. "RUN", "STOP"); empty if unknown
+	KeySwitch    string           // Mode switch position (e.g. "RUN", "PROG", "REM"); empty if unknown
+	MajorFault   bool             // Controller reports a major fault
+	MinorFault   bool             // Controller reports a minor fault
+	Clock        time.Time        // Controller wall clock in UTC; zero if unknown
+	ClockDrift   time.Duration    // Controller clock minus local clock at the time of reading
 }
 
 // ComputeStableValue returns a copy of the value with ignored members removed.
@@ -1,11 +1,14 @@
 package driver
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net"
 	"strings"
 	"syscall"
+
+	"github.com/yatesdr/plcio/internal/ctxerr"
 )
 
 // IsLikelyConnectionError checks if an error indicates a connection problem
@@ -60,3 +63,58 @@ func IsLikelyConnectionError(err error) bool {
 
 	return false
 }
+
+// writeManyByName sends writes through a client WriteMany that takes a map
+// of tag names to values. A name repeated in writes starts a new call, so
+// every write is sent in order and gets its own result. A top-level error
+// ends the batch; the writes not yet sent get that error.
+func writeManyByName(writes []TagWrite, writeMany func(map[string]interface{}) (map[string]error, error)) ([]error, error) {
+	errs := make([]error, len(writes))
+	for start := 0; start < len(writes); {
+		values := make(map[string]interface{})
+		end := start
+		for ; end < len(writes); end++ {
+			if _, dup := values[writes[end].Name]; dup {
+				break
+			}
+			values[writes[end].Name] = writes[end].Value
+		}
+
+		results, err := writeMany(values)
+		for i := start; i < end; i++ {
+			if e, ok := results[writes[i].Name]; ok {
+				errs[i] = e
+			} else if err != nil {
+				errs[i] = err
+			}
+		}
+		if err != nil {
+			for i := end; i < len(writes); i++ {
+				errs[i] = err
+			}
+			return errs, err
+		}
+		start = end
+	}
+	return errs, nil
+}
+
+// contextBinder is a protocol client whose socket I/O can be tied to a
+// context (see s7.Client.BindContext).
+type contextBinder interface {
+	BindContext(ctx context.Context) (release func(), err error)
+}
+
+// withContext runs op with client bound to ctx, for the adapters' *Context
+// methods. A failure caused by ctx ending is matchable with errors.Is.
+func withContext(ctx context.Context, client contextBinder, op func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	release, err := client.BindContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return ctxerr.Wrap(ctx, op())
+}
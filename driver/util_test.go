@@ -0,0 +1,62 @@
+package driver
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWriteManyByNameKeepsDuplicates(t *testing.T) {
+	var calls []map[string]interface{}
+	writeMany := func(values map[string]interface{}) (map[string]error, error) {
+		calls = append(calls, values)
+		results := make(map[string]error, len(values))
+		for name, v := range values {
+			if v == "bad" {
+				results[name] = errors.New("rejected")
+			} else {
+				results[name] = nil
+			}
+		}
+		return results, nil
+	}
+
+	errs, err := writeManyByName([]TagWrite{
+		{Name: "A", Value: 1},
+		{Name: "B", Value: "bad"},
+		{Name: "A", Value: 2},
+		{Name: "C", Value: 3},
+	}, writeMany)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []map[string]interface{}{
+		{"A": 1, "B": "bad"},
+		{"A": 2, "C": 3},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls %v, want %v", calls, want)
+	}
+	if errs[0] != nil || errs[1] == nil || errs[2] != nil || errs[3] != nil {
+		t.Errorf("errs %v", errs)
+	}
+}
+
+func TestWriteManyByNameStopsOnError(t *testing.T) {
+	lost := errors.New("connection lost")
+	calls := 0
+	errs, err := writeManyByName([]TagWrite{
+		{Name: "A", Value: 1},
+		{Name: "A", Value: 2},
+	}, func(values map[string]interface{}) (map[string]error, error) {
+		calls++
+		return nil, lost
+	})
+	if err != lost || calls != 1 {
+		t.Fatalf("err %v after %d calls, want connection lost after 1", err, calls)
+	}
+	if errs[0] != lost || errs[1] != lost {
+		t.Errorf("errs %v, want both connection lost", errs)
+	}
+}
@@ -1,6 +1,7 @@
 package eip
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/yatesdr/plcio/internal/ctxlock"
 	"github.com/yatesdr/plcio/logging"
 )
 
@@ -28,6 +30,11 @@ type EipClient struct {
 	session uint32
 	timeout time.Duration
 	mu      sync.Mutex
+
+	// Context binding (see BindContext). ctx is guarded by mu; ctxMu
+	// serializes bindings so only one caller's context governs the socket.
+	ctx   context.Context
+	ctxMu ctxlock.Mutex
 }
 
 func (e *EipClient) GetAddr() string {
@@ -99,10 +106,27 @@ func NewEipClientWithPort(ipaddr string, port uint16) *EipClient {
 }
 
 // Connect over EIP and register a session.
+// If a context is bound (see BindContext), it governs the dial and handshake.
 func (e *EipClient) Connect() error {
 	if e == nil {
 		return fmt.Errorf("Connect: Received nil client.")
 	}
+	e.mu.Lock()
+	ctx := e.ctx
+	e.mu.Unlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return e.ConnectContext(ctx)
+}
+
+// ConnectContext connects over EIP and registers a session. Cancelling ctx
+// aborts the TCP dial or the RegisterSession exchange; a ctx deadline earlier
+// than the client timeout shortens both.
+func (e *EipClient) ConnectContext(ctx context.Context) error {
+	if e == nil {
+		return fmt.Errorf("Connect: Received nil client.")
+	}
 
 	e.mu.Lock()
 	// Build the connection string.
@@ -114,7 +138,7 @@ func (e *EipClient) Connect() error {
 
 	// Dial with timeout.
 	d := net.Dialer{Timeout: timeout}
-	conn, err := d.Dial("tcp", connString)
+	conn, err := d.DialContext(ctx, "tcp", connString)
 	if err != nil {
 		logging.DebugConnectError("EIP", connString, err)
 		return fmt.Errorf("Failed in Connect: %w", err)
@@ -137,7 +161,11 @@ func (e *EipClient) Connect() error {
 	e.conn = conn
 	e.session = 0
 
+	// Register the session under the caller's context.
+	boundCtx := e.ctx
+	e.ctx = ctx
 	session, err := e.registerSession()
+	e.ctx = boundCtx
 	if err != nil {
 		e.conn = oldConn
 		e.session = oldSession
@@ -275,21 +303,28 @@ func (e *EipClient) transactEncap(msg EipEncap) (*EipEncap, error) {
 	if e.conn == nil {
 		return nil, fmt.Errorf("transactEncap: not connected.")
 	}
+	if err := e.contextErr(); err != nil {
+		return nil, fmt.Errorf("transactEncap: %w", err)
+	}
+
+	// Abort blocked I/O if the bound context is cancelled mid-transaction.
+	stop := e.watchContext()
+	defer stop()
 
 	// Avoid hanging forever on write.
-	_ = e.conn.SetWriteDeadline(time.Now().Add(e.timeout))
+	_ = e.conn.SetWriteDeadline(e.ioDeadline())
 	defer e.conn.SetWriteDeadline(time.Time{})
 	err := e.sendEncap(msg)
 	if err != nil {
-		return nil, fmt.Errorf("transactEncap: failed to send message.  %w", err)
+		return nil, fmt.Errorf("transactEncap: failed to send message.  %w", e.withContextErr(err))
 	}
 
 	// Avoid hanging forever on read.
-	_ = e.conn.SetReadDeadline(time.Now().Add(e.timeout))
+	_ = e.conn.SetReadDeadline(e.ioDeadline())
 	defer e.conn.SetReadDeadline(time.Time{})
 	resp, err := e.recvEncap()
 	if err != nil {
-		return nil, fmt.Errorf("transactEncap: failed to read response.  %w", err)
+		return nil, fmt.Errorf("transactEncap: failed to read response.  %w", e.withContextErr(err))
 	}
 
 	return resp, nil
@@ -489,13 +524,19 @@ func (e *EipClient) SendUnitData(packet EipCommonPacket) error {
 		data:          cmd_bytes,
 	}
 
+	if err := e.contextErr(); err != nil {
+		return fmt.Errorf("SendUnitData: %w", err)
+	}
+	stop := e.watchContext()
+	defer stop()
+
 	// Prevent hanging forever.
-	_ = e.conn.SetWriteDeadline(time.Now().Add(e.timeout))
+	_ = e.conn.SetWriteDeadline(e.ioDeadline())
 	defer e.conn.SetWriteDeadline(time.Time{})
 
 	err := e.sendEncap(req)
 	if err != nil {
-		return fmt.Errorf("SendUnitData: Failed to transmit packet. %w", err)
+		return fmt.Errorf("SendUnitData: Failed to transmit packet. %w", e.withContextErr(err))
 	}
 	return nil
 }
@@ -587,13 +628,19 @@ func (e *EipClient) SendNop() error {
 		data:          nil,
 	}
 
+	if err := e.contextErr(); err != nil {
+		return fmt.Errorf("SendNop: %w", err)
+	}
+	stop := e.watchContext()
+	defer stop()
+
 	// Prevent hanging forever.
-	_ = e.conn.SetWriteDeadline(time.Now().Add(e.timeout))
+	_ = e.conn.SetWriteDeadline(e.ioDeadline())
 	defer e.conn.SetWriteDeadline(time.Time{})
 
 	err := e.sendEncap(msg)
 	if err != nil {
-		return fmt.Errorf("SendNop: failed to transmit message.  %w", err)
+		return fmt.Errorf("SendNop: failed to transmit message.  %w", e.withContextErr(err))
 	}
 
 	return nil
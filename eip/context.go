@@ -0,0 +1,83 @@
+package eip
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// aLongTimeAgo is a non-zero time in the past. Setting it as a socket deadline
+// makes any blocked Read/Write return immediately with a timeout error.
+var aLongTimeAgo = time.Unix(1, 0)
+
+// BindContext ties all socket I/O on this client to ctx until the returned
+// release function is called. While bound, each transaction's deadline is the
+// earlier of the client timeout and the ctx deadline, and cancelling ctx
+// aborts a blocked send or receive. Because an aborted transaction leaves the
+// TCP stream mid-frame, the socket is closed and the caller must reconnect.
+//
+// Bindings are serialized: a second BindContext waits until the first is
+// released, or returns ctx.Err() if ctx ends first. Operations issued without
+// a context while a binding is active are governed by the bound context as
+// well.
+func (e *EipClient) BindContext(ctx context.Context) (release func(), err error) {
+	if e == nil || ctx == nil {
+		return func() {}, nil
+	}
+	if err := e.ctxMu.Lock(ctx); err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.ctx = ctx
+	e.mu.Unlock()
+	return func() {
+		e.mu.Lock()
+		e.ctx = nil
+		e.mu.Unlock()
+		e.ctxMu.Unlock()
+	}, nil
+}
+
+// contextErr returns the bound context's error, if any. Caller must hold e.mu.
+func (e *EipClient) contextErr() error {
+	if e.ctx == nil {
+		return nil
+	}
+	return e.ctx.Err()
+}
+
+// withContextErr attaches the bound context's error to an I/O failure caused
+// by cancellation, so callers can match it with errors.Is. Caller must hold e.mu.
+func (e *EipClient) withContextErr(err error) error {
+	if ctxErr := e.contextErr(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	return err
+}
+
+// ioDeadline returns the deadline for the next socket operation: the client
+// timeout, shortened to the bound context's deadline if that is sooner.
+// Caller must hold e.mu.
+func (e *EipClient) ioDeadline() time.Time {
+	deadline := time.Now().Add(e.timeout)
+	if e.ctx != nil {
+		if d, ok := e.ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+	}
+	return deadline
+}
+
+// watchContext arranges for the current socket to be unblocked if the bound
+// context is cancelled. The returned function stops the watch and must be
+// called when the transaction completes. Caller must hold e.mu.
+func (e *EipClient) watchContext() (stop func()) {
+	if e.ctx == nil || e.ctx.Done() == nil || e.conn == nil {
+		return func() {}
+	}
+	conn := e.conn
+	stopWatch := context.AfterFunc(e.ctx, func() {
+		_ = conn.SetDeadline(aLongTimeAgo)
+	})
+	return func() { stopWatch() }
+}
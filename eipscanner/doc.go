@@ -0,0 +1,35 @@
+// Package eipscanner implements the scanner (originator) side of EtherNet/IP
+// Class 1 implicit I/O. It opens a Forward_Open to a remote adapter — a
+// Point I/O head, a drive, or another plcio process using eipadapter — and
+// then exchanges assembly data cyclically over UDP 2222: O->T output data is
+// sent at the RPI and T->O input data is received from the adapter.
+//
+// Typical use:
+//
+//	sc, err := eipscanner.Open(eipscanner.Config{
+//	    Address:        "192.168.1.50",
+//	    ConfigInstance: 103,
+//	    OutputInstance: 102, OutputSize: 4,
+//	    InputInstance:  101, InputSize: 16,
+//	    RPI:            20 * time.Millisecond,
+//	    OnConsume: func(in []byte) {
+//	        log.Printf("inputs: % X", in)
+//	    },
+//	    OnTimeout: func(err error) {
+//	        log.Printf("I/O connection lost: %v", err)
+//	    },
+//	})
+//	if err != nil { log.Fatal(err) }
+//	defer sc.Close()
+//
+//	// Outputs are sent at every RPI until changed.
+//	sc.SetOutput(0, []byte{0x01, 0x00, 0x00, 0x00})
+//
+// Connections are point-to-point unless Config.Multicast is set, in which
+// case the adapter picks the T→O multicast group and the scanner joins it.
+// Callbacks run on the scanner's I/O goroutines; don't block in them.
+//
+// IMPORTANT: this package is NOT safety-rated. Do not use it to drive
+// outputs that any safety function depends on. See the plcio top-level
+// Safety & Intended Use documentation.
+package eipscanner
@@ -0,0 +1,199 @@
+package eipscanner
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/yatesdr/plcio/eip"
+	"github.com/yatesdr/plcio/logging"
+)
+
+// produce sends O->T data to the adapter at the O->T RPI until the scanner
+// stops.
+//
+// Sent format per packet (raw CPF, no encapsulation header):
+//
+//	CPF item count = 2
+//	  Sequenced Address Item (0x8002, len=8): O->T connID + 32-bit network seq
+//	  Connected Data Item    (0xB1, len=2[+4]+N): 16-bit data seq,
+//	                         run/idle header unless NoRunIdle, N data bytes
+func (s *Scanner) produce() {
+	defer s.wg.Done()
+
+	tick := time.NewTicker(s.otRPI)
+	defer tick.Stop()
+
+	var netSeq uint32
+	var dataSeq uint16
+	for {
+		s.mu.Lock()
+		out := append([]byte{}, s.output...)
+		run := s.run
+		s.mu.Unlock()
+
+		if s.cfg.OnProduce != nil && len(out) > 0 {
+			s.cfg.OnProduce(out)
+			s.mu.Lock()
+			copy(s.output, out)
+			s.mu.Unlock()
+		}
+
+		var header []byte
+		if !s.cfg.NoRunIdle {
+			header = make([]byte, 4)
+			if run {
+				header[0] = 0x01
+			}
+		}
+
+		netSeq++
+		dataSeq++
+		packet := buildIOPacket(s.conn.OTConnID, netSeq, dataSeq, header, out)
+		_ = s.udp.SetWriteDeadline(time.Now().Add(s.otRPI))
+		if _, err := s.udp.WriteToUDP(packet, s.target); err != nil && !errors.Is(err, net.ErrClosed) {
+			logging.DebugError("eipscanner", "O->T write", err)
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// consume receives T->O data and watches for the connection timeout.
+func (s *Scanner) consume() {
+	defer s.wg.Done()
+
+	// Poll at least every RPI so a silent adapter is noticed in time
+	poll := min(s.toRPI, s.timeout)
+	var lastSeq uint16
+	var haveSeq bool
+	buf := make([]byte, 1500)
+	for {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		_ = s.recv.SetReadDeadline(time.Now().Add(poll))
+		n, _, err := s.recv.ReadFromUDP(buf)
+		now := time.Now()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+				logging.DebugError("eipscanner", "T->O read", err)
+			}
+			s.checkTimeout(now)
+			continue
+		}
+
+		seq, data, ok := parseIOPacket(buf[:n], s.conn.TOConnID)
+		if !ok {
+			s.checkTimeout(now)
+			continue
+		}
+
+		// Some adapters prefix T->O data with a run/idle header as well
+		if len(data) == s.cfg.InputSize+4 {
+			data = data[4:]
+		}
+		if len(data) > s.cfg.InputSize {
+			data = data[:s.cfg.InputSize]
+		}
+
+		s.mu.Lock()
+		s.lastInput = now
+		fresh := !haveSeq || seq != lastSeq
+		if fresh {
+			copy(s.input, data)
+		}
+		s.mu.Unlock()
+		lastSeq, haveSeq = seq, true
+
+		if fresh && s.cfg.OnConsume != nil {
+			s.cfg.OnConsume(append([]byte{}, data...))
+		}
+	}
+}
+
+// checkTimeout stops the scanner if no T->O data arrived within the
+// connection timeout.
+func (s *Scanner) checkTimeout(now time.Time) {
+	s.mu.Lock()
+	idle := now.Sub(s.lastInput)
+	s.mu.Unlock()
+	if idle <= s.timeout {
+		return
+	}
+
+	err := fmt.Errorf("%w: no T->O data for %v", ErrTimeout, idle.Round(time.Millisecond))
+	if !s.stop(err) {
+		return
+	}
+	logging.DebugLog("eipscanner", "conn 0x%08X timed out (no T->O data for %v)", s.conn.TOConnID, idle)
+	if s.cfg.OnTimeout != nil {
+		s.cfg.OnTimeout(err)
+	}
+}
+
+func buildIOPacket(connID, netSeq uint32, dataSeq uint16, header, data []byte) []byte {
+	addr := make([]byte, 0, 8)
+	addr = binary.LittleEndian.AppendUint32(addr, connID)
+	addr = binary.LittleEndian.AppendUint32(addr, netSeq)
+
+	payload := make([]byte, 0, 2+len(header)+len(data))
+	payload = binary.LittleEndian.AppendUint16(payload, dataSeq)
+	payload = append(payload, header...)
+	payload = append(payload, data...)
+
+	cpf := eip.EipCommonPacket{Items: []eip.EipCommonPacketItem{
+		{TypeId: eip.CpfSequencedAddressId, Length: uint16(len(addr)), Data: addr},
+		{TypeId: eip.CpfConnectedTransportPacketId, Length: uint16(len(payload)), Data: payload},
+	}}
+	return cpf.Bytes()
+}
+
+// parseIOPacket extracts the data sequence count and data of a T->O packet
+// for connID. Packets may be raw CPF or wrapped in a SendUnitData
+// encapsulation header, as the eipadapter producer sends them.
+func parseIOPacket(raw []byte, connID uint32) (uint16, []byte, bool) {
+	cpfBytes := raw
+	if len(raw) >= int(eip.EncapHeaderLen) {
+		if f, err := eip.ParseFrame(raw); err == nil && f.Command == eip.SendUnitData {
+			rr, err := eip.ParseRRData(f.Data)
+			if err != nil {
+				return 0, nil, false
+			}
+			cpfBytes = rr
+		}
+	}
+
+	pkt, err := eip.ParseEipCommonPacket(cpfBytes)
+	if err != nil {
+		return 0, nil, false
+	}
+	var id uint32
+	var payload []byte
+	for _, it := range pkt.Items {
+		switch it.TypeId {
+		case eip.CpfSequencedAddressId:
+			if len(it.Data) >= 8 {
+				id = binary.LittleEndian.Uint32(it.Data[0:4])
+			}
+		case eip.CpfConnectedTransportPacketId:
+			payload = it.Data
+		}
+	}
+	if id != connID || len(payload) < 2 {
+		return 0, nil, false
+	}
+	return binary.LittleEndian.Uint16(payload[0:2]), payload[2:], true
+}
@@ -0,0 +1,509 @@
+package eipscanner
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yatesdr/plcio/cip"
+	"github.com/yatesdr/plcio/eip"
+	"github.com/yatesdr/plcio/logging"
+)
+
+// ErrTimeout is reported through Config.OnTimeout and Err when the adapter
+// stops sending T->O data for longer than the connection timeout.
+var ErrTimeout = errors.New("eipscanner: connection timed out")
+
+// ErrClosed is returned by Err after Close.
+var ErrClosed = errors.New("eipscanner: scanner closed")
+
+// Config configures a Class 1 connection to one adapter.
+type Config struct {
+	// Address is the adapter's IPv4 address, optionally with ":port" for the
+	// EtherNet/IP TCP port. Default port 44818.
+	Address string
+	// IOPort is the adapter's UDP port for Class 1 I/O. Default 2222.
+	IOPort uint16
+	// LocalIOAddr is the local UDP address T->O data is received on.
+	// Default ":2222". A port other than 2222 is announced to the adapter
+	// in the Forward_Open. With Multicast only its IP is used, to pick the
+	// interface O->T packets are sent from.
+	LocalIOAddr string
+	// Route is a CIP port path to a target behind the adapter, e.g.
+	// cip.ParseConnectionPath("1,3") for a module in slot 3 of a chassis.
+	// Leave empty to connect to the adapter itself.
+	Route []byte
+	// Tag targets a produced tag of a Logix controller by name instead of
+	// assemblies. The connection path is Route followed by the tag's
+	// symbolic segment, and the instance fields are ignored.
+	Tag string
+	// Multicast requests multicast T->O data. The adapter picks the group,
+	// which the scanner joins on the port the adapter names.
+	Multicast bool
+
+	// Assembly instances. ConfigInstance 0 means no configuration assembly.
+	// OutputInstance 0 opens an input-only connection; O->T packets then
+	// carry no data and only serve as a heartbeat.
+	ConfigInstance uint16
+	OutputInstance uint16
+	InputInstance  uint16
+
+	// Data sizes in bytes, excluding the sequence count and run/idle header.
+	OutputSize int
+	InputSize  int
+
+	// RPI is the requested packet interval for both directions. Default
+	// 50ms.
+	RPI time.Duration
+	// TimeoutMultiplier sets the connection timeout to 4 << n times the RPI.
+	// Default 3 (32x).
+	TimeoutMultiplier byte
+	// NoRunIdle sends O->T data without the 32-bit run/idle header most
+	// adapters expect.
+	NoRunIdle bool
+
+	// Timeout bounds the TCP connect, Forward_Open and Forward_Close.
+	// Default 5s.
+	Timeout time.Duration
+
+	// OnProduce, if set, is called before every O->T packet with the output
+	// data. Changes made to out are sent and kept for the next packet.
+	OnProduce func(out []byte)
+	// OnConsume is called with a copy of the input data for each new T->O
+	// packet. Repeated packets with the same sequence count are skipped.
+	OnConsume func(in []byte)
+	// OnTimeout is called once if the connection times out. The scanner
+	// stops exchanging data; Close it and Open a new one to reconnect.
+	OnTimeout func(err error)
+}
+
+func (c *Config) defaults() {
+	if c.IOPort == 0 {
+		c.IOPort = 2222
+	}
+	if c.LocalIOAddr == "" {
+		c.LocalIOAddr = ":2222"
+	}
+	if c.RPI <= 0 {
+		c.RPI = 50 * time.Millisecond
+	}
+	if c.TimeoutMultiplier == 0 {
+		c.TimeoutMultiplier = 3
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+}
+
+// Scanner is an open Class 1 connection.
+type Scanner struct {
+	cfg Config
+
+	client *eip.EipClient
+	conn   *cip.Connection
+	path   []byte
+
+	udp    *net.UDPConn // O->T, and T->O unless multicast
+	recv   *net.UDPConn // T->O
+	target *net.UDPAddr
+
+	otRPI   time.Duration
+	toRPI   time.Duration
+	timeout time.Duration
+
+	mu        sync.Mutex
+	output    []byte
+	input     []byte
+	run       bool
+	lastInput time.Time
+	err       error
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// Open connects to the adapter, opens the Class 1 connection and starts
+// exchanging data.
+func Open(cfg Config) (*Scanner, error) {
+	return OpenContext(context.Background(), cfg)
+}
+
+// OpenContext is like Open but aborts the TCP connect when ctx is cancelled.
+func OpenContext(ctx context.Context, cfg Config) (*Scanner, error) {
+	cfg.defaults()
+	if cfg.InputInstance == 0 && cfg.Tag == "" {
+		return nil, fmt.Errorf("eipscanner: InputInstance or Tag is required")
+	}
+	if cfg.OutputInstance == 0 && cfg.Tag == "" && cfg.OutputSize != 0 {
+		return nil, fmt.Errorf("eipscanner: OutputSize set without OutputInstance")
+	}
+	if cfg.OutputSize < 0 || cfg.InputSize < 0 {
+		return nil, fmt.Errorf("eipscanner: negative assembly size")
+	}
+
+	host, port := cfg.Address, uint16(44818)
+	if h, p, err := net.SplitHostPort(cfg.Address); err == nil {
+		n, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("eipscanner: invalid port in %q", cfg.Address)
+		}
+		host, port = h, uint16(n)
+	}
+	target, err := net.ResolveUDPAddr("udp4", net.JoinHostPort(host, strconv.Itoa(int(cfg.IOPort))))
+	if err != nil {
+		return nil, fmt.Errorf("eipscanner: resolve %q: %w", host, err)
+	}
+	local, err := net.ResolveUDPAddr("udp4", cfg.LocalIOAddr)
+	if err != nil {
+		return nil, fmt.Errorf("eipscanner: resolve local %q: %w", cfg.LocalIOAddr, err)
+	}
+	if cfg.Multicast {
+		// The group socket takes the I/O port; send from an ephemeral one
+		local.Port = 0
+	}
+
+	s := &Scanner{
+		cfg:    cfg,
+		target: target,
+		output: make([]byte, cfg.OutputSize),
+		input:  make([]byte, cfg.InputSize),
+		run:    true,
+		done:   make(chan struct{}),
+	}
+
+	s.udp, err = net.ListenUDP("udp4", local)
+	if err != nil {
+		return nil, fmt.Errorf("eipscanner: listen UDP %q: %w", cfg.LocalIOAddr, err)
+	}
+
+	s.client = eip.NewEipClientWithPort(host, port)
+	s.client.SetTimeout(cfg.Timeout)
+	if err := s.client.ConnectContext(ctx); err != nil {
+		s.udp.Close()
+		logging.DebugConnectError("eipscanner", cfg.Address, err)
+		return nil, fmt.Errorf("eipscanner: connect: %w", err)
+	}
+
+	if err := s.forwardOpen(); err != nil {
+		s.client.Disconnect()
+		s.udp.Close()
+		logging.DebugConnectError("eipscanner", cfg.Address, err)
+		return nil, err
+	}
+	if s.recv == nil {
+		s.recv = s.udp
+	}
+
+	logging.DebugConnectSuccess("eipscanner", cfg.Address,
+		fmt.Sprintf("O->T=0x%08X T->O=0x%08X RPI=%v/%v timeout=%v", s.conn.OTConnID, s.conn.TOConnID, s.otRPI, s.toRPI, s.timeout))
+
+	s.lastInput = time.Now()
+	s.wg.Add(2)
+	go s.produce()
+	go s.consume()
+	return s, nil
+}
+
+// connectionPath builds the route followed by the application path: the
+// tag's symbolic segment, or class 4, configuration instance, then the O->T
+// and T->O connection points.
+func (s *Scanner) connectionPath() ([]byte, error) {
+	if s.cfg.Tag != "" {
+		app, err := cip.EPath().Symbol(s.cfg.Tag).Build()
+		if err != nil {
+			return nil, err
+		}
+		return append(append([]byte{}, s.cfg.Route...), app...), nil
+	}
+
+	b := cip.EPath().Class(0x04)
+	switch inst := s.cfg.ConfigInstance; {
+	case inst == 0:
+		b = b.Instance(0x80)
+	case inst <= 0xFF:
+		b = b.Instance(byte(inst))
+	default:
+		b = b.Instance16(inst)
+	}
+	for _, cp := range []uint16{s.cfg.OutputInstance, s.cfg.InputInstance} {
+		switch {
+		case cp == 0:
+		case cp <= 0xFF:
+			b = b.ConnectionPoint(byte(cp))
+		default:
+			b = b.ConnectionPoint16(cp)
+		}
+	}
+	app, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, s.cfg.Route...), app...), nil
+}
+
+// otConnectionSize is the O->T connection size: sequence count, run/idle
+// header and output data.
+func (s *Scanner) otConnectionSize() int {
+	n := 2 + s.cfg.OutputSize
+	if !s.cfg.NoRunIdle {
+		n += 4
+	}
+	return n
+}
+
+// forwardOpen opens the Class 1 connection over the explicit session.
+func (s *Scanner) forwardOpen() error {
+	path, err := s.connectionPath()
+	if err != nil {
+		return fmt.Errorf("eipscanner: connection path: %w", err)
+	}
+
+	otSize := s.otConnectionSize()
+	toSize := 2 + s.cfg.InputSize
+
+	cfg := cip.DefaultForwardOpenConfig()
+	cfg.ConnectionPath = path
+	cfg.OTConnectionSize = uint16(otSize)
+	cfg.TOConnectionSize = uint16(toSize)
+	cfg.OTRPI = s.cfg.RPI
+	cfg.TORPI = s.cfg.RPI
+	cfg.OTNetworkParams = cip.NetParamsPointToPoint | cip.NetParamsPriorityScheduled | cip.NetParamsFixedSize
+	cfg.TONetworkParams = cip.NetParamsPointToPoint | cip.NetParamsPriorityScheduled | cip.NetParamsFixedSize
+	if s.cfg.Multicast {
+		cfg.TONetworkParams = cip.NetParamsMulticast | cip.NetParamsPriorityScheduled | cip.NetParamsFixedSize
+	}
+	cfg.TransportTrigger = cip.TransportClass1Cyclic
+	cfg.TimeoutMultiplier = s.cfg.TimeoutMultiplier
+
+	// Standard Forward Open (0x54) for sizes ≤511, Large (0x5B) above
+	var reqData []byte
+	var connSerial uint16
+	if otSize <= 511 && toSize <= 511 {
+		reqData, connSerial, err = cip.BuildForwardOpenRequestSmall(cfg)
+	} else {
+		reqData, connSerial, err = cip.BuildForwardOpenRequest(cfg)
+	}
+	if err != nil {
+		return fmt.Errorf("eipscanner: Forward_Open: %w", err)
+	}
+
+	cpf := eip.EipCommonPacket{Items: []eip.EipCommonPacketItem{
+		{TypeId: eip.CpfAddressNullId},
+		{TypeId: eip.CpfUnconnectedMessageId, Length: uint16(len(reqData)), Data: reqData},
+	}}
+	if port := s.udp.LocalAddr().(*net.UDPAddr).Port; port != 2222 && !s.cfg.Multicast {
+		cpf.Items = append(cpf.Items, sockaddrItem(eip.CpfSockAddrInfoTtoOId, uint16(port)))
+	}
+
+	resp, err := s.client.SendRRData(cpf)
+	if err != nil {
+		return fmt.Errorf("eipscanner: Forward_Open: %w", err)
+	}
+	var cipResp []byte
+	var group *net.UDPAddr
+	for _, it := range resp.Items {
+		switch it.TypeId {
+		case eip.CpfUnconnectedMessageId:
+			cipResp = it.Data
+		case eip.CpfSockAddrInfoTtoOId:
+			group = parseSockaddr(it.Data)
+		}
+	}
+	if len(cipResp) < 4 {
+		return fmt.Errorf("eipscanner: Forward_Open: response too short")
+	}
+
+	replyService := cipResp[0]
+	status := cipResp[2]
+	addlStatusSize := int(cipResp[3])
+	if replyService != (cip.SvcForwardOpen|0x80) && replyService != (cip.SvcForwardOpenLarge|0x80) {
+		return fmt.Errorf("eipscanner: Forward_Open: unexpected reply service 0x%02X", replyService)
+	}
+	if status != cip.StatusSuccess {
+		extStatus := uint16(0)
+		if addlStatusSize >= 1 && len(cipResp) >= 6 {
+			extStatus = binary.LittleEndian.Uint16(cipResp[4:6])
+		}
+		return fmt.Errorf("eipscanner: Forward_Open failed - status=0x%02X, extStatus=0x%04X, path=% X",
+			status, extStatus, path)
+	}
+
+	dataStart := 4 + addlStatusSize*2
+	if dataStart >= len(cipResp) {
+		return fmt.Errorf("eipscanner: Forward_Open: response missing data")
+	}
+	fo, err := cip.ParseForwardOpenResponse(cipResp[dataStart:])
+	if err != nil {
+		return fmt.Errorf("eipscanner: %w", err)
+	}
+
+	// The builder fills in its own originator vendor and serial; the reply
+	// echoes them, and Forward_Close must match.
+	s.conn = &cip.Connection{
+		OTConnID:     fo.OTConnectionID,
+		TOConnID:     fo.TOConnectionID,
+		SerialNumber: connSerial,
+		VendorID:     fo.VendorID,
+		OrigSerial:   fo.OriginatorSerial,
+	}
+	s.path = path
+
+	// Actual packet intervals granted by the adapter
+	s.otRPI, s.toRPI = s.cfg.RPI, s.cfg.RPI
+	if fo.OTRPI != 0 {
+		s.otRPI = time.Duration(fo.OTRPI) * time.Microsecond
+	}
+	if fo.TORPI != 0 {
+		s.toRPI = time.Duration(fo.TORPI) * time.Microsecond
+	}
+	s.timeout = connectionTimeout(s.toRPI, s.cfg.TimeoutMultiplier)
+
+	if s.cfg.Multicast {
+		if group == nil || !group.IP.IsMulticast() {
+			s.forwardClose()
+			return fmt.Errorf("eipscanner: Forward_Open reply has no multicast address")
+		}
+		s.recv, err = net.ListenMulticastUDP("udp4", nil, group)
+		if err != nil {
+			s.forwardClose()
+			return fmt.Errorf("eipscanner: join %v: %w", group, err)
+		}
+		logging.DebugLog("eipscanner", "joined multicast group %v for T->O=0x%08X", group, s.conn.TOConnID)
+	}
+	return nil
+}
+
+// forwardClose closes the Class 1 connection. Errors are only logged: the
+// adapter drops the connection on its own once O->T data stops.
+func (s *Scanner) forwardClose() {
+	req, err := cip.BuildForwardCloseRequest(s.conn, s.path)
+	if err != nil {
+		return
+	}
+	cpf := eip.EipCommonPacket{Items: []eip.EipCommonPacketItem{
+		{TypeId: eip.CpfAddressNullId},
+		{TypeId: eip.CpfUnconnectedMessageId, Length: uint16(len(req)), Data: req},
+	}}
+	if _, err := s.client.SendRRData(cpf); err != nil {
+		logging.DebugError("eipscanner", "Forward_Close", err)
+	}
+}
+
+// sockaddrItem builds a Sockaddr Info item announcing a UDP port. The
+// address is left 0 so the adapter uses the originator's IP. Sockaddr fields
+// are big-endian.
+func sockaddrItem(typeID, port uint16) eip.EipCommonPacketItem {
+	data := make([]byte, 16)
+	binary.BigEndian.PutUint16(data[0:2], 2) // AF_INET
+	binary.BigEndian.PutUint16(data[2:4], port)
+	return eip.EipCommonPacketItem{TypeId: typeID, Length: uint16(len(data)), Data: data}
+}
+
+// parseSockaddr decodes a Sockaddr Info item, or returns nil if it is not an
+// IPv4 address.
+func parseSockaddr(data []byte) *net.UDPAddr {
+	if len(data) < 8 || binary.BigEndian.Uint16(data[0:2]) != 2 {
+		return nil
+	}
+	return &net.UDPAddr{
+		IP:   net.IPv4(data[4], data[5], data[6], data[7]),
+		Port: int(binary.BigEndian.Uint16(data[2:4])),
+	}
+}
+
+// connectionTimeout returns the allowed gap between T->O packets: the RPI
+// times 4 << multiplier (CIP Vol 1, table 3-5.4).
+func connectionTimeout(rpi time.Duration, mult byte) time.Duration {
+	if mult > 7 {
+		mult = 7
+	}
+	return rpi * time.Duration(4<<mult)
+}
+
+// SetOutput writes data into the O->T output at offset and returns the
+// number of bytes written (clipped to OutputSize). It is sent from the next
+// RPI on.
+func (s *Scanner) SetOutput(offset int, data []byte) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if offset < 0 || offset >= len(s.output) {
+		return 0
+	}
+	return copy(s.output[offset:], data)
+}
+
+// Output returns a copy of the current O->T output data.
+func (s *Scanner) Output() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]byte{}, s.output...)
+}
+
+// Input returns a copy of the most recent T->O input data.
+func (s *Scanner) Input() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]byte{}, s.input...)
+}
+
+// SetRun sets the run/idle header sent with O->T data. The scanner starts
+// in run mode; adapters typically hold outputs in their idle state while it
+// is cleared. Has no effect with NoRunIdle.
+func (s *Scanner) SetRun(run bool) {
+	s.mu.Lock()
+	s.run = run
+	s.mu.Unlock()
+}
+
+// ConnectionIDs returns the O->T and T->O connection IDs of the connection.
+func (s *Scanner) ConnectionIDs() (ot, to uint32) {
+	return s.conn.OTConnID, s.conn.TOConnID
+}
+
+// Done is closed when the connection times out or the scanner is closed.
+func (s *Scanner) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns nil while the connection is up, an error wrapping ErrTimeout
+// after a timeout, and ErrClosed if it was closed before timing out.
+func (s *Scanner) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// stop ends data exchange with err. It returns false if already stopped.
+func (s *Scanner) stop(err error) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return false
+	}
+	s.err = err
+	close(s.done)
+	return true
+}
+
+// Close sends a Forward_Close, stops data exchange and releases the
+// sockets. Safe to call more than once.
+func (s *Scanner) Close() error {
+	s.closeOnce.Do(func() {
+		s.stop(ErrClosed)
+		s.wg.Wait()
+		s.forwardClose()
+		s.client.Disconnect()
+		s.udp.Close()
+		if s.recv != s.udp {
+			s.recv.Close()
+		}
+		logging.DebugDisconnect("eipscanner", s.cfg.Address, "closed")
+	})
+	return nil
+}
@@ -0,0 +1,190 @@
+package eipscanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/yatesdr/plcio/eipadapter"
+)
+
+// Ports for the test adapter, away from the defaults the eipadapter tests
+// bind so both packages can run in parallel.
+const (
+	testTCPPort = 44918
+	testIOPort  = 2322
+)
+
+// startAdapter serves an adapter until the test ends or the returned cancel
+// function is called. Serve only returns once its TCP sessions end, so the
+// test waits for it in a cleanup, after scanners have been closed.
+func startAdapter(t *testing.T, asm ...*eipadapter.Assembly) context.CancelFunc {
+	t.Helper()
+	a, err := eipadapter.New(eipadapter.Config{
+		BindAddr: "127.0.0.1",
+		TCPPort:  testTCPPort,
+		UDPPort:  testTCPPort,
+		IOPort:   testIOPort,
+		Identity: eipadapter.Identity{
+			VendorID:     0x1337,
+			DeviceType:   0x000C,
+			ProductCode:  1,
+			RevMajor:     1,
+			SerialNumber: 0xC0FFEE02,
+			ProductName:  "Test Adapter",
+			State:        0x03,
+		},
+		Assemblies: asm,
+	})
+	if err != nil {
+		t.Fatalf("eipadapter.New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = a.Serve(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	time.Sleep(50 * time.Millisecond)
+	return cancel
+}
+
+func TestCyclicIOAndTimeout(t *testing.T) {
+	input := eipadapter.NewAssembly(101, eipadapter.AssemblyInput, 4)
+	output := eipadapter.NewAssembly(102, eipadapter.AssemblyOutput, 2)
+	received := make(chan []byte, 16)
+	output.OnChange(func(_, new []byte) {
+		select {
+		case received <- new:
+		default:
+		}
+	})
+	input.SetBytes(0, []byte{0xCA, 0xFE, 0xBA, 0xBE})
+	stopAdapter := startAdapter(t, input, output)
+
+	consumed := make(chan []byte, 64)
+	timedOut := make(chan error, 1)
+	sc, err := Open(Config{
+		Address:           "127.0.0.1:44918",
+		IOPort:            testIOPort,
+		LocalIOAddr:       "127.0.0.1:0",
+		OutputInstance:    102,
+		OutputSize:        2,
+		InputInstance:     101,
+		InputSize:         4,
+		RPI:               20 * time.Millisecond,
+		TimeoutMultiplier: 2,
+		OnProduce: func(out []byte) {
+			out[0] = 0x5A
+		},
+		OnConsume: func(in []byte) {
+			select {
+			case consumed <- in:
+			default:
+			}
+		},
+		OnTimeout: func(err error) { timedOut <- err },
+	})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer sc.Close()
+
+	select {
+	case in := <-consumed:
+		if !bytes.Equal(in, []byte{0xCA, 0xFE, 0xBA, 0xBE}) {
+			t.Errorf("consumed % X, want CA FE BA BE", in)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no T->O data received")
+	}
+
+	sc.SetOutput(1, []byte{0x42})
+	deadline := time.After(2 * time.Second)
+	for got := false; !got; {
+		select {
+		case out := <-received:
+			got = bytes.Equal(out, []byte{0x5A, 0x42})
+		case <-deadline:
+			t.Fatalf("adapter never received output 5A 42, has % X", output.Bytes())
+		}
+	}
+
+	// Stopping the adapter ends T->O traffic
+	stopAdapter()
+	select {
+	case err := <-timedOut:
+		if !errors.Is(err, ErrTimeout) {
+			t.Errorf("OnTimeout error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout not detected")
+	}
+	if !errors.Is(sc.Err(), ErrTimeout) {
+		t.Errorf("Err() = %v, want ErrTimeout", sc.Err())
+	}
+	select {
+	case <-sc.Done():
+	default:
+		t.Error("Done not closed after timeout")
+	}
+}
+
+func TestForwardOpenRejected(t *testing.T) {
+	input := eipadapter.NewAssembly(101, eipadapter.AssemblyInput, 4)
+	startAdapter(t, input)
+
+	_, err := Open(Config{
+		Address:       "127.0.0.1:44918",
+		IOPort:        testIOPort,
+		LocalIOAddr:   "127.0.0.1:0",
+		InputInstance: 150, // no such assembly
+		InputSize:     4,
+	})
+	if err == nil {
+		t.Fatal("expected Forward_Open to fail for a missing assembly")
+	}
+}
+
+func TestConnectionPath(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		want []byte
+	}{
+		{Config{InputInstance: 101}, []byte{0x20, 0x04, 0x24, 0x80, 0x2C, 0x65}},
+		{Config{ConfigInstance: 103, OutputInstance: 102, InputInstance: 101},
+			[]byte{0x20, 0x04, 0x24, 0x67, 0x2C, 0x66, 0x2C, 0x65}},
+		{Config{Route: []byte{0x01, 0x03}, OutputInstance: 0x300, InputInstance: 101},
+			[]byte{0x01, 0x03, 0x20, 0x04, 0x24, 0x80, 0x2D, 0x00, 0x00, 0x03, 0x2C, 0x65}},
+		{Config{Route: []byte{0x01, 0x00}, Tag: "Out1", InputInstance: 101},
+			[]byte{0x01, 0x00, 0x91, 0x04, 'O', 'u', 't', '1'}},
+	}
+	for _, tt := range tests {
+		s := &Scanner{cfg: tt.cfg}
+		got, err := s.connectionPath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("connectionPath(%+v) = % X, want % X", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestParseSockaddr(t *testing.T) {
+	data := []byte{0x00, 0x02, 0x08, 0xAE, 239, 192, 1, 5, 0, 0, 0, 0, 0, 0, 0, 0}
+	got := parseSockaddr(data)
+	if got == nil || !got.IP.Equal(net.IPv4(239, 192, 1, 5)) || got.Port != 2222 {
+		t.Errorf("parseSockaddr = %v, want 239.192.1.5:2222", got)
+	}
+	if parseSockaddr(data[:4]) != nil {
+		t.Error("short item parsed")
+	}
+}
@@ -0,0 +1,18 @@
+// Package ctxerr reports failures caused by a context ending.
+package ctxerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Wrap makes a failure caused by ctx ending matchable with errors.Is. Read
+// paths fold transport errors into each package's ErrConnectionLost, which
+// would otherwise hide that the deadline or cancellation was the cause.
+func Wrap(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ctx.Err(), err)
+}
@@ -0,0 +1,35 @@
+// Package ctxlock provides a mutex whose Lock gives up when a context ends.
+package ctxlock
+
+import (
+	"context"
+	"sync"
+)
+
+// Mutex is a mutual exclusion lock that a waiter can abandon by cancelling
+// its context. The zero value is an unlocked mutex.
+type Mutex struct {
+	once sync.Once
+	sem  chan struct{}
+}
+
+func (m *Mutex) init() {
+	m.once.Do(func() { m.sem = make(chan struct{}, 1) })
+}
+
+// Lock acquires m, or returns ctx.Err() if ctx ends first.
+func (m *Mutex) Lock(ctx context.Context) error {
+	m.init()
+	select {
+	case m.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Unlock releases m. It must be held.
+func (m *Mutex) Unlock() {
+	m.init()
+	<-m.sem
+}
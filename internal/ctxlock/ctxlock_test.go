@@ -0,0 +1,27 @@
+package ctxlock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLockGivesUpWhenContextEnds(t *testing.T) {
+	var m Mutex
+	if err := m.Lock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.Lock(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded while held, got %v", err)
+	}
+
+	m.Unlock()
+	if err := m.Lock(context.Background()); err != nil {
+		t.Fatalf("lock after unlock: %v", err)
+	}
+	m.Unlock()
+}
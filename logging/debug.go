@@ -28,7 +28,7 @@ var knownProtocols = []string{
 	"omron", "fins", "fins/tcp", "fins/udp", "eip", "eip/discovery", "omron/eip",
 	"ads",
 	"logix",
-	"s7",
+	"s7", "s7plus",
 	"mqtt",
 	"kafka",
 	"valkey",
@@ -102,6 +102,8 @@ func (l *DebugLogger) SetFilter(filter string) {
 				l.filters["eip/discovery"] = true
 				l.filters["omron"] = true
 				l.filters["omron/eip"] = true
+			case "s7":
+				l.filters["s7plus"] = true
 			}
 		}
 	}
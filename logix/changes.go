@@ -0,0 +1,158 @@
+package logix
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/yatesdr/plcio/cip"
+)
+
+// Get Attribute List service and the controller object that carries the
+// change-detection attributes (Logix 5000 Data Access, "Detecting changes").
+const (
+	SvcGetAttributeList byte = 0x03
+
+	classChangeDetect byte = 0xAC
+)
+
+// changeDetectAttrs are the attributes the controller updates when tags or
+// data types are added, removed or changed by a download or online edit.
+var changeDetectAttrs = []uint16{1, 2, 3, 4, 10}
+
+// ChangeEvent reports that the controller's tags or data types changed.
+type ChangeEvent struct {
+	Tags    []TagInfo // Refreshed tag list, as stored with SetTags
+	Added   []string  // Tags that did not exist before
+	Removed []string  // Tags that no longer exist
+	Retyped []string  // Tags whose type or dimensions changed
+	Err     error     // Set if the tag list could not be re-read
+}
+
+// changeWatch is the state of WatchChanges.
+type changeWatch struct {
+	interval  time.Duration
+	fn        func(*ChangeEvent)
+	lastCheck time.Time
+}
+
+// ReadChangeSignature reads the controller's change-detection attributes and
+// returns them as an opaque signature. The signature differs after any
+// download or online edit that changes tags or data types.
+func (p *PLC) ReadChangeSignature() ([]byte, error) {
+	if p == nil || p.Connection == nil {
+		return nil, fmt.Errorf("ReadChangeSignature: nil plc or connection")
+	}
+
+	path, err := cip.EPath().Class(classChangeDetect).Instance(1).Build()
+	if err != nil {
+		return nil, fmt.Errorf("ReadChangeSignature: %w", err)
+	}
+
+	data, err := p.getAttributeList(path, changeDetectAttrs)
+	if err != nil {
+		return nil, fmt.Errorf("ReadChangeSignature: %w", err)
+	}
+
+	// The attribute values are compared as a whole, so they are kept raw
+	return append([]byte{}, data...), nil
+}
+
+// CheckForChanges reads the controller's change-detection attributes and,
+// if they changed since the last check, drops the template caches, re-reads
+// the tag list with AllTags and stores it with SetTags. It returns nil when
+// nothing changed or on the first call, which only records the current state.
+//
+// If the tag list cannot be re-read, the returned event carries the error
+// and the next call tries again.
+func (c *Client) CheckForChanges() (*ChangeEvent, error) {
+	if c == nil || c.plc == nil {
+		return nil, fmt.Errorf("CheckForChanges: nil client")
+	}
+
+	sig, err := c.plc.ReadChangeSignature()
+	if err != nil {
+		return nil, err
+	}
+	if c.changeSig == nil {
+		c.changeSig = sig
+		return nil, nil
+	}
+	if bytes.Equal(sig, c.changeSig) {
+		return nil, nil
+	}
+
+	debugLog("CheckForChanges: controller changed (% X -> % X), refreshing tags", c.changeSig, sig)
+	c.ClearTemplateCache()
+
+	tags, err := c.AllTags()
+	if err != nil {
+		return &ChangeEvent{Err: err}, nil
+	}
+	old := c.tagInfo
+	ev := &ChangeEvent{Tags: c.SetTags(tags)}
+	for _, t := range ev.Tags {
+		prev, ok := old[t.Name]
+		switch {
+		case !ok:
+			ev.Added = append(ev.Added, t.Name)
+		case prev.TypeCode != t.TypeCode || !reflect.DeepEqual(prev.Dimensions, t.Dimensions):
+			ev.Retyped = append(ev.Retyped, t.Name)
+		}
+	}
+	for name := range old {
+		if _, ok := c.tagInfo[name]; !ok {
+			ev.Removed = append(ev.Removed, name)
+		}
+	}
+
+	c.changeSig = sig
+	return ev, nil
+}
+
+// WatchChanges makes the client check for controller changes at most once
+// per interval, as part of Keepalive calls, and call fn with each change
+// found (see CheckForChanges). The current state is read right away as the
+// baseline; an error means the controller does not support change
+// detection. An interval of 0 stops watching.
+//
+// A check that finds a change re-reads the whole tag list, so it is kept
+// out of Read: reads never wait for a refresh. fn runs on the goroutine
+// that called Keepalive and may use the client.
+func (c *Client) WatchChanges(interval time.Duration, fn func(*ChangeEvent)) error {
+	if c == nil || c.plc == nil {
+		return fmt.Errorf("WatchChanges: nil client")
+	}
+	if interval <= 0 {
+		c.changes = nil
+		return nil
+	}
+
+	sig, err := c.plc.ReadChangeSignature()
+	if err != nil {
+		return fmt.Errorf("WatchChanges: %w", err)
+	}
+	c.changeSig = sig
+	c.changes = &changeWatch{interval: interval, fn: fn, lastCheck: time.Now()}
+	return nil
+}
+
+// checkChangesIfDue runs CheckForChanges when WatchChanges is active and
+// the interval has passed. Failures are logged and retried next interval.
+func (c *Client) checkChangesIfDue() {
+	w := c.changes
+	if w == nil || time.Since(w.lastCheck) < w.interval {
+		return
+	}
+	w.lastCheck = time.Now()
+
+	ev, err := c.CheckForChanges()
+	if err != nil {
+		debugLog("checkChangesIfDue: %v", err)
+		return
+	}
+	if ev != nil && w.fn != nil {
+		w.fn(ev)
+	}
+}
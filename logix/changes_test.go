@@ -0,0 +1,96 @@
+package logix
+
+import (
+	"encoding/binary"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// symbolEntry encodes one Get Instance Attribute List entry as
+// parseSymbolListResponse expects it.
+func symbolEntry(instance uint16, name string, typeCode uint16) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, instance)
+	b = append(b, 0, 0)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(name)))
+	b = append(b, name...)
+	b = binary.LittleEndian.AppendUint16(b, typeCode)
+	return append(b, make([]byte, 12)...)
+}
+
+func TestCheckForChanges(t *testing.T) {
+	var edited atomic.Bool
+	conn := startCIPServer(t, func(req []byte) []byte {
+		switch req[0] {
+		case SvcGetAttributeList:
+			if want := []byte{0x20, 0xAC, 0x24, 0x01}; !reflect.DeepEqual(req[2:6], want) {
+				t.Errorf("path = % X, want % X", req[2:6], want)
+			}
+			counter := byte(1)
+			if edited.Load() {
+				counter = 2
+			}
+			return []byte{0x83, 0, 0, 0, 0x01, 0x00, 0x0A, 0x00, 0x00, 0x00, counter, 0x00, 0x00, 0x00}
+		case SvcGetInstanceAttributeList:
+			resp := []byte{0xD5, 0, 0, 0}
+			resp = append(resp, symbolEntry(1, "Speed", TypeREAL)...)
+			if edited.Load() {
+				resp = append(resp, symbolEntry(2, "Count", TypeLINT)...)
+				resp = append(resp, symbolEntry(3, "Mode", TypeINT)...)
+			} else {
+				resp = append(resp, symbolEntry(2, "Count", TypeDINT)...)
+				resp = append(resp, symbolEntry(4, "Old", TypeBOOL)...)
+			}
+			return resp
+		}
+		return []byte{req[0] | 0x80, 0, StatusServiceNotSupport, 0}
+	})
+
+	c := &Client{plc: &PLC{Connection: conn}}
+	tags, err := c.AllTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetTags(tags)
+	c.templates = map[uint16]*Template{1: {ID: 1}}
+
+	var events []*ChangeEvent
+	if err := c.WatchChanges(time.Nanosecond, func(ev *ChangeEvent) { events = append(events, ev) }); err != nil {
+		t.Fatalf("WatchChanges: %v", err)
+	}
+
+	time.Sleep(time.Millisecond)
+	c.Keepalive()
+	if len(events) != 0 {
+		t.Fatalf("event without a change: %+v", events[0])
+	}
+
+	edited.Store(true)
+	time.Sleep(time.Millisecond)
+	c.Keepalive()
+	if len(events) != 1 {
+		t.Fatalf("%d events after an edit, want 1", len(events))
+	}
+	ev := events[0]
+	if ev.Err != nil {
+		t.Fatal(ev.Err)
+	}
+	if !reflect.DeepEqual(ev.Added, []string{"Mode"}) || !reflect.DeepEqual(ev.Removed, []string{"Old"}) ||
+		!reflect.DeepEqual(ev.Retyped, []string{"Count"}) {
+		t.Errorf("added %v removed %v retyped %v", ev.Added, ev.Removed, ev.Retyped)
+	}
+	if info, ok := c.GetTagInfo("Count"); !ok || info.TypeCode != TypeLINT {
+		t.Errorf("tag info not refreshed: %+v", info)
+	}
+	if c.templates != nil {
+		t.Error("template cache not cleared")
+	}
+
+	// The new state is the baseline for the next check
+	time.Sleep(time.Millisecond)
+	c.Keepalive()
+	if len(events) != 1 {
+		t.Errorf("%d events, want still 1", len(events))
+	}
+}
@@ -1,10 +1,14 @@
 package logix
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,6 +43,9 @@ type Client struct {
 	templateSizes   map[uint16]uint32  // Cache of template ID -> size in bytes
 	templates       map[uint16]*Template // Cache of template ID -> full template definition
 	failedTemplates map[uint16]bool    // Cache of template IDs that failed to fetch
+	changeSig       []byte             // Last change-detection signature (see CheckForChanges)
+	changes         *changeWatch       // Set by WatchChanges
+	allowClockSet   bool               // Permit SetClock
 }
 
 // options holds configuration options for Connect.
@@ -48,6 +55,7 @@ type options struct {
 	skipForwardOpen bool
 	micro800        bool
 	timeout         time.Duration
+	allowClockSet   bool
 }
 
 // Option is a functional option for Connect.
@@ -97,10 +105,27 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithAllowClockSet enables SetClock, which changes the controller's wall
+// clock. That affects timestamps, schedules and any logic that uses GSV
+// WallClockTime, so without it SetClock returns ErrClockSetNotAllowed.
+func WithAllowClockSet() Option {
+	return func(o *options) {
+		o.allowClockSet = true
+	}
+}
+
 // Connect establishes a connection to a Logix PLC at the given address.
 // It attempts to establish a CIP connection (Forward Open) for efficient messaging.
 // If Forward Open fails, it falls back to unconnected messaging with a warning.
 func Connect(address string, opts ...Option) (*Client, error) {
+	return ConnectContext(context.Background(), address, opts...)
+}
+
+// ConnectContext is like Connect but aborts the TCP dial, session
+// registration and Forward Open when ctx is cancelled or its deadline passes.
+// A Forward Open interrupted by ctx fails the connect rather than falling back
+// to unconnected messaging.
+func ConnectContext(ctx context.Context, address string, opts ...Option) (*Client, error) {
 	// Apply options
 	cfg := &options{}
 	for _, opt := range opts {
@@ -110,7 +135,7 @@ func Connect(address string, opts ...Option) (*Client, error) {
 	debugLog("Connect %s: slot=%d, micro800=%v, skipForwardOpen=%v", address, cfg.slot, cfg.micro800, cfg.skipForwardOpen)
 
 	// Create low-level PLC connection
-	plc, err := NewPLC(address, cfg.timeout)
+	plc, err := NewPLCContext(ctx, address, cfg.timeout)
 	if err != nil {
 		return nil, fmt.Errorf("Connect: %w", err)
 	}
@@ -124,13 +149,21 @@ func Connect(address string, opts ...Option) (*Client, error) {
 
 	// Attempt Forward Open for connected messaging
 	if !cfg.skipForwardOpen {
-		err = plc.OpenConnection()
+		release, err := plc.Connection.BindContext(ctx)
+		if err == nil {
+			err = plc.OpenConnection()
+			release()
+		}
 		if err != nil {
+			if ctx.Err() != nil {
+				plc.Close()
+				return nil, fmt.Errorf("Connect: %w: %w", ctx.Err(), err)
+			}
 			debugLog("Warning: Forward Open failed, using unconnected messaging: %v", err)
 		}
 	}
 
-	return &Client{plc: &plc, micro800: cfg.micro800}, nil
+	return &Client{plc: &plc, micro800: cfg.micro800, allowClockSet: cfg.allowClockSet}, nil
 }
 
 // Close releases all resources associated with the client.
@@ -179,10 +212,12 @@ func (c *Client) ConnectionMode() string {
 // the CIP ForwardOpen connection alive. Should be called periodically
 // when no other operations are being performed to prevent connection timeout.
 // Returns nil if not using connected messaging.
+// Also runs the WatchChanges check when it is due.
 func (c *Client) Keepalive() error {
 	if c == nil || c.plc == nil {
 		return nil
 	}
+	c.checkChangesIfDue()
 	return c.plc.Keepalive()
 }
 
@@ -987,11 +1022,26 @@ func (c *Client) findMemberType(tmpl *Template, memberPath string) uint16 {
 
 // Write writes a value to a tag. If the tag's type is known from discovery,
 // the value is converted to match. Otherwise, the type is inferred from the Go value type.
+// Integer bit paths ("MyDint.5") are written with Read Modify Write, which
+// changes only that bit. A map[string]interface{} value is written to a
+// structure tag with WriteStruct.
 func (c *Client) Write(tagName string, value interface{}) error {
 	if c == nil || c.plc == nil {
 		return fmt.Errorf("Write: nil client")
 	}
 
+	// Single bits go through Read Modify Write so the rest of the integer
+	// holding them is left as the PLC has it
+	if host, size, bit, ok := c.bitTarget(tagName); ok {
+		logging.DebugLog("logix", "Write %s: bit %d of %s (%d bytes), value=%v", tagName, bit, host, size, value)
+		return c.writeBit(host, size, bit, value)
+	}
+
+	// Maps of member values are whole structures
+	if _, ok := value.(map[string]interface{}); ok {
+		return c.WriteStruct(tagName, value)
+	}
+
 	// For UDT member access (path contains dot after base tag), look up type from template
 	// This is more reliable than tagInfo which may have incorrect types for UDT members
 	if memberType := c.getMemberTypeFromTemplate(tagName); memberType != 0 {
@@ -1046,6 +1096,75 @@ func (c *Client) writeTyped(tagName string, value interface{}, targetType uint16
 	return err
 }
 
+// bitTarget resolves a bit-level tag path to the integer holding the bit.
+// It recognizes "Tag.5" on a SINT/INT/DINT/LINT (signed or unsigned) tag or
+// member. BOOL members of UDTs are not bit targets: the SINT the PLC packs
+// them into is hidden from symbolic access, so they are written as BOOLs.
+// Returns ok=false if the path is not a bit or the types are unknown.
+func (c *Client) bitTarget(tagName string) (host string, size int, bit int, ok bool) {
+	dotIdx := strings.LastIndex(tagName, ".")
+	if dotIdx <= 0 {
+		return "", 0, 0, false
+	}
+	base, last := tagName[:dotIdx], tagName[dotIdx+1:]
+
+	// Numeric bit index on an integer: "MyDint.5"
+	n, err := strconv.Atoi(last)
+	if err != nil {
+		return "", 0, 0, false
+	}
+	baseType := c.getMemberTypeFromTemplate(base)
+	if baseType == 0 {
+		if info, found := c.tagInfo[base]; found && !info.IsArray() {
+			baseType = info.TypeCode
+		} else if idx := strings.Index(base, "["); idx > 0 && strings.HasSuffix(base, "]") {
+			// Element of an integer array: "MyDints[3].5"
+			if info, found := c.tagInfo[base[:idx]]; found {
+				baseType = info.TypeCode &^ TypeArrayMask
+			}
+		}
+	}
+	if IsStructure(baseType) || IsArray(baseType) {
+		return "", 0, 0, false
+	}
+	switch baseType & 0x0FFF {
+	case TypeSINT, TypeINT, TypeDINT, TypeLINT, TypeUSINT, TypeUINT, TypeUDINT, TypeULINT:
+		size = TypeSize(baseType)
+		if n < 0 || n >= size*8 {
+			return "", 0, 0, false
+		}
+		return base, size, n, true
+	}
+	return "", 0, 0, false
+}
+
+// writeBit sets or clears one bit of an integer with Read Modify Write.
+func (c *Client) writeBit(host string, size, bit int, value interface{}) error {
+	orMask, andMask, err := c.bitMasks(size, bit, value)
+	if err != nil {
+		return fmt.Errorf("Write %s: %w", host, err)
+	}
+	return c.plc.ReadModifyWriteTag(host, orMask, andMask)
+}
+
+// bitMasks builds the Read Modify Write masks that set (value true) or
+// clear one bit of a size-byte integer.
+func (c *Client) bitMasks(size, bit int, value interface{}) (orMask, andMask []byte, err error) {
+	data, err := c.convertToType(value, TypeBOOL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orMask = make([]byte, size)
+	andMask = bytes.Repeat([]byte{0xFF}, size)
+	if data[0] != 0 {
+		orMask[bit/8] |= 1 << (bit % 8)
+	} else {
+		andMask[bit/8] &^= 1 << (bit % 8)
+	}
+	return orMask, andMask, nil
+}
+
 // isSliceType returns true if the value is a slice type that should be written as an array.
 func isSliceType(value interface{}) bool {
 	switch value.(type) {
@@ -1295,6 +1414,16 @@ func (c *Client) convertToType(value interface{}, targetType uint16) ([]byte, er
 
 // writeArrayTyped writes an array value with the specified element type.
 func (c *Client) writeArrayTyped(tagName string, value interface{}, elemType uint16) error {
+	data, count, err := c.encodeArrayTyped(value, elemType)
+	if err != nil {
+		return err
+	}
+	return c.plc.WriteTagCount(tagName, elemType, data, uint16(count))
+}
+
+// encodeArrayTyped converts a slice to array data of elemType and returns
+// the data and element count.
+func (c *Client) encodeArrayTyped(value interface{}, elemType uint16) ([]byte, int, error) {
 	var data []byte
 	var count int
 
@@ -1310,7 +1439,7 @@ func (c *Client) writeArrayTyped(tagName string, value interface{}, elemType uin
 		for _, val := range v {
 			elem, err := c.convertToType(val, elemType)
 			if err != nil {
-				return err
+				return nil, 0, err
 			}
 			data = append(data, elem...)
 		}
@@ -1319,7 +1448,7 @@ func (c *Client) writeArrayTyped(tagName string, value interface{}, elemType uin
 		for _, val := range v {
 			elem, err := c.convertToType(val, elemType)
 			if err != nil {
-				return err
+				return nil, 0, err
 			}
 			data = append(data, elem...)
 		}
@@ -1328,7 +1457,7 @@ func (c *Client) writeArrayTyped(tagName string, value interface{}, elemType uin
 		for _, val := range v {
 			elem, err := c.convertToType(val, elemType)
 			if err != nil {
-				return err
+				return nil, 0, err
 			}
 			data = append(data, elem...)
 		}
@@ -1337,7 +1466,7 @@ func (c *Client) writeArrayTyped(tagName string, value interface{}, elemType uin
 		for _, val := range v {
 			elem, err := c.convertToType(val, elemType)
 			if err != nil {
-				return err
+				return nil, 0, err
 			}
 			data = append(data, elem...)
 		}
@@ -1346,7 +1475,7 @@ func (c *Client) writeArrayTyped(tagName string, value interface{}, elemType uin
 		for _, val := range v {
 			elem, err := c.convertToType(val, elemType)
 			if err != nil {
-				return err
+				return nil, 0, err
 			}
 			// For STRING arrays, pad each element to 88 bytes (4-byte len + 84 chars)
 			if elemType == TypeSTRING {
@@ -1357,18 +1486,28 @@ func (c *Client) writeArrayTyped(tagName string, value interface{}, elemType uin
 			data = append(data, elem...)
 		}
 	default:
-		return fmt.Errorf("unsupported array type %T", value)
+		return nil, 0, fmt.Errorf("unsupported array type %T", value)
 	}
 
 	if count == 0 {
-		return fmt.Errorf("empty array")
+		return nil, 0, fmt.Errorf("empty array")
 	}
 
-	return c.plc.WriteTagCount(tagName, elemType, data, uint16(count))
+	return data, count, nil
 }
 
 // writeInferred writes using type inferred from the Go value (fallback when tag type unknown).
 func (c *Client) writeInferred(tagName string, value interface{}) error {
+	dataType, data, count, err := encodeInferred(value)
+	if err != nil {
+		return err
+	}
+	return c.plc.WriteTagCount(tagName, dataType, data, uint16(count))
+}
+
+// encodeInferred converts a Go value to tag data, inferring the CIP type
+// from the Go type. Returns the type, data and element count.
+func encodeInferred(value interface{}) (uint16, []byte, int, error) {
 	var dataType uint16
 	var data []byte
 
@@ -1437,7 +1576,7 @@ func (c *Client) writeInferred(tagName string, value interface{}) error {
 
 	case []bool:
 		if len(v) == 0 {
-			return fmt.Errorf("Write: empty array")
+			return 0, nil, 0, fmt.Errorf("Write: empty array")
 		}
 		dataType = TypeBOOL
 		for _, val := range v {
@@ -1447,51 +1586,51 @@ func (c *Client) writeInferred(tagName string, value interface{}) error {
 				data = append(data, 0)
 			}
 		}
-		return c.plc.WriteTagCount(tagName, dataType, data, uint16(len(v)))
+		return dataType, data, len(v), nil
 
 	case []int32:
 		if len(v) == 0 {
-			return fmt.Errorf("Write: empty array")
+			return 0, nil, 0, fmt.Errorf("Write: empty array")
 		}
 		dataType = TypeDINT
 		for _, val := range v {
 			data = binary.LittleEndian.AppendUint32(data, uint32(val))
 		}
-		return c.plc.WriteTagCount(tagName, dataType, data, uint16(len(v)))
+		return dataType, data, len(v), nil
 
 	case []int64:
 		if len(v) == 0 {
-			return fmt.Errorf("Write: empty array")
+			return 0, nil, 0, fmt.Errorf("Write: empty array")
 		}
 		dataType = TypeDINT
 		for _, val := range v {
 			data = binary.LittleEndian.AppendUint32(data, uint32(val))
 		}
-		return c.plc.WriteTagCount(tagName, dataType, data, uint16(len(v)))
+		return dataType, data, len(v), nil
 
 	case []float32:
 		if len(v) == 0 {
-			return fmt.Errorf("Write: empty array")
+			return 0, nil, 0, fmt.Errorf("Write: empty array")
 		}
 		dataType = TypeREAL
 		for _, val := range v {
 			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(val))
 		}
-		return c.plc.WriteTagCount(tagName, dataType, data, uint16(len(v)))
+		return dataType, data, len(v), nil
 
 	case []float64:
 		if len(v) == 0 {
-			return fmt.Errorf("Write: empty array")
+			return 0, nil, 0, fmt.Errorf("Write: empty array")
 		}
 		dataType = TypeREAL
 		for _, val := range v {
 			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(val)))
 		}
-		return c.plc.WriteTagCount(tagName, dataType, data, uint16(len(v)))
+		return dataType, data, len(v), nil
 
 	case []string:
 		if len(v) == 0 {
-			return fmt.Errorf("Write: empty array")
+			return 0, nil, 0, fmt.Errorf("Write: empty array")
 		}
 		dataType = TypeSTRING
 		for _, s := range v {
@@ -1506,20 +1645,105 @@ func (c *Client) writeInferred(tagName string, value interface{}) error {
 			}
 			data = append(data, elem...)
 		}
-		return c.plc.WriteTagCount(tagName, dataType, data, uint16(len(v)))
+		return dataType, data, len(v), nil
 
 	default:
-		return fmt.Errorf("Write: unsupported value type %T", value)
+		return 0, nil, 0, fmt.Errorf("Write: unsupported value type %T", value)
 	}
 
-	return c.plc.WriteTag(tagName, dataType, data)
+	return dataType, data, 1, nil
 }
 
-// WriteBool writes a boolean value to a tag.
+// WriteMany writes several tags, packing them into Multiple Service Packets
+// sized to the connection. Values are converted the same way as by Write,
+// including Read Modify Write for bit paths. The result maps each tag to its
+// error (nil on success); the error return is set only when a packet could
+// not be exchanged. Micro800 controllers are written one tag at a time.
+func (c *Client) WriteMany(values map[string]interface{}) (map[string]error, error) {
+	if c == nil || c.plc == nil {
+		return nil, fmt.Errorf("WriteMany: nil client")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make(map[string]error, len(names))
+	if c.micro800 {
+		for _, name := range names {
+			results[name] = c.Write(name, values[name])
+		}
+		return results, c.connErrorIfDown()
+	}
+
+	var writes []TagWrite
+	var writeNames []string
+	for _, name := range names {
+		w, err := c.encodeWrite(name, values[name])
+		if err != nil {
+			results[name] = fmt.Errorf("%s: %w", name, err)
+			continue
+		}
+		writes = append(writes, w)
+		writeNames = append(writeNames, name)
+	}
+
+	errs, err := c.plc.WriteMultiple(writes)
+	for i, name := range writeNames {
+		if i < len(errs) {
+			results[name] = errs[i]
+		} else {
+			results[name] = err
+		}
+	}
+	return results, err
+}
+
+// encodeWrite converts a value for tagName the way Write does, without
+// sending it.
+func (c *Client) encodeWrite(tagName string, value interface{}) (TagWrite, error) {
+	if host, size, bit, ok := c.bitTarget(tagName); ok {
+		orMask, andMask, err := c.bitMasks(size, bit, value)
+		return TagWrite{Name: host, OrMask: orMask, AndMask: andMask}, err
+	}
+
+	if _, ok := value.(map[string]interface{}); ok {
+		handle, data, err := c.encodeStructValue(tagName, value)
+		return TagWrite{Name: tagName, DataType: CIPStructType, StructHandle: handle, Count: 1, Data: data}, err
+	}
+
+	targetType := c.getMemberTypeFromTemplate(tagName)
+	if targetType == 0 {
+		if info, ok := c.tagInfo[tagName]; ok {
+			targetType = info.TypeCode
+		}
+	}
+	if targetType == 0 {
+		dataType, data, count, err := encodeInferred(value)
+		return TagWrite{Name: tagName, DataType: dataType, Count: uint16(count), Data: data}, err
+	}
+
+	baseType := targetType & 0x0FFF
+	if IsArray(targetType) || isSliceType(value) {
+		data, count, err := c.encodeArrayTyped(value, baseType)
+		return TagWrite{Name: tagName, DataType: baseType, Count: uint16(count), Data: data}, err
+	}
+	data, err := c.convertToType(value, baseType)
+	return TagWrite{Name: tagName, DataType: baseType, Count: 1, Data: data}, err
+}
+
+// WriteBool writes a boolean value to a tag. Bits of integers ("MyDint.5")
+// are written with Read Modify Write; BOOL members of UDTs are plain BOOL
+// writes.
 func (c *Client) WriteBool(tagName string, val bool) error {
 	if c == nil || c.plc == nil {
 		return fmt.Errorf("WriteBool: nil client")
 	}
+	if host, size, bit, ok := c.bitTarget(tagName); ok {
+		return c.writeBit(host, size, bit, val)
+	}
 	data := []byte{0}
 	if val {
 		data[0] = 1
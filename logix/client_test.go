@@ -1,8 +1,14 @@
 package logix
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"net"
 	"testing"
+
+	"github.com/yatesdr/plcio/eip"
 )
 
 // When the underlying transport is down, the batch read paths must surface
@@ -29,3 +35,245 @@ func TestConnErrorIfDownNilPLC(t *testing.T) {
 		t.Fatalf("expected nil error when plc is nil, got %v", err)
 	}
 }
+
+func TestBitTarget(t *testing.T) {
+	c := &Client{
+		plc: &PLC{},
+		tagInfo: map[string]TagInfo{
+			"MyDint":  {Name: "MyDint", TypeCode: TypeDINT},
+			"MyInts":  {Name: "MyInts", TypeCode: TypeINT | TypeArrayMask, Dimensions: []int{10}},
+			"MyReal":  {Name: "MyReal", TypeCode: TypeREAL},
+			"Station": {Name: "Station", TypeCode: TypeStructureMask | 0x0042},
+		},
+		templates: map[uint16]*Template{0x0042: {
+			ID:   0x0042,
+			Size: 8,
+			Members: []TemplateMember{
+				{Name: "ZZZZZZZZZZStation0", Type: TypeSINT, Offset: 0},
+				{Name: "Running", Type: TypeBOOL, Offset: 0, BitOffset: 0},
+				{Name: "Faulted", Type: TypeBOOL, Offset: 0, BitOffset: 1},
+				{Name: "Count", Type: TypeDINT, Offset: 4},
+			},
+			MemberMap: map[string]int{"ZZZZZZZZZZStation0": 0, "Running": 1, "Faulted": 2, "Count": 3},
+		}},
+	}
+
+	tests := []struct {
+		tag  string
+		host string
+		size int
+		bit  int
+		ok   bool
+	}{
+		{"MyDint.5", "MyDint", 4, 5, true},
+		{"MyDint.32", "", 0, 0, false},
+		{"MyInts[3].15", "MyInts[3]", 2, 15, true},
+		{"MyReal.1", "", 0, 0, false},
+		{"Station.Faulted", "", 0, 0, false}, // UDT BOOLs are written as BOOLs
+		{"Station.Count.31", "Station.Count", 4, 31, true},
+		{"Station.Count", "", 0, 0, false},
+		{"Unknown.3", "", 0, 0, false},
+		{"MyDint", "", 0, 0, false},
+	}
+	for _, tt := range tests {
+		host, size, bit, ok := c.bitTarget(tt.tag)
+		if host != tt.host || size != tt.size || bit != tt.bit || ok != tt.ok {
+			t.Errorf("bitTarget(%q) = %q, %d, %d, %v; want %q, %d, %d, %v",
+				tt.tag, host, size, bit, ok, tt.host, tt.size, tt.bit, tt.ok)
+		}
+	}
+}
+
+func TestBuildReadModifyWriteRequest(t *testing.T) {
+	req, err := buildReadModifyWriteRequest("MyDint", []byte{0x20, 0, 0, 0}, []byte{0xFF, 0xFF, 0xFF, 0xFF})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		0x4E, 0x04, 0x91, 0x06, 'M', 'y', 'D', 'i', 'n', 't',
+		0x04, 0x00,
+		0x20, 0x00, 0x00, 0x00,
+		0xFF, 0xFF, 0xFF, 0xFF,
+	}
+	if !bytes.Equal(req, want) {
+		t.Errorf("request = % X, want % X", req, want)
+	}
+
+	if _, err := buildReadModifyWriteRequest("MyDint", []byte{0}, []byte{0, 0}); err == nil {
+		t.Error("mismatched masks accepted")
+	}
+	if _, err := buildReadModifyWriteRequest("MyDint", []byte{0, 0, 0}, []byte{0, 0, 0}); err == nil {
+		t.Error("3-byte mask accepted")
+	}
+}
+
+func TestParseReadModifyWriteResponse(t *testing.T) {
+	if err := parseReplyStatus([]byte{0xCE, 0x00, 0x00, 0x00}, SvcReadModifyWriteTag); err != nil {
+		t.Errorf("success reply: %v", err)
+	}
+	if err := parseReplyStatus([]byte{0xCD, 0x00, 0x00, 0x00}, SvcReadModifyWriteTag); err == nil {
+		t.Error("Write Tag reply accepted for Read Modify Write")
+	}
+	if err := parseReplyStatus([]byte{0xCE, 0x00, 0x05, 0x00}, SvcReadModifyWriteTag); err == nil {
+		t.Error("path error not reported")
+	}
+}
+
+// startCIPServer serves one EtherNet/IP session on 127.0.0.1, answering
+// each unconnected CIP request with handle's reply.
+func startCIPServer(t *testing.T, handle func(req []byte) []byte) *eip.EipClient {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			f, err := eip.ReadFrame(conn)
+			if err != nil {
+				return
+			}
+			switch f.Command {
+			case eip.RegisterSession:
+				reply := f.Reply(eip.EncapStatusSuccess, f.Data)
+				reply.SessionHandle = 0x1234
+				conn.Write(reply.Bytes())
+			case eip.SendRRData:
+				cpfBytes, _ := eip.ParseRRData(f.Data)
+				pkt, err := eip.ParseEipCommonPacket(cpfBytes)
+				if err != nil || len(pkt.Items) < 2 {
+					return
+				}
+				resp := handle(pkt.Items[1].Data)
+				cpf := eip.EipCommonPacket{Items: []eip.EipCommonPacketItem{
+					{TypeId: eip.CpfAddressNullId},
+					{TypeId: eip.CpfUnconnectedMessageId, Length: uint16(len(resp)), Data: resp},
+				}}
+				conn.Write(f.Reply(eip.EncapStatusSuccess, eip.BuildRRData(cpf.Bytes())).Bytes())
+			default:
+				return
+			}
+		}
+	}()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	client := eip.NewEipClientWithPort("127.0.0.1", uint16(port))
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+	return client
+}
+
+func TestWriteManyPacksServices(t *testing.T) {
+	var packets [][]string
+	var rmw []byte
+	conn := startCIPServer(t, func(req []byte) []byte {
+		if req[0] != SvcMultipleServicePacket {
+			t.Errorf("service 0x%02X, want Multiple Service Packet", req[0])
+			return []byte{req[0] | 0x80, 0, 0x08, 0}
+		}
+		ms := req[2+int(req[1])*2:]
+		n := int(binary.LittleEndian.Uint16(ms[0:2]))
+		reply := binary.LittleEndian.AppendUint16(nil, uint16(n))
+		var bodies []byte
+		var names []string
+		for i := 0; i < n; i++ {
+			off := int(binary.LittleEndian.Uint16(ms[2+i*2:]))
+			svc := ms[off]
+			pathLen := int(ms[off+1]) * 2
+			path := ms[off+2 : off+2+pathLen]
+			name := string(path[2 : 2+int(path[1])])
+			names = append(names, name)
+			if svc == SvcReadModifyWriteTag {
+				rmw = append([]byte{}, ms[off+2+pathLen:off+2+pathLen+10]...)
+			}
+			status := byte(0)
+			if name == "Tag_07" {
+				status = StatusPathUnknown
+			}
+			reply = binary.LittleEndian.AppendUint16(reply, uint16(2+2*n+len(bodies)))
+			bodies = append(bodies, svc|0x80, 0, status, 0)
+		}
+		packets = append(packets, names)
+		return append(append([]byte{SvcMultipleServicePacket | 0x80, 0, 0, 0}, reply...), bodies...)
+	})
+
+	c := &Client{
+		plc:     &PLC{Connection: conn},
+		tagInfo: map[string]TagInfo{"Flags": {Name: "Flags", TypeCode: TypeDINT}},
+	}
+	values := map[string]interface{}{"Flags.3": true}
+	for i := 0; i < 30; i++ {
+		values[fmt.Sprintf("Tag_%02d", i)] = int32(i)
+	}
+
+	results, err := c.WriteMany(values)
+	if err != nil {
+		t.Fatalf("WriteMany: %v", err)
+	}
+	if len(results) != len(values) {
+		t.Fatalf("%d results, want %d", len(results), len(values))
+	}
+	for name, err := range results {
+		if (name == "Tag_07") != (err != nil) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+
+	// 30 DINT writes of 20 bytes each plus the bit write don't fit the
+	// 480-byte unconnected budget in one packet
+	if len(packets) != 2 {
+		t.Fatalf("%d packets, want 2", len(packets))
+	}
+	if packets[0][0] != "Flags" {
+		t.Errorf("first service writes %q, want the Flags bit", packets[0][0])
+	}
+	wantRMW := []byte{0x04, 0x00, 0x08, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF}
+	if !bytes.Equal(rmw, wantRMW) {
+		t.Errorf("Read Modify Write data = % X, want % X", rmw, wantRMW)
+	}
+}
+
+func TestWriteMultipleContinuesAfterRejectedPacket(t *testing.T) {
+	var sent []string
+	conn := startCIPServer(t, func(req []byte) []byte {
+		if req[0] == SvcWriteTag {
+			sent = append(sent, "single")
+			return []byte{SvcWriteTag | 0x80, 0, 0, 0}
+		}
+		ms := req[2+int(req[1])*2:]
+		off := int(binary.LittleEndian.Uint16(ms[2:]))
+		name := string(ms[off+4 : off+4+int(ms[off+3])])
+		sent = append(sent, name)
+		if name == "Rejected" {
+			// The whole packet is refused
+			return []byte{SvcMultipleServicePacket | 0x80, 0, 0x02, 0}
+		}
+		return []byte{SvcMultipleServicePacket | 0x80, 0, 0, 0, 1, 0, 4, 0, SvcWriteTag | 0x80, 0, 0, 0}
+	})
+
+	p := &PLC{Connection: conn}
+	errs, err := p.WriteMultiple([]TagWrite{
+		{Name: "Rejected", DataType: TypeDINT, Data: make([]byte, 4)},
+		{Name: "Big", DataType: TypeDINT, Count: 120, Data: make([]byte, 480)},
+		{Name: "After", DataType: TypeDINT, Data: make([]byte, 4)},
+	})
+	if err != nil {
+		t.Fatalf("WriteMultiple: %v", err)
+	}
+	if errs[0] == nil || errs[1] != nil || errs[2] != nil {
+		t.Errorf("errs = %v, want only the rejected packet's write to fail", errs)
+	}
+	want := []string{"Rejected", "single", "After"}
+	if fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Errorf("requests %v, want %v", sent, want)
+	}
+}
@@ -0,0 +1,119 @@
+package logix
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yatesdr/plcio/cip"
+)
+
+// WallClockTime object and the attributes used to read and set the
+// controller clock. Both values are LINT microseconds since 1970-01-01.
+const (
+	SvcSetAttributeList byte = 0x04
+
+	classWallClock byte = 0x8B
+
+	attrClockUTC   uint16 = 0x06 // Current time in UTC
+	attrClockLocal uint16 = 0x0B // Current time with the controller's UTC offset and DST applied
+)
+
+// ErrClockSetNotAllowed is returned by SetClock unless the client was
+// connected with WithAllowClockSet.
+var ErrClockSetNotAllowed = errors.New("logix: setting the controller clock not enabled (use WithAllowClockSet)")
+
+// ControllerClock is the controller's wall clock.
+type ControllerClock struct {
+	UTC    time.Time     // Controller time in UTC
+	Local  time.Time     // Controller local time, in a zone with the controller's offset
+	Offset time.Duration // Local minus UTC: the configured UTC offset plus any DST adjustment
+}
+
+// readClock reads the UTC and local values of the controller's wall clock.
+func (p *PLC) readClock() (*ControllerClock, error) {
+	path, err := cip.EPath().Class(classWallClock).Instance(1).Build()
+	if err != nil {
+		return nil, err
+	}
+	data, err := p.getAttributeList(path, []uint16{attrClockUTC, attrClockLocal})
+	if err != nil {
+		return nil, err
+	}
+
+	// [count:2] then per attribute [attr_id:2] [status:2] [value:8]
+	if len(data) < 2+2*12 {
+		return nil, fmt.Errorf("response too short for attribute data")
+	}
+	var micros [2]int64
+	for i := range micros {
+		attr := data[2+i*12:]
+		if st := binary.LittleEndian.Uint16(attr[2:4]); st != 0 {
+			return nil, fmt.Errorf("attribute 0x%02X error status: 0x%04X", binary.LittleEndian.Uint16(attr[0:2]), st)
+		}
+		micros[i] = int64(binary.LittleEndian.Uint64(attr[4:12]))
+	}
+
+	utc := time.UnixMicro(micros[0]).UTC()
+	offset := time.Duration(micros[1]-micros[0]) * time.Microsecond
+	// Round away the few microseconds the controller takes between the two values
+	offset = offset.Round(time.Minute)
+	return &ControllerClock{
+		UTC:    utc,
+		Local:  utc.In(time.FixedZone("", int(offset/time.Second))),
+		Offset: offset,
+	}, nil
+}
+
+// ReadClock reads the controller's wall clock. The local time and offset
+// come from the time zone and DST settings in the controller.
+func (c *Client) ReadClock() (*ControllerClock, error) {
+	if c == nil || c.plc == nil {
+		return nil, fmt.Errorf("ReadClock: nil client")
+	}
+	clock, err := c.plc.readClock()
+	if err != nil {
+		return nil, fmt.Errorf("ReadClock: %w", err)
+	}
+	return clock, nil
+}
+
+// SetClock sets the controller's wall clock to t. The clock is set in UTC,
+// so t's location does not matter: the controller derives local time from
+// its own UTC offset and DST settings, which SetClock leaves unchanged.
+// Requires WithAllowClockSet.
+func (c *Client) SetClock(t time.Time) error {
+	if c == nil || c.plc == nil {
+		return fmt.Errorf("SetClock: nil client")
+	}
+	if !c.allowClockSet {
+		return ErrClockSetNotAllowed
+	}
+
+	path, err := cip.EPath().Class(classWallClock).Instance(1).Build()
+	if err != nil {
+		return fmt.Errorf("SetClock: %w", err)
+	}
+
+	reqData := make([]byte, 0, 2+len(path)+12)
+	reqData = append(reqData, SvcSetAttributeList)
+	reqData = append(reqData, path.WordLen())
+	reqData = append(reqData, path...)
+	reqData = binary.LittleEndian.AppendUint16(reqData, 1)
+	reqData = binary.LittleEndian.AppendUint16(reqData, attrClockUTC)
+	reqData = binary.LittleEndian.AppendUint64(reqData, uint64(t.UnixMicro()))
+
+	data, err := c.plc.sendAttributeRequest(reqData)
+	if err != nil {
+		return fmt.Errorf("SetClock: %w", err)
+	}
+
+	// [count:2] [attr_id:2] [status:2]
+	if len(data) >= 6 {
+		if st := binary.LittleEndian.Uint16(data[4:6]); st != 0 {
+			return fmt.Errorf("SetClock: attribute error status: 0x%04X", st)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package logix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+// clockReply builds a Get Attribute List reply for the WallClockTime UTC and
+// local attributes.
+func clockReply(utc time.Time, offset time.Duration) []byte {
+	resp := []byte{0x83, 0, 0, 0, 0x02, 0x00}
+	resp = binary.LittleEndian.AppendUint16(resp, attrClockUTC)
+	resp = append(resp, 0, 0)
+	resp = binary.LittleEndian.AppendUint64(resp, uint64(utc.UnixMicro()))
+	resp = binary.LittleEndian.AppendUint16(resp, attrClockLocal)
+	resp = append(resp, 0, 0)
+	return binary.LittleEndian.AppendUint64(resp, uint64(utc.Add(offset).UnixMicro()))
+}
+
+func TestReadClock(t *testing.T) {
+	utc := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	conn := startCIPServer(t, func(req []byte) []byte {
+		return clockReply(utc, -4*time.Hour) // EST with DST
+	})
+
+	c := &Client{plc: &PLC{Connection: conn}}
+	clock, err := c.ReadClock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !clock.UTC.Equal(utc) || clock.Offset != -4*time.Hour {
+		t.Errorf("UTC %v offset %v", clock.UTC, clock.Offset)
+	}
+	if clock.Local.Hour() != 8 {
+		t.Errorf("local %v, want 08:00", clock.Local)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	var got []byte
+	conn := startCIPServer(t, func(req []byte) []byte {
+		got = append([]byte{}, req...)
+		return []byte{0x84, 0, 0, 0, 0x01, 0x00, 0x06, 0x00, 0x00, 0x00}
+	})
+
+	c := &Client{plc: &PLC{Connection: conn}}
+	when := time.Date(2026, 7, 1, 8, 0, 0, 0, time.FixedZone("EDT", -4*3600))
+	if err := c.SetClock(when); !errors.Is(err, ErrClockSetNotAllowed) {
+		t.Fatalf("SetClock without WithAllowClockSet: %v", err)
+	}
+	if got != nil {
+		t.Fatal("request sent without WithAllowClockSet")
+	}
+
+	c.allowClockSet = true
+	if err := c.SetClock(when); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{SvcSetAttributeList, 0x02, 0x20, 0x8B, 0x24, 0x01, 0x01, 0x00, 0x06, 0x00}
+	want = binary.LittleEndian.AppendUint64(want, uint64(when.UTC().UnixMicro()))
+	if !bytes.Equal(got, want) {
+		t.Errorf("request % X, want % X", got, want)
+	}
+}
@@ -408,6 +408,254 @@ func (p *PLC) ReadMultiple(tagNames []string) ([]*Tag, error) {
 	return tags, nil
 }
 
+// TagWrite is one write in WriteMultiple. Data holds Count elements of
+// DataType. When OrMask and AndMask are set instead, the write is a Read
+// Modify Write of those bits (see ReadModifyWriteTag).
+type TagWrite struct {
+	Name     string
+	DataType uint16
+	Count    uint16 // Element count; 0 means 1
+	Data     []byte
+
+	StructHandle uint16 // Structure handle, sent after DataType when it is CIPStructType
+
+	OrMask  []byte
+	AndMask []byte
+}
+
+// WriteMultiple writes several tags with Multiple Service Packets, packing
+// as many writes into each packet as the connection size allows. A write too
+// large to share a packet is sent on its own. It returns one error per write
+// in order (nil on success); a packet the PLC rejects as a whole marks each
+// of its writes. The error return is set only when a request could not be
+// exchanged; writes from that request on are then marked with it too.
+func (p *PLC) WriteMultiple(writes []TagWrite) ([]error, error) {
+	if p == nil || p.Connection == nil {
+		return nil, fmt.Errorf("WriteMultiple: nil plc or connection")
+	}
+	errs := make([]error, len(writes))
+	if len(writes) == 0 {
+		return errs, nil
+	}
+
+	requests := make([]cip.MultiServiceRequest, len(writes))
+	for i, w := range writes {
+		req, err := buildWriteService(w)
+		if err != nil {
+			errs[i] = fmt.Errorf("WriteMultiple: tag %q: %w", w.Name, err)
+			continue
+		}
+		requests[i] = req
+	}
+
+	// Same budget as chunked reads: connection size less protocol overhead
+	maxRequest := 480
+	if p.connSize > 0 {
+		maxRequest = int(p.connSize) - 100
+	}
+
+	// Multiple Service Packet header: service, path size, Message Router
+	// path, service count
+	const mspHeader = 2 + 4 + 2
+	var batch []int
+	size := mspHeader
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		reqs := make([]cip.MultiServiceRequest, len(batch))
+		for k, idx := range batch {
+			reqs[k] = requests[idx]
+		}
+		responses, rejected, err := p.exchangeMultipleService(reqs)
+		if err != nil {
+			return err
+		}
+		for k, idx := range batch {
+			if rejected != nil {
+				errs[idx] = fmt.Errorf("WriteMultiple: tag %q: %w", writes[idx].Name, rejected)
+			} else {
+				errs[idx] = writeServiceError(responses[k], requests[idx].Service)
+			}
+		}
+		batch = batch[:0]
+		size = mspHeader
+		return nil
+	}
+
+	for i := range writes {
+		if errs[i] != nil {
+			continue
+		}
+		svcSize := 2 + 2 + len(requests[i].Path) + len(requests[i].Data) // offset + service
+		if mspHeader+svcSize > maxRequest {
+			// Too large to share a packet: send it alone, after the writes before it
+			if err := flush(); err != nil {
+				return failFrom(errs, batch[0], err), fmt.Errorf("WriteMultiple: %w", err)
+			}
+			svcErr, err := p.sendSingleService(requests[i])
+			if err != nil {
+				return failFrom(errs, i, err), fmt.Errorf("WriteMultiple: %w", err)
+			}
+			errs[i] = svcErr
+			continue
+		}
+		if len(batch) > 0 && (size+svcSize > maxRequest || len(batch) == 200) {
+			if err := flush(); err != nil {
+				return failFrom(errs, batch[0], err), fmt.Errorf("WriteMultiple: %w", err)
+			}
+		}
+		batch = append(batch, i)
+		size += svcSize
+	}
+	if err := flush(); err != nil {
+		return failFrom(errs, batch[0], err), fmt.Errorf("WriteMultiple: %w", err)
+	}
+	return errs, nil
+}
+
+// failFrom sets err on every write from index start on that has no error yet.
+func failFrom(errs []error, start int, err error) []error {
+	for i := start; i < len(errs); i++ {
+		if errs[i] == nil {
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+// buildWriteService builds the Write Tag or Read Modify Write Tag service
+// for one TagWrite.
+func buildWriteService(w TagWrite) (cip.MultiServiceRequest, error) {
+	path, err := cip.EPath().Symbol(w.Name).Build()
+	if err != nil {
+		return cip.MultiServiceRequest{}, err
+	}
+
+	if w.OrMask != nil || w.AndMask != nil {
+		data, err := readModifyWriteData(w.OrMask, w.AndMask)
+		if err != nil {
+			return cip.MultiServiceRequest{}, err
+		}
+		return cip.MultiServiceRequest{Service: SvcReadModifyWriteTag, Path: path, Data: data}, nil
+	}
+
+	count := w.Count
+	if count == 0 {
+		count = 1
+	}
+	data := make([]byte, 0, 6+len(w.Data))
+	data = binary.LittleEndian.AppendUint16(data, w.DataType)
+	if w.DataType == CIPStructType {
+		data = binary.LittleEndian.AppendUint16(data, w.StructHandle)
+	}
+	data = binary.LittleEndian.AppendUint16(data, count)
+	data = append(data, w.Data...)
+	return cip.MultiServiceRequest{Service: SvcWriteTag, Path: path, Data: data}, nil
+}
+
+// writeServiceError converts one embedded reply of a Multiple Service
+// Packet to an error, nil on success.
+func writeServiceError(resp cip.MultiServiceResponse, service byte) error {
+	if resp.Service != service|0x80 {
+		return fmt.Errorf("unexpected reply service: 0x%02X", resp.Service)
+	}
+	if resp.Status != StatusSuccess {
+		return parseCipError(resp.Status, byte(len(resp.ExtStatus)/2), resp.ExtStatus)
+	}
+	return nil
+}
+
+// sendMultipleService sends requests in one Multiple Service Packet to the
+// Message Router and returns one reply per request.
+func (p *PLC) sendMultipleService(requests []cip.MultiServiceRequest) ([]cip.MultiServiceResponse, error) {
+	responses, rejected, err := p.exchangeMultipleService(requests)
+	if err != nil {
+		return nil, err
+	}
+	return responses, rejected
+}
+
+// exchangeMultipleService is sendMultipleService with the failures split:
+// err is set when the packet could not be exchanged, rejected when the PLC
+// answered but refused the packet as a whole.
+func (p *PLC) exchangeMultipleService(requests []cip.MultiServiceRequest) (responses []cip.MultiServiceResponse, rejected, err error) {
+	msData, err := cip.BuildMultipleServiceRequest(requests)
+	if err != nil {
+		return nil, err, nil
+	}
+
+	msPath, _ := cip.EPath().Class(0x02).Instance(1).Build() // Message Router
+	reqData := make([]byte, 0, 2+len(msPath)+len(msData))
+	reqData = append(reqData, cip.SvcMultipleServicePacket)
+	reqData = append(reqData, msPath.WordLen())
+	reqData = append(reqData, msPath...)
+	reqData = append(reqData, msData...)
+
+	cipResp, err := p.sendCipRequest(reqData)
+	if err != nil {
+		return nil, nil, err
+	}
+	responses, rejected = parseMultipleServiceReply(cipResp, len(requests))
+	return responses, rejected, nil
+}
+
+// sendSingleService sends one service of a Multiple Service Packet batch on
+// its own. err is set when the request could not be exchanged; svcErr is the
+// service's own result.
+func (p *PLC) sendSingleService(req cip.MultiServiceRequest) (svcErr, err error) {
+	reqData := make([]byte, 0, 2+len(req.Path)+len(req.Data))
+	reqData = append(reqData, req.Service)
+	reqData = append(reqData, req.Path.WordLen())
+	reqData = append(reqData, req.Path...)
+	reqData = append(reqData, req.Data...)
+
+	cipResp, err := p.sendCipRequest(reqData)
+	if err != nil {
+		return nil, err
+	}
+	// [Service|0x80] [Reserved] [Status] [AddlStatusSize] [AddlStatus...]
+	if len(cipResp) < 4 {
+		return fmt.Errorf("response too short"), nil
+	}
+	ext := cipResp[4:]
+	if n := int(cipResp[3]) * 2; n < len(ext) {
+		ext = ext[:n]
+	}
+	return writeServiceError(cip.MultiServiceResponse{Service: cipResp[0], Status: cipResp[2], ExtStatus: ext}, req.Service), nil
+}
+
+// parseMultipleServiceReply checks a Multiple Service Packet reply and
+// returns its embedded replies. Status 0x1E (embedded service error) is
+// accepted; the individual replies carry the failures.
+func parseMultipleServiceReply(cipResp []byte, want int) ([]cip.MultiServiceResponse, error) {
+	if len(cipResp) < 4 {
+		return nil, fmt.Errorf("response too short")
+	}
+	replyService := cipResp[0]
+	status := cipResp[2]
+	addlStatusSize := cipResp[3]
+	if replyService != (cip.SvcMultipleServicePacket | 0x80) {
+		return nil, fmt.Errorf("unexpected reply service: 0x%02X", replyService)
+	}
+	if status != StatusSuccess && status != 0x1E {
+		return nil, parseCipError(status, addlStatusSize, cipResp[4:])
+	}
+
+	dataStart := 4 + int(addlStatusSize)*2
+	if dataStart > len(cipResp) {
+		return nil, fmt.Errorf("response too short")
+	}
+	responses, err := cip.ParseMultipleServiceResponse(cipResp[dataStart:])
+	if err != nil {
+		return nil, err
+	}
+	if len(responses) != want {
+		return nil, fmt.Errorf("expected %d responses, got %d", want, len(responses))
+	}
+	return responses, nil
+}
+
 // buildConnectionPath builds the connection path for Forward Open.
 // Matches pylogix's _connected_path() exactly:
 // route (port segment) + [0x20, 0x02, 0x24, 0x01] (Message Router class 2, instance 1)
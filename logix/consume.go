@@ -0,0 +1,153 @@
+package logix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yatesdr/plcio/eipscanner"
+)
+
+// ConsumeConfig configures a consumed-tag connection.
+type ConsumeConfig struct {
+	// RPI is the requested packet interval. Default 50ms. The producer may
+	// grant a different interval.
+	RPI time.Duration
+	// Unicast requests point-to-point T->O data. By default the connection
+	// is multicast, as between Logix controllers.
+	Unicast bool
+	// LocalIOAddr is the local UDP address for I/O. Default ":2222".
+	LocalIOAddr string
+
+	// OnValue is called with each new value of the tag, at most once per RPI.
+	OnValue func(v *TagValue)
+	// OnTimeout is called once if the producer stops sending. The consumer
+	// is stopped; Close it and call Consume again to reconnect.
+	OnTimeout func(err error)
+}
+
+// Consumer receives a produced tag over a Class 1 connection.
+type Consumer struct {
+	name     string
+	dataType uint16
+	count    int
+	handle   []byte // structure handle prepended to struct data
+	sc       *eipscanner.Scanner
+
+	mu     sync.Mutex
+	latest *TagValue
+}
+
+// Consume opens a Class 1 connection to a produced tag and decodes its data
+// into TagValues as they arrive. The tag must be configured as produced in
+// the controller and have a free consumer slot. Structure templates are read
+// once over the explicit connection; values are then delivered without
+// further requests.
+func (c *Client) Consume(tagName string, cfg ConsumeConfig) (*Consumer, error) {
+	if c == nil || c.plc == nil || c.plc.Connection == nil {
+		return nil, fmt.Errorf("Consume: nil client")
+	}
+	if c.micro800 {
+		return nil, fmt.Errorf("Consume: produced tags not supported on Micro800")
+	}
+
+	info, ok := c.GetTagInfo(tagName)
+	if !ok {
+		found, err := c.plc.FindSymbolByName(tagName)
+		if err != nil {
+			return nil, fmt.Errorf("Consume: %w", err)
+		}
+		if found == nil {
+			return nil, fmt.Errorf("Consume: tag %q not found", tagName)
+		}
+		info = *found
+	}
+
+	cons := &Consumer{name: tagName, dataType: info.TypeCode, count: info.ElementCount()}
+	size := 0
+	if IsStructure(info.TypeCode) {
+		tmpl, err := c.GetTemplate(info.TypeCode)
+		if err != nil {
+			return nil, fmt.Errorf("Consume: %w", err)
+		}
+		size = int(tmpl.Size) * cons.count
+		// Read responses carry the structure handle ahead of the data;
+		// produced data doesn't, so add it back for DecodeUDT.
+		cons.handle = binary.LittleEndian.AppendUint16(nil, tmpl.RawHandle)
+	} else {
+		size = TypeSize(info.TypeCode) * cons.count
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("Consume: unknown size for type 0x%04X", info.TypeCode)
+	}
+
+	route := c.plc.RoutePath
+	if len(route) == 0 {
+		route = []byte{0x01, c.plc.Slot}
+	}
+
+	onValue := cfg.OnValue
+	sc, err := eipscanner.Open(eipscanner.Config{
+		Address:     c.plc.IpAddress,
+		LocalIOAddr: cfg.LocalIOAddr,
+		Route:       route,
+		Tag:         tagName,
+		Multicast:   !cfg.Unicast,
+		InputSize:   size,
+		RPI:         cfg.RPI,
+		NoRunIdle:   true,
+		Timeout:     c.plc.Connection.GetTimeout(),
+		OnConsume: func(in []byte) {
+			v := cons.value(in)
+			if onValue != nil {
+				onValue(v)
+			}
+		},
+		OnTimeout: cfg.OnTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Consume %q: %w", tagName, err)
+	}
+	cons.sc = sc
+	debugLog("Consume %s: type=0x%04X size=%d multicast=%v", tagName, info.TypeCode, size, !cfg.Unicast)
+	return cons, nil
+}
+
+// value wraps produced data in a TagValue and keeps it as the latest value.
+func (cons *Consumer) value(data []byte) *TagValue {
+	v := &TagValue{
+		Name:     cons.name,
+		DataType: cons.dataType,
+		Bytes:    append(append([]byte{}, cons.handle...), data...),
+		Count:    cons.count,
+	}
+	cons.mu.Lock()
+	cons.latest = v
+	cons.mu.Unlock()
+	return v
+}
+
+// Value returns the most recently received value, or nil before the first
+// packet arrives.
+func (cons *Consumer) Value() *TagValue {
+	cons.mu.Lock()
+	defer cons.mu.Unlock()
+	return cons.latest
+}
+
+// Done is closed when the connection times out or the consumer is closed.
+func (cons *Consumer) Done() <-chan struct{} {
+	return cons.sc.Done()
+}
+
+// Err returns nil while the connection is up, an error wrapping
+// eipscanner.ErrTimeout after a timeout, and eipscanner.ErrClosed after Close.
+func (cons *Consumer) Err() error {
+	return cons.sc.Err()
+}
+
+// Close closes the connection to the producer. The Client stays connected.
+func (cons *Consumer) Close() error {
+	return cons.sc.Close()
+}
@@ -0,0 +1,210 @@
+package logix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/yatesdr/plcio/cip"
+	"github.com/yatesdr/plcio/eip"
+)
+
+// fakeProducer is a minimal producing controller: it accepts one session,
+// grants a Forward_Open to a symbolic tag path and sends T->O data to the
+// port the consumer announces.
+type fakeProducer struct {
+	t    *testing.T
+	ln   net.Listener
+	data []byte
+	path chan []byte
+	stop chan struct{}
+}
+
+func startProducer(t *testing.T, data []byte) *fakeProducer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &fakeProducer{t: t, ln: ln, data: data, path: make(chan []byte, 1), stop: make(chan struct{})}
+	go p.serve()
+	t.Cleanup(func() {
+		close(p.stop)
+		ln.Close()
+	})
+	return p
+}
+
+func (p *fakeProducer) serve() {
+	conn, err := p.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for {
+		f, err := eip.ReadFrame(conn)
+		if err != nil {
+			return
+		}
+		switch f.Command {
+		case eip.RegisterSession:
+			reply := f.Reply(eip.EncapStatusSuccess, f.Data)
+			reply.SessionHandle = 0x1234
+			conn.Write(reply.Bytes())
+		case eip.SendRRData:
+			conn.Write(f.Reply(eip.EncapStatusSuccess, eip.BuildRRData(p.handle(f))).Bytes())
+		default:
+			return
+		}
+	}
+}
+
+// handle answers Forward_Open and Forward_Close requests.
+func (p *fakeProducer) handle(f *eip.Frame) []byte {
+	cpfBytes, _ := eip.ParseRRData(f.Data)
+	pkt, err := eip.ParseEipCommonPacket(cpfBytes)
+	if err != nil {
+		p.t.Errorf("parse CPF: %v", err)
+		return nil
+	}
+	var req []byte
+	port := 2222
+	for _, it := range pkt.Items {
+		switch it.TypeId {
+		case eip.CpfUnconnectedMessageId:
+			req = it.Data
+		case eip.CpfSockAddrInfoTtoOId:
+			port = int(binary.BigEndian.Uint16(it.Data[2:4]))
+		}
+	}
+	svc := req[0]
+	body := req[2+int(req[1])*2:]
+	var resp []byte
+	switch svc {
+	case cip.SvcForwardOpen, cip.SvcForwardOpenLarge:
+		fo, err := cip.ParseForwardOpenRequest(body, svc == cip.SvcForwardOpenLarge)
+		if err != nil {
+			p.t.Errorf("parse Forward_Open: %v", err)
+			return nil
+		}
+		p.path <- fo.ConnectionPath
+		resp = append([]byte{svc | 0x80, 0, 0, 0}, cip.BuildForwardOpenSuccess(cip.ForwardOpenSuccess{
+			OTConnectionID:   0x11110000,
+			TOConnectionID:   0x22220000,
+			ConnectionSerial: fo.ConnectionSerial,
+			VendorID:         fo.VendorID,
+			OriginatorSerial: fo.OriginatorSerial,
+			OTAPI:            fo.OTRPI,
+			TOAPI:            fo.TORPI,
+		})...)
+		go p.produce(0x22220000, port, time.Duration(fo.TORPI)*time.Microsecond)
+	default:
+		resp = []byte{svc | 0x80, 0, 0, 0}
+	}
+	cpf := eip.EipCommonPacket{Items: []eip.EipCommonPacketItem{
+		{TypeId: eip.CpfAddressNullId},
+		{TypeId: eip.CpfUnconnectedMessageId, Length: uint16(len(resp)), Data: resp},
+	}}
+	return cpf.Bytes()
+}
+
+// produce sends the tag data with a Logix connection status header.
+func (p *fakeProducer) produce(connID uint32, port int, rpi time.Duration) {
+	conn, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port})
+	if err != nil {
+		p.t.Errorf("dial UDP: %v", err)
+		return
+	}
+	defer conn.Close()
+	tick := time.NewTicker(rpi)
+	defer tick.Stop()
+	for seq := uint16(1); ; seq++ {
+		addr := binary.LittleEndian.AppendUint32(nil, connID)
+		addr = binary.LittleEndian.AppendUint32(addr, uint32(seq))
+		payload := binary.LittleEndian.AppendUint16(nil, seq)
+		payload = append(payload, 0, 0, 0, 0)
+		payload = append(payload, p.data...)
+		cpf := eip.EipCommonPacket{Items: []eip.EipCommonPacketItem{
+			{TypeId: eip.CpfSequencedAddressId, Length: uint16(len(addr)), Data: addr},
+			{TypeId: eip.CpfConnectedTransportPacketId, Length: uint16(len(payload)), Data: payload},
+		}}
+		conn.Write(cpf.Bytes())
+		select {
+		case <-p.stop:
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+func TestConsumeStructTag(t *testing.T) {
+	data := []byte{0x2A, 0x00, 0x00, 0x00, 0x00, 0x00, 0x48, 0x42} // DINT 42, REAL 50.0
+	p := startProducer(t, data)
+
+	const typeCode = TypeStructureMask | 0x0123
+	addr := p.ln.Addr().(*net.TCPAddr)
+	c := &Client{
+		plc: &PLC{
+			IpAddress:  addr.String(),
+			Connection: eip.NewEipClientWithPort("127.0.0.1", uint16(addr.Port)),
+		},
+		tagInfo: map[string]TagInfo{"Produced": {Name: "Produced", TypeCode: typeCode}},
+		templates: map[uint16]*Template{0x0123: {
+			ID:        0x0123,
+			Name:      "Status",
+			Size:      8,
+			RawHandle: 0xBEEF,
+			Members: []TemplateMember{
+				{Name: "Count", Type: TypeDINT, Offset: 0},
+				{Name: "Speed", Type: TypeREAL, Offset: 4},
+			},
+		}},
+	}
+
+	values := make(chan *TagValue, 16)
+	cons, err := c.Consume("Produced", ConsumeConfig{
+		RPI:         10 * time.Millisecond,
+		Unicast:     true,
+		LocalIOAddr: "127.0.0.1:2422",
+		OnValue: func(v *TagValue) {
+			select {
+			case values <- v:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	defer cons.Close()
+
+	wantPath := []byte{0x01, 0x00, 0x91, 0x08, 'P', 'r', 'o', 'd', 'u', 'c', 'e', 'd'}
+	if path := <-p.path; !bytes.Equal(path, wantPath) {
+		t.Errorf("connection path = % X, want % X", path, wantPath)
+	}
+
+	var v *TagValue
+	select {
+	case v = <-values:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no value received")
+	}
+	if v.Name != "Produced" || v.DataType != typeCode {
+		t.Errorf("value = %q type 0x%04X", v.Name, v.DataType)
+	}
+	if !bytes.Equal(v.Bytes, append([]byte{0xEF, 0xBE}, data...)) {
+		t.Errorf("bytes = % X", v.Bytes)
+	}
+	decoded, ok := v.GoValueDecoded(c).(map[string]interface{})
+	if !ok {
+		t.Fatalf("decoded = %T, want map", v.GoValueDecoded(c))
+	}
+	if decoded["Count"] != int64(42) || decoded["Speed"] != float64(50) {
+		t.Errorf("decoded = %v", decoded)
+	}
+	if cons.Value() == nil {
+		t.Error("Value() = nil after a packet arrived")
+	}
+}
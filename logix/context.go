@@ -0,0 +1,62 @@
+package logix
+
+import (
+	"context"
+
+	"github.com/yatesdr/plcio/internal/ctxerr"
+)
+
+// BindContext ties the client's EIP socket I/O to ctx until the returned
+// release function is called. Cancelling ctx aborts a blocked transaction,
+// including each request of a fragmented or chunked read; the EIP session is
+// closed because the stream is left mid-frame. See eip.EipClient.BindContext.
+//
+// Bindings are serialized: a second BindContext waits until the first is
+// released, or returns ctx.Err() if ctx ends first. Do not call the *Context methods while holding a binding.
+func (c *Client) BindContext(ctx context.Context) (release func(), err error) {
+	if c == nil || c.plc == nil || c.plc.Connection == nil || ctx == nil {
+		return func() {}, nil
+	}
+	return c.plc.Connection.BindContext(ctx)
+}
+
+// ReadContext is like Read but honors ctx cancellation and deadline.
+func (c *Client) ReadContext(ctx context.Context, tagNames ...string) ([]*TagValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	values, err := c.Read(tagNames...)
+	return values, ctxerr.Wrap(ctx, err)
+}
+
+// WriteContext is like Write but honors ctx cancellation and deadline.
+func (c *Client) WriteContext(ctx context.Context, tagName string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return ctxerr.Wrap(ctx, c.Write(tagName, value))
+}
+
+// AllTagsContext is like AllTags but honors ctx cancellation and deadline.
+func (c *Client) AllTagsContext(ctx context.Context) ([]TagInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	tags, err := c.AllTags()
+	return tags, ctxerr.Wrap(ctx, err)
+}
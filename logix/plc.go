@@ -1,6 +1,7 @@
 package logix
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"time"
@@ -36,9 +37,15 @@ type Tag struct {
 	Bytes    []byte // Raw tag value bytes (little-endian)
 }
 
-// NewPLC creates the PLC wrapper (does not connect).
+// NewPLC creates the PLC wrapper and registers an EIP session.
 // If timeout > 0, it overrides the EIP client's default timeout.
 func NewPLC(ipaddr string, timeout time.Duration) (PLC, error) {
+	return NewPLCContext(context.Background(), ipaddr, timeout)
+}
+
+// NewPLCContext is like NewPLC but aborts the TCP dial and session
+// registration when ctx is cancelled or its deadline passes.
+func NewPLCContext(ctx context.Context, ipaddr string, timeout time.Duration) (PLC, error) {
 	if ipaddr == "" {
 		return PLC{}, fmt.Errorf("NewPLC: empty ipaddr")
 	}
@@ -51,7 +58,7 @@ func NewPLC(ipaddr string, timeout time.Duration) (PLC, error) {
 	if timeout > 0 {
 		c.SetTimeout(timeout)
 	}
-	err := c.Connect()
+	err := c.ConnectContext(ctx)
 	if err != nil {
 		debugLog("NewPLC %s: connect failed: %v", ipaddr, err)
 		return PLC{}, fmt.Errorf("NewPLC: failed to connect. %w", err)
@@ -391,6 +398,110 @@ func (p *PLC) WriteTagCount(tagName string, dataType uint16, value []byte, count
 	return nil
 }
 
+// WriteStructTag writes one structure instance. Structures are written with
+// data type 0x02A0 followed by the template's structure handle, which the
+// PLC checks against the tag's type; data is the structure's bytes without
+// the handle.
+func (p *PLC) WriteStructTag(tagName string, handle uint16, data []byte) error {
+	if p == nil || p.Connection == nil {
+		return fmt.Errorf("WriteStructTag: nil plc or connection")
+	}
+
+	path, err := cip.EPath().Symbol(tagName).Build()
+	if err != nil {
+		return fmt.Errorf("WriteStructTag: failed to build path: %w", err)
+	}
+
+	// [Service] [PathSize] [Path] [0x02A0] [Handle 2 bytes] [Count 2 bytes] [Data]
+	reqData := make([]byte, 0, 2+len(path)+6+len(data))
+	reqData = append(reqData, SvcWriteTag)
+	reqData = append(reqData, path.WordLen())
+	reqData = append(reqData, path...)
+	reqData = binary.LittleEndian.AppendUint16(reqData, CIPStructType)
+	reqData = binary.LittleEndian.AppendUint16(reqData, handle)
+	reqData = binary.LittleEndian.AppendUint16(reqData, 1)
+	reqData = append(reqData, data...)
+
+	cipResp, err := p.sendCipRequest(reqData)
+	if err != nil {
+		return fmt.Errorf("WriteStructTag: %w", err)
+	}
+	if err := parseWriteTagResponse(cipResp); err != nil {
+		return fmt.Errorf("WriteStructTag: %w", err)
+	}
+	return nil
+}
+
+// ReadModifyWriteTag changes individual bits of an integer tag in a single
+// atomic operation. The PLC computes (value OR orMask) AND andMask, so bits
+// set in orMask are set, bits cleared in andMask are cleared and all others
+// keep whatever the PLC holds at that moment. Both masks must have the tag's
+// size in bytes (1, 2, 4, 8 or 12).
+func (p *PLC) ReadModifyWriteTag(tagName string, orMask, andMask []byte) error {
+	if p == nil || p.Connection == nil {
+		return fmt.Errorf("ReadModifyWriteTag: nil plc or connection")
+	}
+
+	reqData, err := buildReadModifyWriteRequest(tagName, orMask, andMask)
+	if err != nil {
+		return fmt.Errorf("ReadModifyWriteTag: %w", err)
+	}
+
+	logging.DebugLog("logix", "ReadModifyWriteTag %s: or=%X and=%X", tagName, orMask, andMask)
+
+	cipResp, err := p.sendCipRequest(reqData)
+	if err != nil {
+		return fmt.Errorf("ReadModifyWriteTag: %w", err)
+	}
+	if err := parseReplyStatus(cipResp, SvcReadModifyWriteTag); err != nil {
+		return fmt.Errorf("ReadModifyWriteTag: %w", err)
+	}
+	return nil
+}
+
+// buildReadModifyWriteRequest builds a Read Modify Write Tag request:
+// [Service 1] [PathSize 1] [Path n] [Request data n]
+func buildReadModifyWriteRequest(tagName string, orMask, andMask []byte) ([]byte, error) {
+	if tagName == "" {
+		return nil, fmt.Errorf("empty tag name")
+	}
+	data, err := readModifyWriteData(orMask, andMask)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := cip.EPath().Symbol(tagName).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build path: %w", err)
+	}
+
+	reqData := make([]byte, 0, 2+len(path)+len(data))
+	reqData = append(reqData, SvcReadModifyWriteTag)
+	reqData = append(reqData, path.WordLen())
+	reqData = append(reqData, path...)
+	reqData = append(reqData, data...)
+	return reqData, nil
+}
+
+// readModifyWriteData builds the request data of Read Modify Write Tag:
+// [MaskSize 2] [OR mask n] [AND mask n]
+func readModifyWriteData(orMask, andMask []byte) ([]byte, error) {
+	if len(orMask) != len(andMask) {
+		return nil, fmt.Errorf("mask sizes differ: %d and %d bytes", len(orMask), len(andMask))
+	}
+	switch len(orMask) {
+	case 1, 2, 4, 8, 12:
+	default:
+		return nil, fmt.Errorf("invalid mask size %d", len(orMask))
+	}
+
+	data := make([]byte, 0, 2+2*len(orMask))
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(orMask)))
+	data = append(data, orMask...)
+	data = append(data, andMask...)
+	return data, nil
+}
+
 // buildRoutedCpf wraps a CIP request in a CPF packet with routing via Connection Manager.
 // The routePath specifies how to reach the target (e.g., {0x01, 0x00} for backplane port 1, slot 0).
 func buildRoutedCpf(cipRequest []byte, routePath []byte) *eip.EipCommonPacket {
@@ -620,6 +731,12 @@ func parseReadTagFragmentedResponse(data []byte, tagName string) (*Tag, bool, er
 // parseWriteTagResponse parses the CIP response for a Write Tag request.
 // Response format: [ReplyService 1] [Reserved 1] [Status 1] [AddlStatusSize 1] [AddlStatus n]
 func parseWriteTagResponse(data []byte) error {
+	return parseReplyStatus(data, SvcWriteTag)
+}
+
+// parseReplyStatus checks the reply header of a response that carries no
+// data, such as Write Tag and Read Modify Write Tag.
+func parseReplyStatus(data []byte, service byte) error {
 	if len(data) < 4 {
 		return fmt.Errorf("response too short: %d bytes", len(data))
 	}
@@ -629,8 +746,8 @@ func parseWriteTagResponse(data []byte) error {
 	status := data[2]
 	addlStatusSize := data[3]
 
-	// Verify it's a reply to Write Tag
-	if replyService != (SvcWriteTag | 0x80) {
+	// Verify it's a reply to the request
+	if replyService != (service | 0x80) {
 		return fmt.Errorf("unexpected reply service: 0x%02X", replyService)
 	}
 
@@ -0,0 +1,273 @@
+package logix
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ArraySlice is a rectangular block of elements read from an array tag.
+type ArraySlice struct {
+	Name       string // Array tag name
+	DataType   uint16 // Element type code
+	Dimensions []int  // Dimensions of the whole array
+	Start      []int  // Index of the first element read, per dimension
+	Counts     []int  // Number of elements read, per dimension
+	Bytes      []byte // Element data, last index varying fastest
+}
+
+// ReadArraySlice reads the elements of an array tag from start through
+// start+counts-1 in each dimension, e.g. start [2, 0] and counts [3, 10]
+// read rows 2-4 of a [N, 10] array, and start [100] with counts [100]
+// reads elements 100-199 of a one-dimensional array.
+//
+// The array's dimensions come from the discovered tag info (see SetTags),
+// or from the symbol table when the tag was not discovered. Runs of
+// contiguous elements are read together, split to fit the connection size.
+// BOOL arrays are not supported, since the controller packs them into DWORDs.
+func (c *Client) ReadArraySlice(tagName string, start, counts []int) (*ArraySlice, error) {
+	if c == nil || c.plc == nil {
+		return nil, fmt.Errorf("ReadArraySlice: nil client")
+	}
+	if tagName == "" || strings.ContainsAny(tagName, "[]") {
+		return nil, fmt.Errorf("ReadArraySlice: %q is not an array tag name", tagName)
+	}
+
+	info, err := c.arrayInfo(tagName)
+	if err != nil {
+		return nil, fmt.Errorf("ReadArraySlice: %w", err)
+	}
+	dims := info.Dimensions
+	if len(start) != len(dims) || len(counts) != len(dims) {
+		return nil, fmt.Errorf("ReadArraySlice: %q has %d dimensions, got %d start indices and %d counts",
+			tagName, len(dims), len(start), len(counts))
+	}
+	total := 1
+	for i := range dims {
+		if start[i] < 0 || counts[i] < 1 || start[i]+counts[i] > dims[i] {
+			return nil, fmt.Errorf("ReadArraySlice: range %d..%d of dimension %d outside 0..%d",
+				start[i], start[i]+counts[i]-1, i, dims[i]-1)
+		}
+		total *= counts[i]
+	}
+
+	elemType := info.TypeCode &^ SymbolTypeArrayMask
+	if !IsStructure(elemType) && BaseType(elemType) == TypeBOOL {
+		return nil, fmt.Errorf("ReadArraySlice: BOOL arrays are not supported")
+	}
+	elemSize := int(c.GetElementSize(elemType))
+	if elemSize == 0 {
+		return nil, fmt.Errorf("ReadArraySlice: unknown element size for type 0x%04X", elemType)
+	}
+
+	// Dimensions fully covered from the end inward make longer contiguous
+	// runs. Dimension k is the outermost one inside a run.
+	k := len(dims) - 1
+	for k > 0 && start[k] == 0 && counts[k] == dims[k] {
+		k--
+	}
+	run := 1
+	for _, n := range counts[k:] {
+		run *= n
+	}
+
+	data := make([]byte, 0, total*elemSize)
+	idx := append([]int{}, start...)
+	for {
+		chunk, err := c.readArrayRun(tagName, dims, flatIndex(idx, dims), run, elemSize)
+		if err != nil {
+			return nil, fmt.Errorf("ReadArraySlice: %w", err)
+		}
+		data = append(data, chunk...)
+
+		// Advance the indices outside the run, odometer style
+		j := k - 1
+		for ; j >= 0; j-- {
+			idx[j]++
+			if idx[j] < start[j]+counts[j] {
+				break
+			}
+			idx[j] = start[j]
+		}
+		if j < 0 {
+			break
+		}
+	}
+
+	return &ArraySlice{
+		Name:       tagName,
+		DataType:   elemType,
+		Dimensions: dims,
+		Start:      append([]int{}, start...),
+		Counts:     append([]int{}, counts...),
+		Bytes:      data,
+	}, nil
+}
+
+// arrayInfo returns the tag info of an array tag with one dimension size
+// per array dimension, reading them from the symbol table if needed.
+func (c *Client) arrayInfo(tagName string) (TagInfo, error) {
+	info, known := c.tagInfo[tagName]
+	if !known {
+		found, err := c.plc.FindSymbolByName(tagName)
+		if err != nil {
+			return TagInfo{}, err
+		}
+		if found == nil {
+			return TagInfo{}, fmt.Errorf("tag %q not found", tagName)
+		}
+		info = *found
+		info.Name = tagName
+	}
+
+	numDims := ArrayDimensions(info.TypeCode)
+	if numDims == 0 {
+		return TagInfo{}, fmt.Errorf("tag %q is not an array", tagName)
+	}
+	if len(info.Dimensions) == numDims {
+		return info, nil
+	}
+
+	// Discovery may have stored only the total element count
+	dims, err := c.plc.getSymbolDimensions(info.Instance, numDims)
+	if err != nil {
+		return TagInfo{}, fmt.Errorf("dimensions of %q: %w", tagName, err)
+	}
+	info.Dimensions = dims
+	if known {
+		c.tagInfo[tagName] = info
+	}
+	return info, nil
+}
+
+// readArrayRun reads count contiguous elements starting at flat index pos,
+// in requests that fit the connection size.
+func (c *Client) readArrayRun(tagName string, dims []int, pos, count, elemSize int) ([]byte, error) {
+	maxPayload := 480 // Conservative default for unconnected messaging
+	if c.plc.connSize > 0 {
+		maxPayload = int(c.plc.connSize) - 100 // Leave room for protocol overhead
+	}
+	perRequest := maxPayload / elemSize
+	if perRequest < 1 {
+		perRequest = 1
+	}
+
+	data := make([]byte, 0, count*elemSize)
+	for count > 0 {
+		n := min(count, perRequest, 0xFFFF)
+		name := elementName(tagName, unflatIndex(pos, dims))
+		tag, _, err := c.plc.readTagCountInternal(name, uint16(n))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		// Structure replies start with the structure handle
+		b := tag.Bytes
+		if IsCIPStructResponse(tag.DataType) && len(b) >= 2 {
+			b = b[2:]
+		}
+		got := min(len(b)/elemSize, n)
+		if got == 0 {
+			return nil, fmt.Errorf("%s: no element data in reply", name)
+		}
+
+		data = append(data, b[:got*elemSize]...)
+		pos += got
+		count -= got
+	}
+	return data, nil
+}
+
+// elementName formats an element reference such as "Arr[2,3,0]".
+func elementName(tagName string, idx []int) string {
+	parts := make([]string, len(idx))
+	for i, v := range idx {
+		parts[i] = strconv.Itoa(v)
+	}
+	return tagName + "[" + strings.Join(parts, ",") + "]"
+}
+
+// flatIndex converts per-dimension indices to a row-major element offset.
+func flatIndex(idx, dims []int) int {
+	pos := 0
+	for i, v := range idx {
+		pos = pos*dims[i] + v
+	}
+	return pos
+}
+
+// unflatIndex converts a row-major element offset to per-dimension indices.
+func unflatIndex(pos int, dims []int) []int {
+	idx := make([]int, len(dims))
+	for i := len(dims) - 1; i >= 0; i-- {
+		idx[i] = pos % dims[i]
+		pos /= dims[i]
+	}
+	return idx
+}
+
+// GoValue returns the elements as nested slices with one level per
+// dimension, e.g. [][]int64 for a two-dimensional DINT slice. Elements use
+// the same Go types as TagValue.GoValue; structure elements are raw bytes
+// ([]int). Use GoValueDecoded to decode structures.
+func (s *ArraySlice) GoValue() interface{} {
+	return s.GoValueDecoded(nil)
+}
+
+// GoValueDecoded is like GoValue but decodes structure elements into
+// map[string]interface{} using the client's templates.
+func (s *ArraySlice) GoValueDecoded(client *Client) interface{} {
+	total := 1
+	for _, n := range s.Counts {
+		total *= n
+	}
+	if total == 0 || len(s.Bytes) == 0 {
+		return nil
+	}
+
+	var flat interface{}
+	if IsStructure(s.DataType) {
+		elemSize := len(s.Bytes) / total
+		elems := make([]interface{}, total)
+		for i := range elems {
+			elem := &TagValue{DataType: s.DataType, Bytes: s.Bytes[i*elemSize : (i+1)*elemSize]}
+			elems[i] = elem.bytesToIntArray()
+			if client == nil {
+				continue
+			}
+			if tmpl, err := client.GetTemplate(s.DataType); err == nil {
+				if m, err := client.decodeUDTWithTemplateInternal(tmpl, elem.Bytes, false); err == nil {
+					elems[i] = m
+				}
+			}
+		}
+		flat = elems
+	} else {
+		v := &TagValue{DataType: s.DataType, Bytes: s.Bytes}
+		flat = v.parseArray(BaseType(s.DataType))
+	}
+
+	rv := reflect.ValueOf(flat)
+	if rv.Kind() != reflect.Slice || rv.Len() != total {
+		return flat
+	}
+	return shapeSlice(rv, s.Counts).Interface()
+}
+
+// shapeSlice splits a flat slice into nested slices of the given counts.
+func shapeSlice(flat reflect.Value, counts []int) reflect.Value {
+	if len(counts) <= 1 {
+		return flat
+	}
+	inner := flat.Len() / counts[0]
+	var out reflect.Value
+	for i := 0; i < counts[0]; i++ {
+		row := shapeSlice(flat.Slice(i*inner, (i+1)*inner), counts[1:])
+		if i == 0 {
+			out = reflect.MakeSlice(reflect.SliceOf(row.Type()), counts[0], counts[0])
+		}
+		out.Index(i).Set(row)
+	}
+	return out
+}
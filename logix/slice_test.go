@@ -0,0 +1,114 @@
+package logix
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// memberIndices returns the element indices of a Read Tag request path.
+func memberIndices(path []byte) []int {
+	var idx []int
+	for len(path) > 0 {
+		switch path[0] {
+		case 0x91:
+			n := 2 + int(path[1])
+			path = path[n+n%2:]
+		case 0x28:
+			idx = append(idx, int(path[1]))
+			path = path[2:]
+		case 0x29:
+			idx = append(idx, int(binary.LittleEndian.Uint16(path[2:4])))
+			path = path[4:]
+		default:
+			return idx
+		}
+	}
+	return idx
+}
+
+func TestReadArraySlice(t *testing.T) {
+	dims := []int{4, 5, 6}
+	var requests [][]int
+	conn := startCIPServer(t, func(req []byte) []byte {
+		if req[0] != SvcReadTag {
+			return []byte{req[0] | 0x80, 0, StatusServiceNotSupport, 0}
+		}
+		pathLen := int(req[1]) * 2
+		idx := memberIndices(req[2 : 2+pathLen])
+		count := int(binary.LittleEndian.Uint16(req[2+pathLen:]))
+		requests = append(requests, append(idx, count))
+
+		// Each DINT holds its own flat index
+		resp := []byte{0xCC, 0, 0, 0, 0xC4, 0x00}
+		for i := 0; i < count; i++ {
+			resp = binary.LittleEndian.AppendUint32(resp, uint32(flatIndex(idx, dims)+i))
+		}
+		return resp
+	})
+
+	c := &Client{plc: &PLC{Connection: conn}}
+	c.SetTags([]TagInfo{{Name: "Grid", TypeCode: TypeDINT | SymbolTypeArray3D, Dimensions: dims}})
+
+	s, err := c.ReadArraySlice("Grid", []int{1, 2, 4}, []int{2, 2, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][][]int64{
+		{{46, 47}, {52, 53}},
+		{{76, 77}, {82, 83}},
+	}
+	if got := s.GoValue(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GoValue = %v, want %v", got, want)
+	}
+	if len(requests) != 4 || !reflect.DeepEqual(requests[0], []int{1, 2, 4, 2}) {
+		t.Errorf("requests %v, want 4 runs of 2 starting at [1 2 4]", requests)
+	}
+
+	// Whole rows merge into one run: elements 100..159 of the flattened array
+	requests = nil
+	s, err = c.ReadArraySlice("Grid", []int{3, 0, 0}, []int{1, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := s.GoValue().([][][]int64)
+	if len(got) != 1 || len(got[0]) != 5 || got[0][4][5] != 119 {
+		t.Errorf("GoValue = %v", got)
+	}
+	if len(requests) != 1 || !reflect.DeepEqual(requests[0], []int{3, 0, 0, 30}) {
+		t.Errorf("requests %v, want one read of 30 from [3 0 0]", requests)
+	}
+
+	if _, err := c.ReadArraySlice("Grid", []int{3, 0, 0}, []int{2, 1, 1}); err == nil {
+		t.Error("range past the end accepted")
+	}
+	if _, err := c.ReadArraySlice("Grid", []int{0}, []int{1}); err == nil {
+		t.Error("wrong number of indices accepted")
+	}
+}
+
+func TestReadArraySliceSplitsLargeRuns(t *testing.T) {
+	var counts []int
+	conn := startCIPServer(t, func(req []byte) []byte {
+		pathLen := int(req[1]) * 2
+		count := int(binary.LittleEndian.Uint16(req[2+pathLen:]))
+		counts = append(counts, count)
+		resp := []byte{0xCC, 0, 0, 0, 0xCA, 0x00}
+		return append(resp, make([]byte, 4*count)...)
+	})
+
+	c := &Client{plc: &PLC{Connection: conn}}
+	c.SetTags([]TagInfo{{Name: "Big", TypeCode: TypeREAL | SymbolTypeArray1D, Dimensions: []int{1000}}})
+
+	s, err := c.ReadArraySlice("Big", []int{100}, []int{300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := s.GoValue().([]float64); len(v) != 300 {
+		t.Errorf("%d values, want 300", len(v))
+	}
+	// 480-byte unconnected budget fits 120 REALs
+	if !reflect.DeepEqual(counts, []int{120, 120, 60}) {
+		t.Errorf("request counts %v", counts)
+	}
+}
@@ -0,0 +1,223 @@
+package logix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/yatesdr/plcio/cip"
+)
+
+// Identity object and its status word attribute.
+const (
+	classIdentity      byte = 0x01
+	attrIdentityStatus byte = 5
+)
+
+// Identity status word bits (CIP Vol 1, 5-2.2.1.5). Logix controllers put
+// their operating mode in the extended device status field and the
+// keyswitch position in bits 12-13.
+const (
+	statusOwned                   uint16 = 0x0001
+	statusMinorRecoverableFault   uint16 = 0x0100
+	statusMinorUnrecoverableFault uint16 = 0x0200
+	statusMajorRecoverableFault   uint16 = 0x0400
+	statusMajorUnrecoverableFault uint16 = 0x0800
+
+	statusModeMask      uint16 = 0x00F0
+	statusKeyswitchMask uint16 = 0x3000
+)
+
+// ControllerMode is the controller operating mode from the identity status word.
+type ControllerMode uint8
+
+const (
+	ModeUnknown     ControllerMode = 0x0
+	ModeFlashUpdate ControllerMode = 0x1 // Firmware update in progress
+	ModeFaulted     ControllerMode = 0x5 // Major fault
+	ModeRun         ControllerMode = 0x6
+	ModeProgram     ControllerMode = 0x7
+)
+
+// String returns the mode name.
+func (m ControllerMode) String() string {
+	switch m {
+	case ModeFlashUpdate:
+		return "FLASH UPDATE"
+	case ModeFaulted:
+		return "FAULTED"
+	case ModeRun:
+		return "RUN"
+	case ModeProgram:
+		return "PROGRAM"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", uint8(m))
+	}
+}
+
+// KeyswitchPosition is the position of the controller's mode switch.
+type KeyswitchPosition uint8
+
+const (
+	KeyswitchUnknown KeyswitchPosition = 0
+	KeyswitchRun     KeyswitchPosition = 1
+	KeyswitchProgram KeyswitchPosition = 2
+	KeyswitchRemote  KeyswitchPosition = 3
+)
+
+// String returns the keyswitch position name, or "" if unknown.
+func (k KeyswitchPosition) String() string {
+	switch k {
+	case KeyswitchRun:
+		return "RUN"
+	case KeyswitchProgram:
+		return "PROG"
+	case KeyswitchRemote:
+		return "REM"
+	default:
+		return ""
+	}
+}
+
+// ControllerStatus is the controller's operating state.
+type ControllerStatus struct {
+	Status    uint16            // Raw identity status word
+	Mode      ControllerMode    // Operating mode
+	Keyswitch KeyswitchPosition // Mode switch position
+	Owned     bool              // An I/O connection owns the controller
+
+	MinorRecoverableFault   bool
+	MinorUnrecoverableFault bool
+	MajorRecoverableFault   bool
+	MajorUnrecoverableFault bool
+
+	Clock    time.Time // Controller wall clock (UTC); zero if it could not be read
+	ReadTime time.Time // Local time the clock was read
+}
+
+// MajorFault reports whether the controller has a major fault.
+func (s *ControllerStatus) MajorFault() bool {
+	return s.MajorRecoverableFault || s.MajorUnrecoverableFault
+}
+
+// MinorFault reports whether the controller has a minor fault.
+func (s *ControllerStatus) MinorFault() bool {
+	return s.MinorRecoverableFault || s.MinorUnrecoverableFault
+}
+
+// ClockDrift returns how far the controller clock is ahead of the local
+// clock (negative if behind), or 0 if the clock was not read.
+func (s *ControllerStatus) ClockDrift() time.Duration {
+	if s.Clock.IsZero() {
+		return 0
+	}
+	return s.Clock.Sub(s.ReadTime)
+}
+
+// RunState returns the mode as shown in the programming software, e.g.
+// "REMOTE RUN" when the keyswitch is in REM.
+func (s *ControllerStatus) RunState() string {
+	if s.Keyswitch == KeyswitchRemote && (s.Mode == ModeRun || s.Mode == ModeProgram) {
+		return "REMOTE " + s.Mode.String()
+	}
+	return s.Mode.String()
+}
+
+// parseControllerStatus decodes an identity status word.
+func parseControllerStatus(word uint16) *ControllerStatus {
+	return &ControllerStatus{
+		Status:                  word,
+		Mode:                    ControllerMode((word & statusModeMask) >> 4),
+		Keyswitch:               KeyswitchPosition((word & statusKeyswitchMask) >> 12),
+		Owned:                   word&statusOwned != 0,
+		MinorRecoverableFault:   word&statusMinorRecoverableFault != 0,
+		MinorUnrecoverableFault: word&statusMinorUnrecoverableFault != 0,
+		MajorRecoverableFault:   word&statusMajorRecoverableFault != 0,
+		MajorUnrecoverableFault: word&statusMajorUnrecoverableFault != 0,
+	}
+}
+
+// ReadStatusWord reads the identity status word of the controller the
+// connection is routed to. Unlike ListIdentity, which is answered by the
+// Ethernet module in a ControlLogix chassis, this reaches the CPU.
+func (p *PLC) ReadStatusWord() (uint16, error) {
+	if p == nil || p.Connection == nil {
+		return 0, fmt.Errorf("ReadStatusWord: nil plc or connection")
+	}
+
+	path, err := cip.EPath().Class(classIdentity).Instance(1).Attribute(attrIdentityStatus).Build()
+	if err != nil {
+		return 0, fmt.Errorf("ReadStatusWord: %w", err)
+	}
+
+	reqData := make([]byte, 0, 2+len(path))
+	reqData = append(reqData, SvcGetAttributeSingle)
+	reqData = append(reqData, path.WordLen())
+	reqData = append(reqData, path...)
+
+	data, err := p.sendAttributeRequest(reqData)
+	if err != nil {
+		return 0, fmt.Errorf("ReadStatusWord: %w", err)
+	}
+	if len(data) < 2 {
+		return 0, fmt.Errorf("ReadStatusWord: response missing data")
+	}
+	return binary.LittleEndian.Uint16(data[0:2]), nil
+}
+
+// getAttributeList sends Get Attribute List for attrs and returns the reply
+// data: the attribute count, then each attribute's ID, status and value.
+func (p *PLC) getAttributeList(path cip.EPath_t, attrs []uint16) ([]byte, error) {
+	reqData := make([]byte, 0, 2+len(path)+2+2*len(attrs))
+	reqData = append(reqData, SvcGetAttributeList)
+	reqData = append(reqData, path.WordLen())
+	reqData = append(reqData, path...)
+	reqData = binary.LittleEndian.AppendUint16(reqData, uint16(len(attrs)))
+	for _, attr := range attrs {
+		reqData = binary.LittleEndian.AppendUint16(reqData, attr)
+	}
+	return p.sendAttributeRequest(reqData)
+}
+
+// sendAttributeRequest sends a request and returns the reply data after the
+// status fields.
+func (p *PLC) sendAttributeRequest(reqData []byte) ([]byte, error) {
+	cipResp, err := p.sendCipRequest(reqData)
+	if err != nil {
+		return nil, err
+	}
+	if err := parseReplyStatus(cipResp, reqData[0]); err != nil {
+		return nil, err
+	}
+	dataStart := 4 + int(cipResp[3])*2
+	if dataStart > len(cipResp) {
+		return nil, fmt.Errorf("response missing data")
+	}
+	return cipResp[dataStart:], nil
+}
+
+// ControllerStatus reads the controller's mode, keyswitch position, fault
+// flags and wall clock. The clock is left zero on controllers without a
+// WallClockTime object, such as Micro800.
+func (c *Client) ControllerStatus() (*ControllerStatus, error) {
+	if c == nil || c.plc == nil {
+		return nil, fmt.Errorf("ControllerStatus: nil client")
+	}
+
+	word, err := c.plc.ReadStatusWord()
+	if err != nil {
+		return nil, err
+	}
+	st := parseControllerStatus(word)
+
+	// Compare the clock against local time halfway through the round trip
+	sent := time.Now()
+	clock, err := c.plc.readClock()
+	st.ReadTime = sent.Add(time.Since(sent) / 2)
+	if err != nil {
+		debugLog("ControllerStatus: wall clock: %v", err)
+	} else {
+		st.Clock = clock.UTC
+	}
+	return st, nil
+}
@@ -0,0 +1,64 @@
+package logix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestControllerStatus(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	conn := startCIPServer(t, func(req []byte) []byte {
+		switch req[0] {
+		case SvcGetAttributeSingle:
+			// Remote keyswitch, run mode, minor recoverable fault
+			return []byte{0x8E, 0, 0, 0, 0x60, 0x31}
+		case SvcGetAttributeList:
+			if req[3] != classWallClock {
+				t.Errorf("class 0x%02X, want wall clock", req[3])
+			}
+			// A UTC offset must not leak into Clock: it is UTC, not local time
+			return clockReply(clock, -5*time.Hour)
+		}
+		return []byte{req[0] | 0x80, 0, StatusServiceNotSupport, 0}
+	})
+
+	c := &Client{plc: &PLC{Connection: conn}}
+	st, err := c.ControllerStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Mode != ModeRun || st.Keyswitch != KeyswitchRemote || st.RunState() != "REMOTE RUN" {
+		t.Errorf("mode %v keyswitch %v run state %q", st.Mode, st.Keyswitch, st.RunState())
+	}
+	if !st.MinorFault() || st.MajorFault() || !st.MinorRecoverableFault {
+		t.Errorf("faults: %+v", st)
+	}
+	if !st.Clock.Equal(clock) {
+		t.Errorf("clock %v, want %v", st.Clock, clock)
+	}
+	if d := st.ClockDrift(); d >= 0 {
+		t.Errorf("drift %v, want controller clock behind", d)
+	}
+}
+
+func TestControllerStatusWithoutClock(t *testing.T) {
+	conn := startCIPServer(t, func(req []byte) []byte {
+		if req[0] == SvcGetAttributeSingle {
+			// Program keyswitch, faulted, major recoverable fault
+			return []byte{0x8E, 0, 0, 0, 0x50, 0x24}
+		}
+		return []byte{req[0] | 0x80, 0, StatusPathUnknown, 0}
+	})
+
+	c := &Client{plc: &PLC{Connection: conn}}
+	st, err := c.ControllerStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Mode != ModeFaulted || st.Keyswitch != KeyswitchProgram || !st.MajorFault() {
+		t.Errorf("status: %+v", st)
+	}
+	if !st.Clock.IsZero() || st.ClockDrift() != 0 {
+		t.Errorf("clock %v drift %v, want zero", st.Clock, st.ClockDrift())
+	}
+}
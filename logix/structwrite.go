@@ -0,0 +1,316 @@
+package logix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// WriteStruct writes a whole structure (UDT) tag in one Write Tag request.
+// value is a map[string]interface{} of member names to values, as returned
+// by GoValueDecoded, or a Go struct whose exported fields are matched to
+// members by a `logix:"Name"` field tag or by field name (case-insensitive;
+// `logix:"-"` skips a field). Nested structures take maps or structs, array
+// members take slices, and STRING-like members (LEN + DATA) also take a Go
+// string.
+//
+// The value is encoded into the exact byte layout of the cached template,
+// including BOOL members packed into bits. If it does not set every member,
+// the current value is read first and only the given members are changed.
+// That read and the write are two requests: members the controller changes
+// in between are overwritten with the values read.
+//
+// tagName can be a structure tag, a structure member ("Station.Recipe") or
+// an element of a structure array ("Stations[3]").
+func (c *Client) WriteStruct(tagName string, value interface{}) error {
+	if c == nil || c.plc == nil {
+		return fmt.Errorf("WriteStruct: nil client")
+	}
+
+	handle, data, err := c.encodeStructValue(tagName, value)
+	if err != nil {
+		return fmt.Errorf("WriteStruct %s: %w", tagName, err)
+	}
+	return c.plc.WriteStructTag(tagName, handle, data)
+}
+
+// encodeStructValue encodes value as the structure tagName, reading the
+// current value first if value does not set every member. It returns the
+// template's structure handle and the structure bytes.
+func (c *Client) encodeStructValue(tagName string, value interface{}) (uint16, []byte, error) {
+	typeCode, err := c.structType(tagName)
+	if err != nil {
+		return 0, nil, err
+	}
+	tmpl, err := c.GetTemplate(typeCode)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	data := make([]byte, tmpl.Size)
+	complete, err := c.encodeStruct(tmpl, value, data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if !complete {
+		current, err := c.plc.ReadTag(tagName)
+		if err != nil {
+			return 0, nil, fmt.Errorf("reading current value: %w", err)
+		}
+		b := current.Bytes
+		if IsCIPStructResponse(current.DataType) && len(b) >= 2 {
+			b = b[2:] // Structure handle
+		}
+		if len(b) < int(tmpl.Size) {
+			return 0, nil, fmt.Errorf("read %d bytes, template %q is %d bytes", len(b), tmpl.Name, tmpl.Size)
+		}
+		copy(data, b)
+		if _, err := c.encodeStruct(tmpl, value, data); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	logging.DebugLog("logix", "encodeStructValue %s: template %q handle 0x%04X, %d bytes (complete=%v)",
+		tagName, tmpl.Name, tmpl.RawHandle, len(data), complete)
+	return tmpl.RawHandle, data, nil
+}
+
+// structType returns the structure type code of a tag, structure member or
+// structure array element.
+func (c *Client) structType(tagName string) (uint16, error) {
+	typeCode := c.getMemberTypeFromTemplate(tagName)
+	if typeCode == 0 {
+		base := tagName
+		if i := strings.Index(base, "["); i > 0 && strings.HasSuffix(base, "]") {
+			base = base[:i]
+		}
+		if info, ok := c.tagInfo[base]; ok {
+			typeCode = info.TypeCode
+		} else if resolved, ok := c.ResolveTagType(base); ok {
+			typeCode = resolved
+		}
+	}
+	if typeCode == 0 {
+		return 0, fmt.Errorf("tag type unknown")
+	}
+	typeCode &^= SymbolTypeArrayMask
+	if !IsStructure(typeCode) {
+		return 0, fmt.Errorf("not a structure (type %s)", TypeName(typeCode))
+	}
+	return typeCode, nil
+}
+
+// encodeStruct encodes value into buf, which holds one instance of tmpl.
+// Members not in value keep buf's contents. complete reports whether every
+// visible member was set.
+func (c *Client) encodeStruct(tmpl *Template, value interface{}, buf []byte) (complete bool, err error) {
+	if s, ok := value.(string); ok && isStringTemplate(tmpl) {
+		return true, encodeStringStruct(tmpl, s, buf)
+	}
+
+	members, ok := structMembers(value)
+	if !ok {
+		return false, fmt.Errorf("%s: cannot encode %T as a structure", tmpl.Name, value)
+	}
+
+	set := make(map[int]bool, len(members))
+	complete = true
+	for name, v := range members {
+		idx, ok := tmpl.MemberMap[name]
+		if !ok {
+			idx, ok = memberIndexFold(tmpl, name)
+		}
+		if !ok {
+			return false, fmt.Errorf("%s has no member %q", tmpl.Name, name)
+		}
+		member := &tmpl.Members[idx]
+		memberComplete, err := c.encodeMember(member, v, buf)
+		if err != nil {
+			return false, fmt.Errorf("%s.%s: %w", tmpl.Name, member.Name, err)
+		}
+		complete = complete && memberComplete
+		set[idx] = true
+	}
+	for _, idx := range tmpl.MemberMap {
+		if !set[idx] {
+			complete = false
+		}
+	}
+	return complete, nil
+}
+
+// encodeMember encodes one member's value at its offset in buf.
+func (c *Client) encodeMember(member *TemplateMember, value interface{}, buf []byte) (complete bool, err error) {
+	if int(member.Offset) > len(buf) {
+		return false, fmt.Errorf("offset %d outside structure", member.Offset)
+	}
+	data := buf[member.Offset:]
+
+	if member.IsArray() {
+		return c.encodeArrayMember(member, value, data)
+	}
+
+	if IsStructure(member.Type) {
+		nested, err := c.GetTemplate(member.Type)
+		if err != nil {
+			return false, err
+		}
+		if len(data) < int(nested.Size) {
+			return false, fmt.Errorf("structure %q does not fit", nested.Name)
+		}
+		return c.encodeStruct(nested, value, data[:nested.Size])
+	}
+
+	baseType := BaseType(member.Type)
+	elem, err := c.convertToType(value, baseType)
+	if err != nil {
+		return false, err
+	}
+	if baseType == TypeBOOL {
+		// BOOL members are bits of a hidden SINT shared with other BOOLs
+		if len(data) < 1 || member.BitOffset > 7 {
+			return false, fmt.Errorf("BOOL bit %d outside structure", member.BitOffset)
+		}
+		if elem[0] != 0 {
+			data[0] |= 1 << member.BitOffset
+		} else {
+			data[0] &^= 1 << member.BitOffset
+		}
+		return true, nil
+	}
+	if len(data) < len(elem) {
+		return false, fmt.Errorf("%s does not fit", TypeName(baseType))
+	}
+	copy(data, elem)
+	return true, nil
+}
+
+// encodeArrayMember encodes a slice into an array member. A shorter slice
+// sets the leading elements only.
+func (c *Client) encodeArrayMember(member *TemplateMember, value interface{}, data []byte) (complete bool, err error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false, fmt.Errorf("array member needs a slice, got %T", value)
+	}
+	count := member.ElementCount()
+	if rv.Len() > count {
+		return false, fmt.Errorf("%d elements for an array of %d", rv.Len(), count)
+	}
+
+	var nested *Template
+	elemSize := TypeSize(BaseType(member.Type))
+	if IsStructure(member.Type) {
+		if nested, err = c.GetTemplate(member.Type); err != nil {
+			return false, err
+		}
+		elemSize = int(nested.Size)
+	}
+	if elemSize == 0 {
+		return false, fmt.Errorf("unknown element size for type 0x%04X", member.Type)
+	}
+	if len(data) < count*elemSize {
+		return false, fmt.Errorf("array of %d does not fit", count)
+	}
+
+	complete = rv.Len() == count
+	for i := 0; i < rv.Len(); i++ {
+		elemData := data[i*elemSize : (i+1)*elemSize]
+		if nested != nil {
+			elemComplete, err := c.encodeStruct(nested, rv.Index(i).Interface(), elemData)
+			if err != nil {
+				return false, fmt.Errorf("[%d]: %w", i, err)
+			}
+			complete = complete && elemComplete
+			continue
+		}
+		elem, err := c.convertToType(rv.Index(i).Interface(), BaseType(member.Type))
+		if err != nil {
+			return false, fmt.Errorf("[%d]: %w", i, err)
+		}
+		copy(elemData, elem)
+	}
+	return complete, nil
+}
+
+// structMembers returns value's members by name: a map with string keys as
+// is, or the exported fields of a struct or struct pointer.
+func structMembers(value interface{}) (map[string]interface{}, bool) {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m, true
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m, true
+	case reflect.Struct:
+		m := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if tag := field.Tag.Get("logix"); tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			m[name] = rv.Field(i).Interface()
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+// memberIndexFold finds a visible member by case-insensitive name, as
+// Logix tag and member names are not case-sensitive.
+func memberIndexFold(tmpl *Template, name string) (int, bool) {
+	for memberName, idx := range tmpl.MemberMap {
+		if strings.EqualFold(memberName, name) {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+// isStringTemplate reports whether tmpl is a STRING-like structure: a DINT
+// LEN followed by a SINT array DATA.
+func isStringTemplate(tmpl *Template) bool {
+	lenMember, data := tmpl.GetMember("LEN"), tmpl.GetMember("DATA")
+	return lenMember != nil && data != nil && BaseType(lenMember.Type) == TypeDINT &&
+		BaseType(data.Type) == TypeSINT && data.IsArray()
+}
+
+// encodeStringStruct encodes s into a STRING-like structure, zeroing the
+// unused characters.
+func encodeStringStruct(tmpl *Template, s string, buf []byte) error {
+	lenMember, data := tmpl.GetMember("LEN"), tmpl.GetMember("DATA")
+	capacity := data.ElementCount()
+	if len(s) > capacity {
+		return fmt.Errorf("%s: string of %d characters exceeds %d", tmpl.Name, len(s), capacity)
+	}
+	if int(lenMember.Offset)+4 > len(buf) || int(data.Offset)+capacity > len(buf) {
+		return fmt.Errorf("%s: members outside structure", tmpl.Name)
+	}
+	binary.LittleEndian.PutUint32(buf[lenMember.Offset:], uint32(len(s)))
+	chars := buf[data.Offset : int(data.Offset)+capacity]
+	clear(chars)
+	copy(chars, s)
+	return nil
+}
@@ -0,0 +1,153 @@
+package logix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+// recipeClient returns a client with cached templates for a "Recipe" UDT:
+// two BOOLs in a hidden SINT, a DINT, a REAL, a STRING-like member and an
+// INT[3], plus a tag of that type.
+func recipeClient(conn *PLC) *Client {
+	str := &Template{
+		ID: 0x101, Name: "STR8", Size: 12, RawHandle: 0x2222,
+		Members: []TemplateMember{
+			{Name: "LEN", Type: TypeDINT, Offset: 0},
+			{Name: "DATA", Type: TypeSINT, Offset: 4, ArrayDims: []int{8}},
+		},
+		MemberMap: map[string]int{"LEN": 0, "DATA": 1},
+	}
+	recipe := &Template{
+		ID: 0x100, Name: "Recipe", Size: 32, RawHandle: 0x1111,
+		Members: []TemplateMember{
+			{Name: "ZZZZZZZZZZRecipe0", Type: TypeSINT, Offset: 0, Hidden: true},
+			{Name: "Enable", Type: TypeBOOL, Offset: 0, BitOffset: 0},
+			{Name: "Done", Type: TypeBOOL, Offset: 0, BitOffset: 1},
+			{Name: "Count", Type: TypeDINT, Offset: 4},
+			{Name: "Speed", Type: TypeREAL, Offset: 8},
+			{Name: "Label", Type: 0x8101, Offset: 12},
+			{Name: "Steps", Type: TypeINT, Offset: 24, ArrayDims: []int{3}},
+		},
+		MemberMap: map[string]int{"Enable": 1, "Done": 2, "Count": 3, "Speed": 4, "Label": 5, "Steps": 6},
+	}
+
+	c := &Client{plc: conn, templates: map[uint16]*Template{0x100: recipe, 0x101: str}}
+	c.SetTags([]TagInfo{{Name: "Recipe", TypeCode: 0x8100}})
+	return c
+}
+
+// structWriteServer answers Write Tag requests, recording the handle and
+// data, and Read Tag requests with current as the structure value.
+func structWriteServer(t *testing.T, current []byte, handle *uint16, written *[]byte) *PLC {
+	conn := startCIPServer(t, func(req []byte) []byte {
+		pathLen := int(req[1]) * 2
+		body := req[2+pathLen:]
+		switch req[0] {
+		case SvcReadTag:
+			resp := []byte{0xCC, 0, 0, 0, 0xA0, 0x02, 0x11, 0x11}
+			return append(resp, current...)
+		case SvcWriteTag:
+			if binary.LittleEndian.Uint16(body[0:2]) != CIPStructType {
+				t.Errorf("data type 0x%04X, want structure", binary.LittleEndian.Uint16(body[0:2]))
+			}
+			if n := binary.LittleEndian.Uint16(body[4:6]); n != 1 {
+				t.Errorf("count %d, want 1", n)
+			}
+			*handle = binary.LittleEndian.Uint16(body[2:4])
+			*written = append([]byte{}, body[6:]...)
+			return []byte{0xCD, 0, 0, 0}
+		}
+		return []byte{req[0] | 0x80, 0, StatusServiceNotSupport, 0}
+	})
+	return &PLC{Connection: conn}
+}
+
+func TestWriteStruct(t *testing.T) {
+	var handle uint16
+	var written []byte
+	c := recipeClient(structWriteServer(t, nil, &handle, &written))
+
+	err := c.WriteStruct("Recipe", map[string]interface{}{
+		"Enable": true,
+		"done":   false,
+		"Count":  int32(42),
+		"Speed":  1.5,
+		"Label":  "MIX",
+		"Steps":  []int{1, 2, 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handle != 0x1111 {
+		t.Errorf("handle 0x%04X, want 0x1111", handle)
+	}
+
+	want := make([]byte, 32)
+	want[0] = 0x01
+	binary.LittleEndian.PutUint32(want[4:], 42)
+	binary.LittleEndian.PutUint32(want[8:], math.Float32bits(1.5))
+	binary.LittleEndian.PutUint32(want[12:], 3)
+	copy(want[16:], "MIX")
+	binary.LittleEndian.PutUint16(want[24:], 1)
+	binary.LittleEndian.PutUint16(want[26:], 2)
+	binary.LittleEndian.PutUint16(want[28:], 3)
+	if !bytes.Equal(written, want) {
+		t.Errorf("data\n got % X\nwant % X", written, want)
+	}
+}
+
+func TestWriteStructPartial(t *testing.T) {
+	current := make([]byte, 32)
+	for i := range current {
+		current[i] = byte(i + 1) // Byte 0 has Enable set and Done clear
+	}
+	var handle uint16
+	var written []byte
+	c := recipeClient(structWriteServer(t, current, &handle, &written))
+
+	type update struct {
+		Done   bool
+		Total  int `logix:"Count"`
+		ignore int
+		Skip   int `logix:"-"`
+	}
+	if err := c.WriteStruct("Recipe", update{Done: true, Total: 7, Skip: 9}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := append([]byte{}, current...)
+	want[0] |= 0x02
+	binary.LittleEndian.PutUint32(want[4:], 7)
+	if !bytes.Equal(written, want) {
+		t.Errorf("data\n got % X\nwant % X", written, want)
+	}
+}
+
+func TestWriteStructErrors(t *testing.T) {
+	var handle uint16
+	var written []byte
+	c := recipeClient(structWriteServer(t, make([]byte, 32), &handle, &written))
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"unknown member", map[string]interface{}{"Missing": 1}, `no member "Missing"`},
+		{"string too long", map[string]interface{}{"Label": "TOO LONG!"}, "exceeds 8"},
+		{"array too long", map[string]interface{}{"Steps": []int{1, 2, 3, 4}}, "array of 3"},
+		{"not a structure", 42, "cannot encode int"},
+	}
+	for _, tt := range tests {
+		err := c.WriteStruct("Recipe", tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+	if written != nil {
+		t.Errorf("wrote % X after encoding errors", written)
+	}
+}
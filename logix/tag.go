@@ -91,7 +91,8 @@ func (t TagInfo) IsArray() bool {
 }
 
 // GetArrayDimensions fetches the array dimensions for a tag using Get Attribute Single.
-// First tries attribute 8 (byte count), then falls back to attribute 3 (dimensions).
+// First tries attribute 8 (byte count), then falls back to attribute 3 (dimensions);
+// multi-dimensional arrays try attribute 3 first so each dimension is known.
 // Returns nil for scalars. The instance ID must be from the tag's discovery.
 func (p *PLC) GetArrayDimensions(instance uint32, typeCode uint16) ([]int, error) {
 	// Check if this is an array type
@@ -101,6 +102,17 @@ func (p *PLC) GetArrayDimensions(instance uint32, typeCode uint16) ([]int, error
 
 	var attr8Err, attr3Err error
 
+	// The byte count only gives the total, so multi-dimensional arrays try
+	// attribute 3 (dimensions) first
+	numDims := ArrayDimensions(typeCode)
+	if numDims > 1 {
+		dims, err := p.getSymbolDimensions(instance, numDims)
+		if err == nil && len(dims) == numDims {
+			return dims, nil
+		}
+		attr3Err = err
+	}
+
 	// Try attribute 8 (byte count) - more widely supported
 	byteCount, err := p.getSymbolByteCount(instance)
 	if err != nil {
@@ -117,7 +129,6 @@ func (p *PLC) GetArrayDimensions(instance uint32, typeCode uint16) ([]int, error
 	}
 
 	// Fall back to attribute 3 (dimensions) for ControlLogix
-	numDims := ArrayDimensions(typeCode)
 	if numDims == 0 {
 		// Can't try attribute 3 - return attribute 8 error if we had one
 		if attr8Err != nil {
@@ -126,11 +137,13 @@ func (p *PLC) GetArrayDimensions(instance uint32, typeCode uint16) ([]int, error
 		return nil, nil
 	}
 
-	dims, err := p.getSymbolDimensions(instance, numDims)
-	if err != nil {
-		attr3Err = err
-	} else if len(dims) > 0 {
-		return dims, nil
+	if numDims == 1 {
+		dims, err := p.getSymbolDimensions(instance, numDims)
+		if err != nil {
+			attr3Err = err
+		} else if len(dims) > 0 {
+			return dims, nil
+		}
 	}
 
 	// Both failed - return combined error
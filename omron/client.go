@@ -3,6 +3,7 @@
 package omron
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/yatesdr/plcio/cip"
 	"github.com/yatesdr/plcio/eip"
+	"github.com/yatesdr/plcio/internal/ctxlock"
 	"github.com/yatesdr/plcio/logging"
 )
 
@@ -24,6 +26,10 @@ import (
 // (The EIP path already escalates connection errors on its own.)
 var ErrConnectionLost = errors.New("omron: connection lost during read")
 
+// ErrClockSetNotAllowed is returned by SetClock unless the client was
+// connected with WithAllowClockSet.
+var ErrClockSetNotAllowed = errors.New("omron: setting the PLC clock not enabled (use WithAllowClockSet)")
+
 // connErrorIfDownLocked returns a wrapped ErrConnectionLost when the underlying
 // transport has dropped, otherwise nil. It must be called with c.mu held (the
 // read paths hold it) and queries each transport's own state without re-locking
@@ -49,7 +55,7 @@ const (
 
 // finsTransport is the interface for FINS transports (UDP and TCP).
 type finsTransport interface {
-	connect(address string, port int, network, node, unit, srcNode byte) error
+	connect(ctx context.Context, address string, port int, network, node, unit, srcNode byte) error
 	close() error
 	isConnected() bool
 	setDisconnected()
@@ -60,9 +66,12 @@ type finsTransport interface {
 	writeBits(area byte, address uint16, bitOffset byte, bits []bool) error
 	readCPUStatus() (*CPUStatus, error)
 	readCycleTime() (*CycleTime, error)
+	readClock(loc *time.Location) (time.Time, error)
+	writeClock(t time.Time) error
 	connectionMode(address string, port int) string
 	getSourceNode() byte
 	setDebug(enabled bool)
+	setContext(ctx context.Context)
 }
 
 // Client represents a connection to an Omron PLC.
@@ -79,6 +88,9 @@ type Client struct {
 	debug     bool
 	connected bool
 
+	clockLoc      *time.Location // Time zone of the PLC clock (default time.Local)
+	allowClockSet bool           // Permit SetClock
+
 	// FINS transport (for UDP/TCP)
 	fins finsTransport
 
@@ -86,6 +98,12 @@ type Client struct {
 	eipClient *eip.EipClient
 	cipConn   *cip.Connection
 	connSize  uint16 // Connection size for connected messaging
+
+	// Context binding (see BindContext). ctx and eipRelease are guarded by
+	// mu; ctxMu serializes bindings.
+	ctx        context.Context
+	eipRelease func()
+	ctxMu      ctxlock.Mutex
 }
 
 // Option is a functional option for configuring the client.
@@ -133,8 +151,30 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithClockLocation sets the time zone the PLC clock runs in, used by
+// ReadClock and SetClock. The default is the local time zone of this host.
+func WithClockLocation(loc *time.Location) Option {
+	return func(c *Client) {
+		c.clockLoc = loc
+	}
+}
+
+// WithAllowClockSet enables SetClock, which changes the PLC clock. Without
+// it SetClock returns ErrClockSetNotAllowed.
+func WithAllowClockSet() Option {
+	return func(c *Client) {
+		c.allowClockSet = true
+	}
+}
+
 // Connect establishes a connection to an Omron PLC.
 func Connect(address string, opts ...Option) (*Client, error) {
+	return ConnectContext(context.Background(), address, opts...)
+}
+
+// ConnectContext is like Connect but aborts the TCP dial, FINS/TCP node
+// negotiation or EIP session setup when ctx is cancelled or its deadline passes.
+func ConnectContext(ctx context.Context, address string, opts ...Option) (*Client, error) {
 	c := &Client{
 		address:   address,
 		transport: TransportFINS, // Default to FINS (auto TCP/UDP)
@@ -149,6 +189,19 @@ func Connect(address string, opts ...Option) (*Client, error) {
 	logging.DebugLog("Omron", "Connect to %s transport=%s port=%d network=%d node=%d unit=%d srcNode=%d timeout=%v",
 		address, c.transport, c.port, c.network, c.node, c.unit, c.srcNode, c.timeout)
 
+	// Govern the connect sequence by ctx, then drop the binding so the
+	// returned client is not tied to the caller's context.
+	c.ctx = ctx
+	client, err := c.connect()
+	c.mu.Lock()
+	c.detachContextLocked()
+	c.ctx = nil
+	c.mu.Unlock()
+	return client, err
+}
+
+// connect dispatches to the configured transport's connect sequence.
+func (c *Client) connect() (*Client, error) {
 	switch c.transport {
 	case TransportFINS:
 		// Try TCP first (more reliable), fall back to UDP if TCP fails
@@ -199,13 +252,14 @@ func (c *Client) tryConnectFINSTCP() error {
 	t.timeout = c.timeout
 	t.debug = c.debug
 
-	if err := t.connect(c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
+	if err := t.connect(c.context(), c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
 		return err
 	}
 
 	c.fins = t
 	c.srcNode = t.getSourceNode()
 	c.connected = true
+	t.setContext(c.ctx)
 	return nil
 }
 
@@ -215,13 +269,14 @@ func (c *Client) tryConnectFINSUDP() error {
 	t.timeout = c.timeout
 	t.debug = c.debug
 
-	if err := t.connect(c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
+	if err := t.connect(c.context(), c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
 		return err
 	}
 
 	c.fins = t
 	c.srcNode = t.getSourceNode()
 	c.connected = true
+	t.setContext(c.ctx)
 	return nil
 }
 
@@ -231,13 +286,14 @@ func (c *Client) connectFINSUDP() (*Client, error) {
 	t.timeout = c.timeout
 	t.debug = c.debug
 
-	if err := t.connect(c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
+	if err := t.connect(c.context(), c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
 		return nil, err
 	}
 
 	c.fins = t
 	c.srcNode = t.getSourceNode()
 	c.connected = true
+	t.setContext(c.ctx)
 	return c, nil
 }
 
@@ -247,13 +303,14 @@ func (c *Client) connectFINSTCP() (*Client, error) {
 	t.timeout = c.timeout
 	t.debug = c.debug
 
-	if err := t.connect(c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
+	if err := t.connect(c.context(), c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
 		return nil, err
 	}
 
 	c.fins = t
 	c.srcNode = t.getSourceNode()
 	c.connected = true
+	t.setContext(c.ctx)
 	return c, nil
 }
 
@@ -272,11 +329,15 @@ func (c *Client) connectEIP() (*Client, error) {
 		return nil, fmt.Errorf("failed to set timeout: %w", err)
 	}
 
-	if err := c.eipClient.Connect(); err != nil {
+	if err := c.eipClient.ConnectContext(c.context()); err != nil {
 		logging.DebugLog("Omron", "EIP connect failed: %v", err)
 		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 
+	c.mu.Lock()
+	c.attachContextLocked()
+	c.mu.Unlock()
+
 	c.connected = true
 	logging.DebugLog("Omron", "EIP/CIP connection established to %s:%d", c.address, port)
 
@@ -303,6 +364,7 @@ func (c *Client) Close() error {
 	if c.cipConn != nil {
 		c.closeConnection()
 	}
+	c.detachContextLocked()
 
 	if c.fins != nil {
 		err := c.fins.close()
@@ -665,6 +727,7 @@ func (c *Client) Reconnect() error {
 	logging.DebugLog("Omron", "Reconnecting to %s (transport=%s)", c.address, c.transport)
 
 	// Close existing
+	c.detachContextLocked()
 	if c.fins != nil {
 		c.fins.close()
 		c.fins = nil
@@ -697,24 +760,26 @@ func (c *Client) Reconnect() error {
 		t := newUDPTransport()
 		t.timeout = c.timeout
 		t.debug = c.debug
-		if err := t.connect(c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
+		if err := t.connect(c.context(), c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
 			logging.DebugLog("Omron", "Reconnect FINS/UDP failed: %v", err)
 			return err
 		}
 		c.fins = t
 		c.connected = true
+		t.setContext(c.ctx)
 		logging.DebugLog("Omron", "Reconnect FINS/UDP successful")
 
 	case TransportFINSTCP:
 		t := newTCPTransport()
 		t.timeout = c.timeout
 		t.debug = c.debug
-		if err := t.connect(c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
+		if err := t.connect(c.context(), c.address, c.port, c.network, c.node, c.unit, c.srcNode); err != nil {
 			logging.DebugLog("Omron", "Reconnect FINS/TCP failed: %v", err)
 			return err
 		}
 		c.fins = t
 		c.connected = true
+		t.setContext(c.ctx)
 		logging.DebugLog("Omron", "Reconnect FINS/TCP successful")
 
 	case TransportEIP:
@@ -726,10 +791,11 @@ func (c *Client) Reconnect() error {
 		c.connSize = 0
 		c.eipClient = eip.NewEipClientWithPort(c.address, uint16(port))
 		c.eipClient.SetTimeout(c.timeout)
-		if err := c.eipClient.Connect(); err != nil {
+		if err := c.eipClient.ConnectContext(c.context()); err != nil {
 			logging.DebugLog("Omron", "Reconnect EIP failed: %v", err)
 			return err
 		}
+		c.attachContextLocked()
 		c.connected = true
 		logging.DebugLog("Omron", "Reconnect EIP session established")
 
@@ -1254,6 +1320,46 @@ func (c *Client) ReadCycleTime() (*CycleTime, error) {
 	return c.fins.readCycleTime()
 }
 
+// ReadClock reads the PLC clock (FINS only). The PLC clock has whole-second
+// resolution and no time zone; the result is in the WithClockLocation zone.
+func (c *Client) ReadClock() (time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fins == nil {
+		return time.Time{}, fmt.Errorf("clock only supported for FINS transport")
+	}
+
+	return c.fins.readClock(c.clockLocation())
+}
+
+// SetClock sets the PLC clock to t (FINS only). t is converted to the
+// WithClockLocation zone first, so DST is applied for the date being set.
+// Like logix.Client.SetClock it is opt-in: without WithAllowClockSet it
+// returns ErrClockSetNotAllowed.
+func (c *Client) SetClock(t time.Time) error {
+	if !c.allowClockSet {
+		return ErrClockSetNotAllowed
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fins == nil {
+		return fmt.Errorf("clock only supported for FINS transport")
+	}
+
+	return c.fins.writeClock(t.In(c.clockLocation()))
+}
+
+// clockLocation returns the time zone of the PLC clock.
+func (c *Client) clockLocation() *time.Location {
+	if c.clockLoc != nil {
+		return c.clockLoc
+	}
+	return time.Local
+}
+
 // isEIPConnectionError checks if an error indicates a dead EIP/CIP connection.
 func isEIPConnectionError(err error) bool {
 	if err == nil {
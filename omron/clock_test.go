@@ -0,0 +1,39 @@
+package omron
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClockEncoding(t *testing.T) {
+	loc := time.FixedZone("JST", 9*3600)
+	tm := time.Date(2026, 10, 16, 23, 59, 7, 0, loc) // Friday
+	data := BuildClockWriteRequest(tm)
+	if want := []byte{0x26, 0x10, 0x16, 0x23, 0x59, 0x07, 0x05}; !bytes.Equal(data, want) {
+		t.Errorf("BuildClockWriteRequest = % X, want % X", data, want)
+	}
+
+	got, err := ParseClock(data, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(tm) {
+		t.Errorf("ParseClock = %v, want %v", got, tm)
+	}
+
+	if got, _ := ParseClock([]byte{0x98, 0x01, 0x01, 0, 0, 0, 0x04}, time.UTC); got.Year() != 1998 {
+		t.Errorf("year 98 parsed as %d", got.Year())
+	}
+	if _, err := ParseClock([]byte{0x2A, 0x01, 0x01, 0, 0, 0}, time.UTC); err == nil {
+		t.Error("invalid BCD accepted")
+	}
+}
+
+func TestSetClockRequiresAllowClockSet(t *testing.T) {
+	c := &Client{}
+	if err := c.SetClock(time.Now()); !errors.Is(err, ErrClockSetNotAllowed) {
+		t.Errorf("SetClock = %v, want ErrClockSetNotAllowed", err)
+	}
+}
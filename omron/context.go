@@ -0,0 +1,129 @@
+package omron
+
+import (
+	"context"
+
+	"github.com/yatesdr/plcio/internal/ctxerr"
+)
+
+// BindContext ties the client's socket I/O to ctx until the returned release
+// function is called. While bound, each FINS command or CIP transaction uses
+// the earlier of the client timeout and the ctx deadline, and cancelling ctx
+// aborts a blocked send or receive. An aborted exchange marks the transport
+// disconnected (a late reply would otherwise be matched to the next request),
+// so the caller must Reconnect.
+//
+// Bindings are serialized: a second BindContext waits until the first is
+// released, or returns ctx.Err() if ctx ends first. Do not call the *Context methods while holding a binding.
+func (c *Client) BindContext(ctx context.Context) (release func(), err error) {
+	if c == nil || ctx == nil {
+		return func() {}, nil
+	}
+	if err := c.ctxMu.Lock(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.ctx = ctx
+	c.attachContextLocked()
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		c.detachContextLocked()
+		c.ctx = nil
+		c.mu.Unlock()
+		c.ctxMu.Unlock()
+	}, nil
+}
+
+// context returns the bound context, or context.Background if none.
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// attachContextLocked applies the bound context to the active transport.
+// Caller must hold c.mu.
+func (c *Client) attachContextLocked() {
+	if c.ctx == nil {
+		return
+	}
+	if c.fins != nil {
+		c.fins.setContext(c.ctx)
+	}
+	if c.eipClient != nil && c.eipRelease == nil {
+		// Only this client binds eipClient, so this fails only if c.ctx
+		// has already ended, which the next exchange reports anyway.
+		if release, err := c.eipClient.BindContext(c.ctx); err == nil {
+			c.eipRelease = release
+		}
+	}
+}
+
+// detachContextLocked removes the bound context from the active transport.
+// Caller must hold c.mu.
+func (c *Client) detachContextLocked() {
+	if c.fins != nil {
+		c.fins.setContext(nil)
+	}
+	if c.eipRelease != nil {
+		c.eipRelease()
+		c.eipRelease = nil
+	}
+}
+
+// ReadContext is like Read but honors ctx cancellation and deadline.
+func (c *Client) ReadContext(ctx context.Context, addresses ...string) ([]*TagValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	values, err := c.Read(addresses...)
+	return values, ctxerr.Wrap(ctx, err)
+}
+
+// ReadWithTypesContext is like ReadWithTypes but honors ctx cancellation and deadline.
+func (c *Client) ReadWithTypesContext(ctx context.Context, requests []TagRequest) ([]*TagValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	values, err := c.ReadWithTypes(requests)
+	return values, ctxerr.Wrap(ctx, err)
+}
+
+// WriteContext is like WriteWithType but honors ctx cancellation and deadline.
+func (c *Client) WriteContext(ctx context.Context, address string, value interface{}, typeHint string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return ctxerr.Wrap(ctx, c.WriteWithType(address, value, typeHint))
+}
+
+// AllTagsContext is like AllTags but honors ctx cancellation and deadline.
+func (c *Client) AllTagsContext(ctx context.Context) ([]TagInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	tags, err := c.AllTags()
+	return tags, ctxerr.Wrap(ctx, err)
+}
@@ -3,6 +3,7 @@ package omron
 import (
 	"encoding/binary"
 	"fmt"
+	"time"
 
 	"github.com/yatesdr/plcio/logging"
 )
@@ -15,6 +16,8 @@ const (
 	FINSCmdCPURead         uint16 = 0x0501
 	FINSCmdCPUStatus       uint16 = 0x0601
 	FINSCmdCycleTime       uint16 = 0x0620
+	FINSCmdClockRead       uint16 = 0x0701
+	FINSCmdClockWrite      uint16 = 0x0702
 )
 
 // FINS end codes.
@@ -479,3 +482,42 @@ func ParseCycleTime(data []byte) (*CycleTime, error) {
 		Min:     binary.BigEndian.Uint32(data[8:12]),
 	}, nil
 }
+
+// ParseClock parses a clock read response: year, month, day, hour, minute,
+// second and day of week, each one BCD byte. The PLC clock has no time zone,
+// so the result is in loc. Two-digit years 70-99 are 1970-1999, 00-69 are
+// 2000-2069.
+func ParseClock(data []byte, loc *time.Location) (time.Time, error) {
+	if len(data) < 6 {
+		return time.Time{}, fmt.Errorf("clock response too short")
+	}
+	var v [6]int
+	for i := range v {
+		b := data[i]
+		if b>>4 > 9 || b&0x0F > 9 {
+			return time.Time{}, fmt.Errorf("clock response has invalid BCD byte 0x%02X", b)
+		}
+		v[i] = int(b>>4)*10 + int(b&0x0F)
+	}
+	year := 2000 + v[0]
+	if v[0] >= 70 {
+		year = 1900 + v[0]
+	}
+	return time.Date(year, time.Month(v[1]), v[2], v[3], v[4], v[5], 0, loc), nil
+}
+
+// BuildClockWriteRequest builds the data for a clock write: t's year,
+// month, day, hour, minute, second and day of week as BCD bytes. t should
+// already be in the PLC's time zone.
+func BuildClockWriteRequest(t time.Time) []byte {
+	bcd := func(v int) byte { return byte(v/10)<<4 | byte(v%10) }
+	return []byte{
+		bcd(t.Year() % 100),
+		bcd(int(t.Month())),
+		bcd(t.Day()),
+		bcd(t.Hour()),
+		bcd(t.Minute()),
+		bcd(t.Second()),
+		bcd(int(t.Weekday())),
+	}
+}
@@ -1,6 +1,7 @@
 package omron
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -37,6 +38,10 @@ type tcpTransport struct {
 	timeout    time.Duration
 	debug      bool
 	connected  bool
+
+	// ctx, when set, bounds socket deadlines and aborts blocked I/O on
+	// cancellation (see Client.BindContext). Guarded by mu.
+	ctx context.Context
 }
 
 // newTCPTransport creates a new TCP transport.
@@ -47,7 +52,8 @@ func newTCPTransport() *tcpTransport {
 }
 
 // connect establishes the TCP connection.
-func (t *tcpTransport) connect(address string, port int, network, node, unit, srcNode byte) error {
+// ctx bounds the TCP dial and the node address negotiation.
+func (t *tcpTransport) connect(ctx context.Context, address string, port int, network, node, unit, srcNode byte) error {
 	if port <= 0 {
 		port = defaultFINSPort
 	}
@@ -64,7 +70,8 @@ func (t *tcpTransport) connect(address string, port int, network, node, unit, sr
 	logging.DebugConnect("FINS/TCP", addr)
 	logging.DebugLog("FINS/TCP", "Connection params: network=%d, node=%d, unit=%d, srcNode=%d", network, node, unit, srcNode)
 
-	conn, err := net.DialTimeout("tcp", addr, t.timeout)
+	d := net.Dialer{Timeout: t.timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
 	if err != nil {
 		logging.DebugConnectError("FINS/TCP", addr, err)
 		return fmt.Errorf("failed to connect: %w", err)
@@ -73,10 +80,18 @@ func (t *tcpTransport) connect(address string, port int, network, node, unit, sr
 
 	logging.DebugLog("FINS/TCP", "TCP connection established to %s", addr)
 
-	// Perform node address negotiation
-	if err := t.negotiateNodeAddress(); err != nil {
+	// Perform node address negotiation under the caller's context
+	t.ctx = ctx
+	stop := t.watchContext()
+	err = t.negotiateNodeAddress()
+	stop()
+	t.ctx = nil
+	if err != nil {
 		conn.Close()
 		logging.DebugError("FINS/TCP", "node address negotiation", err)
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
 		return fmt.Errorf("node address negotiation failed: %w", err)
 	}
 
@@ -118,8 +133,8 @@ func (t *tcpTransport) negotiateNodeAddress() error {
 	logging.DebugTX("FINS/TCP", req)
 
 	// Set deadline
-	if t.timeout > 0 {
-		t.conn.SetDeadline(time.Now().Add(t.timeout))
+	if deadline := t.ioDeadline(); !deadline.IsZero() {
+		t.conn.SetDeadline(deadline)
 	}
 
 	// Send request
@@ -223,6 +238,50 @@ func (t *tcpTransport) setDisconnected() {
 	t.connected = false
 }
 
+// setContext binds ctx to subsequent commands; nil clears the binding.
+func (t *tcpTransport) setContext(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ctx = ctx
+}
+
+// ioDeadline returns the transport timeout, shortened to the bound context's
+// deadline if that is sooner. A zero time means no deadline. Caller must hold t.mu.
+func (t *tcpTransport) ioDeadline() time.Time {
+	var deadline time.Time
+	if t.timeout > 0 {
+		deadline = time.Now().Add(t.timeout)
+	}
+	if t.ctx != nil {
+		if d, ok := t.ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+	}
+	return deadline
+}
+
+// withContextErr attaches the bound context's error to an I/O failure caused
+// by cancellation so callers can match it with errors.Is. Caller must hold t.mu.
+func (t *tcpTransport) withContextErr(err error) error {
+	if t.ctx != nil && t.ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", t.ctx.Err(), err)
+	}
+	return err
+}
+
+// watchContext unblocks pending socket I/O when the bound context is
+// cancelled. The returned function stops the watch. Caller must hold t.mu.
+func (t *tcpTransport) watchContext() (stop func()) {
+	if t.ctx == nil || t.ctx.Done() == nil || t.conn == nil {
+		return func() {}
+	}
+	conn := t.conn
+	stopWatch := context.AfterFunc(t.ctx, func() {
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	})
+	return func() { stopWatch() }
+}
+
 // nextSID returns the next service ID.
 func (t *tcpTransport) nextSID() byte {
 	return byte(atomic.AddUint32(&t.sid, 1) & 0xFF)
@@ -241,6 +300,13 @@ func (t *tcpTransport) sendCommand(command uint16, data []byte) ([]byte, error)
 		logging.DebugLog("FINS/TCP", "sendCommand called but not connected")
 		return nil, fmt.Errorf("not connected")
 	}
+	if t.ctx != nil && t.ctx.Err() != nil {
+		return nil, t.ctx.Err()
+	}
+
+	// Abort blocked I/O if the bound context is cancelled mid-exchange
+	stop := t.watchContext()
+	defer stop()
 
 	sid := t.nextSID()
 
@@ -281,15 +347,15 @@ func (t *tcpTransport) sendCommand(command uint16, data []byte) ([]byte, error)
 	logging.DebugTX("FINS/TCP", tcpFrame)
 
 	// Set deadline
-	if t.timeout > 0 {
-		t.conn.SetDeadline(time.Now().Add(t.timeout))
+	if deadline := t.ioDeadline(); !deadline.IsZero() {
+		t.conn.SetDeadline(deadline)
 	}
 
 	// Send
 	if _, err := t.conn.Write(tcpFrame); err != nil {
 		t.connected = false
 		logging.DebugDisconnect("FINS/TCP", t.address, fmt.Sprintf("send failed: %v", err))
-		return nil, fmt.Errorf("failed to send: %w", err)
+		return nil, fmt.Errorf("failed to send: %w", t.withContextErr(err))
 	}
 
 	// Read response header
@@ -297,7 +363,7 @@ func (t *tcpTransport) sendCommand(command uint16, data []byte) ([]byte, error)
 	if _, err := io.ReadFull(t.conn, respHeader); err != nil {
 		t.connected = false
 		logging.DebugDisconnect("FINS/TCP", t.address, fmt.Sprintf("recv header failed: %v", err))
-		return nil, fmt.Errorf("failed to read response header: %w", err)
+		return nil, fmt.Errorf("failed to read response header: %w", t.withContextErr(err))
 	}
 
 	// Verify magic
@@ -336,7 +402,7 @@ func (t *tcpTransport) sendCommand(command uint16, data []byte) ([]byte, error)
 	if _, err := io.ReadFull(t.conn, respFrame); err != nil {
 		t.connected = false
 		logging.DebugDisconnect("FINS/TCP", t.address, fmt.Sprintf("recv frame failed: %v", err))
-		return nil, fmt.Errorf("failed to read FINS response: %w", err)
+		return nil, fmt.Errorf("failed to read FINS response: %w", t.withContextErr(err))
 	}
 
 	// Log complete received packet
@@ -445,6 +511,21 @@ func (t *tcpTransport) readCycleTime() (*CycleTime, error) {
 	return ParseCycleTime(resp)
 }
 
+// readClock reads the PLC clock as a time in loc.
+func (t *tcpTransport) readClock(loc *time.Location) (time.Time, error) {
+	resp, err := t.sendCommand(FINSCmdClockRead, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ParseClock(resp, loc)
+}
+
+// writeClock sets the PLC clock.
+func (t *tcpTransport) writeClock(tm time.Time) error {
+	_, err := t.sendCommand(FINSCmdClockWrite, BuildClockWriteRequest(tm))
+	return err
+}
+
 // connectionMode returns a description of the connection.
 func (t *tcpTransport) connectionMode(address string, port int) string {
 	return fmt.Sprintf("FINS/TCP %s:%d (NET:%d NODE:%d UNIT:%d, LOCAL:%d)",
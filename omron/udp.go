@@ -1,6 +1,7 @@
 package omron
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"net"
@@ -28,6 +29,10 @@ type udpTransport struct {
 	timeout   time.Duration
 	debug     bool
 	connected bool
+
+	// ctx, when set, bounds socket deadlines and aborts a blocked receive on
+	// cancellation (see Client.BindContext). Guarded by mu.
+	ctx context.Context
 }
 
 // newUDPTransport creates a new UDP transport.
@@ -38,10 +43,14 @@ func newUDPTransport() *udpTransport {
 }
 
 // connect establishes the UDP connection.
-func (t *udpTransport) connect(address string, port int, network, node, unit, srcNode byte) error {
+// UDP has no handshake, so ctx is only checked before the socket is opened.
+func (t *udpTransport) connect(ctx context.Context, address string, port int, network, node, unit, srcNode byte) error {
 	if port <= 0 {
 		port = defaultFINSPort
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	addr := fmt.Sprintf("%s:%d", address, port)
 	logging.DebugConnect("FINS/UDP", addr)
@@ -134,6 +143,22 @@ func (t *udpTransport) setDisconnected() {
 	t.connected = false
 }
 
+// setContext binds ctx to subsequent commands; nil clears the binding.
+func (t *udpTransport) setContext(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ctx = ctx
+}
+
+// withContextErr attaches the bound context's error to an I/O failure caused
+// by cancellation so callers can match it with errors.Is. Caller must hold t.mu.
+func (t *udpTransport) withContextErr(err error) error {
+	if t.ctx != nil && t.ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", t.ctx.Err(), err)
+	}
+	return err
+}
+
 // nextSID returns the next service ID.
 func (t *udpTransport) nextSID() byte {
 	return byte(atomic.AddUint32(&t.sid, 1) & 0xFF)
@@ -149,6 +174,9 @@ func (t *udpTransport) sendCommand(command uint16, data []byte) ([]byte, error)
 		logging.DebugLog("FINS/UDP", "sendCommand called but not connected")
 		return nil, fmt.Errorf("not connected")
 	}
+	if t.ctx != nil && t.ctx.Err() != nil {
+		return nil, t.ctx.Err()
+	}
 
 	sid := t.nextSID()
 
@@ -179,16 +207,34 @@ func (t *udpTransport) sendCommand(command uint16, data []byte) ([]byte, error)
 	frameBytes := frame.Bytes()
 	logging.DebugTX("FINS/UDP", frameBytes)
 
-	// Set deadline
+	// Set deadline, shortened to the bound context's deadline if sooner
+	var deadline time.Time
 	if t.timeout > 0 {
-		t.conn.SetDeadline(time.Now().Add(t.timeout))
+		deadline = time.Now().Add(t.timeout)
+	}
+	if t.ctx != nil {
+		if d, ok := t.ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+	}
+	if !deadline.IsZero() {
+		t.conn.SetDeadline(deadline)
+	}
+
+	// Abort a blocked receive if the bound context is cancelled
+	if t.ctx != nil && t.ctx.Done() != nil {
+		conn := t.conn
+		stop := context.AfterFunc(t.ctx, func() {
+			_ = conn.SetDeadline(time.Unix(1, 0))
+		})
+		defer stop()
 	}
 
 	// Send
 	if _, err := t.conn.WriteToUDP(frameBytes, t.plcAddr); err != nil {
 		t.connected = false
 		logging.DebugDisconnect("FINS/UDP", t.plcAddr.String(), fmt.Sprintf("send failed: %v", err))
-		return nil, fmt.Errorf("failed to send: %w", err)
+		return nil, fmt.Errorf("failed to send: %w", t.withContextErr(err))
 	}
 
 	// Receive response
@@ -197,7 +243,7 @@ func (t *udpTransport) sendCommand(command uint16, data []byte) ([]byte, error)
 	if err != nil {
 		t.connected = false
 		logging.DebugDisconnect("FINS/UDP", t.plcAddr.String(), fmt.Sprintf("recv failed: %v", err))
-		return nil, fmt.Errorf("failed to receive: %w", err)
+		return nil, fmt.Errorf("failed to receive: %w", t.withContextErr(err))
 	}
 
 	logging.DebugRX("FINS/UDP", buf[:n])
@@ -304,6 +350,21 @@ func (t *udpTransport) readCycleTime() (*CycleTime, error) {
 	return ParseCycleTime(resp)
 }
 
+// readClock reads the PLC clock as a time in loc.
+func (t *udpTransport) readClock(loc *time.Location) (time.Time, error) {
+	resp, err := t.sendCommand(FINSCmdClockRead, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ParseClock(resp, loc)
+}
+
+// writeClock sets the PLC clock.
+func (t *udpTransport) writeClock(tm time.Time) error {
+	_, err := t.sendCommand(FINSCmdClockWrite, BuildClockWriteRequest(tm))
+	return err
+}
+
 // connectionMode returns a description of the connection.
 func (t *udpTransport) connectionMode(address string, port int) string {
 	return fmt.Sprintf("FINS/UDP %s:%d (NET:%d NODE:%d UNIT:%d, LOCAL:%d)",
@@ -1,6 +1,7 @@
 package pccc
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -93,6 +94,12 @@ func WithMicroLogix() Option {
 //	client, err := pccc.Connect("192.168.1.100", pccc.WithPLC5())
 //	client, err := pccc.Connect("192.168.1.100", pccc.WithTimeout(10*time.Second))
 func Connect(address string, opts ...Option) (*Client, error) {
+	return ConnectContext(context.Background(), address, opts...)
+}
+
+// ConnectContext is like Connect but aborts the TCP dial and session
+// registration when ctx is cancelled or its deadline passes.
+func ConnectContext(ctx context.Context, address string, opts ...Option) (*Client, error) {
 	cfg := &options{
 		vendorID:  0x0001, // Default vendor ID
 		serialNum: 0x12345678,
@@ -113,7 +120,7 @@ func Connect(address string, opts ...Option) (*Client, error) {
 		eipClient.SetTimeout(cfg.timeout)
 	}
 
-	if err := eipClient.Connect(); err != nil {
+	if err := eipClient.ConnectContext(ctx); err != nil {
 		return nil, fmt.Errorf("Connect: %w", err)
 	}
 
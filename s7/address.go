@@ -17,6 +17,10 @@ const (
 	AreaM              // Merker/Flag (MB, MW, MD)
 	AreaT              // Timer
 	AreaC              // Counter
+	AreaDI             // Instance Data Block (DI5.DIW0)
+	AreaPI             // Peripheral Input, read directly from the I/O (PIW256)
+	AreaPQ             // Peripheral Output, written directly to the I/O (PQW256)
+	AreaV              // V memory of LOGO! and S7-200 (VW20), served as DB1
 )
 
 // String returns the area name.
@@ -34,6 +38,14 @@ func (a Area) String() string {
 		return "T"
 	case AreaC:
 		return "C"
+	case AreaDI:
+		return "DI"
+	case AreaPI:
+		return "PI"
+	case AreaPQ:
+		return "PQ"
+	case AreaV:
+		return "V"
 	default:
 		return "?"
 	}
@@ -41,8 +53,8 @@ func (a Area) String() string {
 
 // Address represents a parsed S7 memory address.
 type Address struct {
-	Area     Area   // Memory area (DB, I, Q, M, T, C)
-	DBNumber int    // Data block number (only for AreaDB)
+	Area     Area   // Memory area (DB, DI, I, Q, M, T, C, PI, PQ, V)
+	DBNumber int    // Data block number (AreaDB and AreaDI; 1 for AreaV)
 	Offset   int    // Byte offset
 	BitNum   int    // Bit number (0-7 for BOOL, -1 for other types)
 	DataType uint16 // Inferred data type
@@ -58,11 +70,17 @@ var (
 	// Simple DB addresses: DB1.0 or DB1.0[6] (offset only, type from config, optional array count)
 	reDBSimple = regexp.MustCompile(`^DB(\d+)\.(\d+)(?:\[(\d+)\])?$`)
 
-	// I/Q/M addresses: M0.0 (bit), MB0 (byte), MW0 (word), MD0 (dword)
-	reIQM = regexp.MustCompile(`^([IQM])([XBWDL])?(\d+)(?:\.(\d))?$`)
+	// I/Q/M/V addresses: M0.0 (bit), MB0 (byte), MW0 (word), MD0 (dword)
+	reIQM = regexp.MustCompile(`^([IQMV])([XBWDL])?(\d+)(?:\.(\d))?$`)
 
 	// Timer/Counter: T0, C0
 	reTC = regexp.MustCompile(`^([TC])(\d+)$`)
+
+	// Instance DB addresses: DI5.DIX0.0, DI5.DIB0, DI5.DIW0, DI5.DID0
+	reDI = regexp.MustCompile(`^DI(\d+)\.DI([XBWDL])(\d+)(?:\.(\d))?$`)
+
+	// Peripheral I/O: PIB0, PIW256, PID0, PQB0, PQW256, PQD0 (no bit access)
+	rePeriph = regexp.MustCompile(`^P([IQ])([BWD])(\d+)$`)
 )
 
 // ParseAddress parses an S7 address string and returns an Address.
@@ -72,15 +90,28 @@ var (
 //   - DB1.DBB0   - Data Block byte
 //   - DB1.DBW0   - Data Block word
 //   - DB1.DBD0   - Data Block dword
+//   - DI5.DIX0.0, DI5.DIB0, DI5.DIW0, DI5.DID0 - Instance Data Block
 //   - M0.0       - Merker bit
 //   - MB0        - Merker byte
 //   - MW0        - Merker word
 //   - MD0        - Merker dword
 //   - I0.0, IB0, IW0, ID0 - Input
 //   - Q0.0, QB0, QW0, QD0 - Output
+//   - PIB0, PIW256, PID0 - Peripheral input (read only)
+//   - PQB0, PQW256, PQD0 - Peripheral output (write only)
+//   - V0.1, VB10, VW20, VD30 - V memory (LOGO!, S7-200 Smart)
 //   - T0         - Timer
 //   - C0         - Counter
+//
+// Symbolic names such as DB_Motor.Speed are resolved against layouts added
+// with RegisterLayout.
 func ParseAddress(addr string) (*Address, error) {
+	var layout *Layout
+	return layout.ParseAddress(addr)
+}
+
+// parseAbsoluteAddress parses an absolute S7 address (see ParseAddress).
+func parseAbsoluteAddress(addr string) (*Address, error) {
 	addr = strings.ToUpper(strings.TrimSpace(addr))
 	if addr == "" {
 		return nil, fmt.Errorf("empty address")
@@ -96,6 +127,27 @@ func ParseAddress(addr string) (*Address, error) {
 		return parseDBAddress(m)
 	}
 
+	// Try instance DB address (DI5.DIW0 format)
+	if m := reDI.FindStringSubmatch(addr); m != nil {
+		a, err := parseDBAddress(m)
+		if err == nil {
+			a.Area = AreaDI
+		}
+		return a, err
+	}
+
+	// Try peripheral I/O address
+	if m := rePeriph.FindStringSubmatch(addr); m != nil {
+		a, err := parseIQMAddress([]string{m[0], m[1], m[2], m[3], ""})
+		if err == nil {
+			a.Area = AreaPI
+			if m[1] == "Q" {
+				a.Area = AreaPQ
+			}
+		}
+		return a, err
+	}
+
 	// Try I/Q/M address
 	if m := reIQM.FindStringSubmatch(addr); m != nil {
 		return parseIQMAddress(m)
@@ -188,6 +240,8 @@ func parseIQMAddress(m []string) (*Address, error) {
 		area = AreaQ
 	case "M":
 		area = AreaM
+	case "V":
+		area = AreaV
 	}
 
 	typeLetter := m[2]
@@ -202,6 +256,9 @@ func parseIQMAddress(m []string) (*Address, error) {
 		BitNum: -1,
 		Count:  1, // Scalar by default
 	}
+	if area == AreaV {
+		addr.DBNumber = 1 // LOGO! and S7-200 map V memory to DB1
+	}
 
 	switch typeLetter {
 	case "X":
@@ -40,6 +40,23 @@ func TestParseAddress(t *testing.T) {
 		{"QW0", false, AreaQ, 0, 0, -1, TypeWord},
 		{"QD0", false, AreaQ, 0, 0, -1, TypeDWord},
 
+		// Instance DB addresses
+		{"DI5.DIX0.1", false, AreaDI, 5, 0, 1, TypeBool},
+		{"DI5.DIB2", false, AreaDI, 5, 2, -1, TypeByte},
+		{"DI5.DIW4", false, AreaDI, 5, 4, -1, TypeWord},
+		{"DI5.DID8", false, AreaDI, 5, 8, -1, TypeDWord},
+
+		// Peripheral I/O
+		{"PIB0", false, AreaPI, 0, 0, -1, TypeByte},
+		{"PIW256", false, AreaPI, 0, 256, -1, TypeWord},
+		{"PQD4", false, AreaPQ, 0, 4, -1, TypeDWord},
+
+		// V memory (LOGO!, S7-200 Smart), served as DB1
+		{"V0.1", false, AreaV, 1, 0, 1, TypeBool},
+		{"VB10", false, AreaV, 1, 10, -1, TypeByte},
+		{"VW20", false, AreaV, 1, 20, -1, TypeWord},
+		{"VD30", false, AreaV, 1, 30, -1, TypeDWord},
+
 		// Timers and counters
 		{"T0", false, AreaT, 0, 0, -1, TypeWord},
 		{"T100", false, AreaT, 0, 100, -1, TypeWord},
@@ -51,6 +68,7 @@ func TestParseAddress(t *testing.T) {
 		{"invalid", true, 0, 0, 0, 0, 0},
 		{"DB1.DBX0.8", true, 0, 0, 0, 0, 0}, // Bit > 7
 		{"DB1.DBX0", true, 0, 0, 0, 0, 0},   // DBX without bit
+		{"PI0.0", true, 0, 0, 0, 0, 0},      // No peripheral bit access
 	}
 
 	for _, tt := range tests {
@@ -85,3 +103,16 @@ func TestParseAddress(t *testing.T) {
 	}
 }
 
+func TestVAreaS7Any(t *testing.T) {
+	addr, err := ParseAddress("VW20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := addressToS7Any(addr)
+	if db := int(item[6])<<8 | int(item[7]); db != 1 || item[8] != s7AreaDB {
+		t.Errorf("VW20 encodes as area 0x%02X DB%d, want DB1 (0x%02X)", item[8], db, s7AreaDB)
+	}
+	if bitAddr := int(item[9])<<16 | int(item[10])<<8 | int(item[11]); bitAddr != 20*8 {
+		t.Errorf("VW20 bit address %d, want %d", bitAddr, 20*8)
+	}
+}
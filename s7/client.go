@@ -1,13 +1,16 @@
 package s7
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/yatesdr/plcio/internal/ctxlock"
 	"github.com/yatesdr/plcio/logging"
 )
 
@@ -16,7 +19,8 @@ import (
 // return a nil top-level error, so callers cannot tell a lost link from a
 // single bad address. When the transport has dropped, the read methods surface
 // this error at the top level — alongside any partial results — so callers can
-// reconnect. Detect it with errors.Is(err, ErrConnectionLost).
+// reconnect. WriteMany reports a dropped link the same way. Detect it with
+// errors.Is(err, ErrConnectionLost).
 var ErrConnectionLost = errors.New("s7: connection lost during read")
 
 // connErrorIfDownLocked returns a wrapped ErrConnectionLost when the underlying
@@ -38,13 +42,31 @@ type Client struct {
 	slot      int
 	pduRef    uint16
 	mu        sync.Mutex
+
+	localTSAP  uint16 // Explicit TSAPs (see WithTSAP); 0 derives them from rack/slot
+	remoteTSAP uint16
+
+	allowControl bool // Permit Stop/HotStart/ColdStart
+	readGap      int  // Max unused bytes between merged reads; negative disables merging
+
+	layout atomic.Pointer[Layout] // Symbolic DB layout (see SetLayout)
+
+	// Context binding (see BindContext). ctx is guarded by mu; ctxMu
+	// serializes bindings.
+	ctx   context.Context
+	ctxMu ctxlock.Mutex
 }
 
 // options holds configuration options for Connect.
 type options struct {
-	rack    int
-	slot    int
-	timeout time.Duration
+	rack         int
+	slot         int
+	timeout      time.Duration
+	allowControl bool
+	readGap      int
+	layout       *Layout
+	localTSAP    uint16
+	remoteTSAP   uint16
 }
 
 // Option is a functional option for Connect.
@@ -60,6 +82,17 @@ func WithRackSlot(rack, slot int) Option {
 	}
 }
 
+// WithTSAP sets the local and remote TSAPs of the COTP connection instead of
+// deriving the remote TSAP from rack and slot. LOGO! and S7-200 Smart need an
+// explicit pair, e.g. WithTSAP(0x0100, 0x0200) for a LOGO! 0BA7/0BA8 whose
+// server connection uses TSAP 02.00.
+func WithTSAP(local, remote uint16) Option {
+	return func(o *options) {
+		o.localTSAP = local
+		o.remoteTSAP = remote
+	}
+}
+
 // WithTimeout configures the connection timeout.
 func WithTimeout(d time.Duration) Option {
 	return func(o *options) {
@@ -67,8 +100,42 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithAllowControl enables Stop, HotStart and ColdStart, which change the
+// operating mode of the CPU. Switching a production controller's mode is
+// disruptive, so without it those calls return ErrControlNotAllowed.
+func WithAllowControl() Option {
+	return func(o *options) {
+		o.allowControl = true
+	}
+}
+
+// WithReadGap sets how many unused bytes may separate two addresses in the
+// same area before reads stop merging them into one byte-range read.
+// Reading a few spare bytes is usually cheaper than an extra S7ANY item.
+// The default 0 merges only adjacent or overlapping addresses; a negative
+// value disables merging.
+func WithReadGap(bytes int) Option {
+	return func(o *options) {
+		o.readGap = bytes
+	}
+}
+
+// WithLayout resolves symbolic names such as DB_Motor.Speed against layout
+// (see SetLayout).
+func WithLayout(layout *Layout) Option {
+	return func(o *options) {
+		o.layout = layout
+	}
+}
+
 // Connect establishes a connection to an S7 PLC at the given address.
 func Connect(address string, opts ...Option) (*Client, error) {
+	return ConnectContext(context.Background(), address, opts...)
+}
+
+// ConnectContext is like Connect but aborts the TCP dial and the COTP/S7
+// setup handshake when ctx is cancelled or its deadline passes.
+func ConnectContext(ctx context.Context, address string, opts ...Option) (*Client, error) {
 	// Apply options
 	// Default to slot 2 for S7-300/400 (CPU typically in slot 2)
 	// S7-1200/1500 users should explicitly set slot 0 (integrated CPU)
@@ -83,18 +150,28 @@ func Connect(address string, opts ...Option) (*Client, error) {
 
 	t := newTransport()
 	t.timeout = cfg.timeout
+	t.localTSAP = cfg.localTSAP
+	t.remoteTSAP = cfg.remoteTSAP
 
-	if err := t.connect(address, cfg.rack, cfg.slot); err != nil {
+	if err := t.connect(ctx, address, cfg.rack, cfg.slot); err != nil {
 		return nil, fmt.Errorf("Connect: %w", err)
 	}
 
-	return &Client{
+	c := &Client{
 		transport: t,
 		address:   address,
 		rack:      cfg.rack,
 		slot:      cfg.slot,
 		pduRef:    0,
-	}, nil
+
+		localTSAP:  cfg.localTSAP,
+		remoteTSAP: cfg.remoteTSAP,
+
+		allowControl: cfg.allowControl,
+		readGap:      cfg.readGap,
+	}
+	c.layout.Store(cfg.layout)
+	return c, nil
 }
 
 // Close releases all resources associated with the client.
@@ -155,18 +232,22 @@ func (c *Client) Reconnect() error {
 	address := c.address
 	rack := c.rack
 	slot := c.slot
+	ctx := c.ctx
 	c.mu.Unlock()
 
 	// Create new transport
 	t := newTransport()
 	t.timeout = 10 * time.Second
+	t.localTSAP = c.localTSAP
+	t.remoteTSAP = c.remoteTSAP
 
-	if err := t.connect(address, rack, slot); err != nil {
+	if err := t.connect(ctx, address, rack, slot); err != nil {
 		return fmt.Errorf("reconnect failed: %w", err)
 	}
 
 	c.mu.Lock()
 	c.transport = t
+	t.setContext(c.ctx)
 	c.mu.Unlock()
 
 	return nil
@@ -181,7 +262,11 @@ func (c *Client) ConnectionMode() string {
 	connected := c.transport != nil && c.transport.isConnected()
 	rack := c.rack
 	slot := c.slot
+	local, remote := c.localTSAP, c.remoteTSAP
 	c.mu.Unlock()
+	if connected && remote != 0 {
+		return fmt.Sprintf("S7 Connected (TSAP %04X/%04X)", local, remote)
+	}
 	if connected {
 		return fmt.Sprintf("S7 Connected (Rack %d, Slot %d)", rack, slot)
 	}
@@ -221,6 +306,8 @@ type parsedRequest struct {
 	addr     *Address
 	readAddr *Address // Address with totalSize calculated
 	err      error    // Parse error if any
+
+	members []parsedRequest // Requests served by this merged block read (see planReads)
 }
 
 // ReadWithTypes reads addresses with optional type hints.
@@ -243,12 +330,16 @@ func (c *Client) ReadWithTypes(requests []TagRequest) ([]*TagValue, error) {
 		parsed[i].index = i
 		parsed[i].request = req
 
-		addr, err := ParseAddress(req.Address)
+		addr, err := c.Layout().ParseAddress(req.Address)
 		if err != nil {
 			logging.DebugLog("S7", "ParseAddress failed for %q: %v", req.Address, err)
 			parsed[i].err = err
 			continue
 		}
+		if addr.Area == AreaPQ {
+			parsed[i].err = fmt.Errorf("%s: peripheral outputs cannot be read", req.Address)
+			continue
+		}
 
 		// If address didn't specify type/size, use the type hint
 		if addr.Size == 0 && req.TypeHint != "" {
@@ -327,6 +418,7 @@ func (c *Client) ReadWithTypes(requests []TagRequest) ([]*TagValue, error) {
 	// Group requests into batches
 	var currentBatch []parsedRequest
 	var currentResponseSize int
+	var retry []parsedRequest // Members of merged blocks whose read failed
 
 	flushBatch := func() {
 		if len(currentBatch) == 0 {
@@ -343,32 +435,24 @@ func (c *Client) ReadWithTypes(requests []TagRequest) ([]*TagValue, error) {
 			data, err := c.readAddress(p.readAddr)
 			if err != nil {
 				logging.DebugLog("S7", "Read %q failed: %v", p.request.Address, err)
-				results[p.index] = &TagValue{
-					Name:  p.request.Address,
-					Error: err,
-				}
 			} else {
 				logging.DebugLog("S7", "Read %q success: got %d bytes", p.request.Address, len(data))
-				results[p.index] = &TagValue{
-					Name:     p.request.Address,
-					DataType: p.addr.DataType,
-					Bytes:    data,
-					BitNum:   p.addr.BitNum,
-					Count:    p.addr.Count,
-					Error:    nil,
-				}
 			}
+			retry = append(retry, storeRead(results, p, data, err)...)
 		} else {
 			// Multi-item batch read
-			c.readBatch(currentBatch, results)
+			retry = append(retry, c.readBatch(currentBatch, results)...)
 		}
 
 		currentBatch = nil
 		currentResponseSize = 0
 	}
 
-	for i := range parsed {
-		p := &parsed[i]
+	// Merge requests for nearby bytes into block reads
+	items := c.planReads(parsed, maxResponsePayload-4)
+
+	for i := range items {
+		p := &items[i]
 
 		// Handle parse errors
 		if p.err != nil {
@@ -443,13 +527,26 @@ func (c *Client) ReadWithTypes(requests []TagRequest) ([]*TagValue, error) {
 	// Flush remaining batch
 	flushBatch()
 
+	// A merged block can fail because of one bad member (e.g. a DB too short
+	// for the last request). Read its members individually so each reports
+	// its own result.
+	for _, p := range retry {
+		if !c.transport.isConnected() {
+			storeRead(results, p, nil, fmt.Errorf("read incomplete: %w", ErrConnectionLost))
+			continue
+		}
+		data, err := c.readAddress(p.readAddr)
+		storeRead(results, p, data, err)
+	}
+
 	return results, c.connErrorIfDownLocked()
 }
 
-// readBatch reads multiple addresses in a single S7 request.
-func (c *Client) readBatch(batch []parsedRequest, results []*TagValue) {
+// readBatch reads multiple addresses in a single S7 request. It returns the
+// members of any merged blocks that failed, for individual retry.
+func (c *Client) readBatch(batch []parsedRequest, results []*TagValue) []parsedRequest {
 	if len(batch) == 0 {
-		return
+		return nil
 	}
 
 	// Build list of addresses for the batch
@@ -477,60 +574,33 @@ func (c *Client) readBatch(batch []parsedRequest, results []*TagValue) {
 		// All items in batch fail with same error
 		logging.DebugLog("S7", "Batch read failed: %v", err)
 		for _, p := range batch {
-			if p.index >= 0 && p.index < len(results) {
-				results[p.index] = &TagValue{
-					Name:  p.request.Address,
-					Error: err,
-				}
-			}
+			storeRead(results, p, nil, err)
 		}
-		return
+		return nil
 	}
 
 	// Parse response
 	data, errors := parseReadResponse(response, len(batch))
 
 	// Map results back to original positions
+	var retry []parsedRequest
 	for i, p := range batch {
-		// Bounds check for safety
-		if p.index < 0 || p.index >= len(results) {
-			logging.DebugLog("S7", "Batch item %d has invalid index %d (results len=%d)", i, p.index, len(results))
-			continue
-		}
-
 		if i >= len(errors) || i >= len(data) {
 			logging.DebugLog("S7", "Batch item %d: response arrays too short (errors=%d, data=%d)", i, len(errors), len(data))
-			results[p.index] = &TagValue{
-				Name:  p.request.Address,
-				Error: fmt.Errorf("internal error: response parsing mismatch"),
-			}
+			storeRead(results, p, nil, fmt.Errorf("internal error: response parsing mismatch"))
 			continue
 		}
 
 		if errors[i] != nil {
 			logging.DebugLog("S7", "Batch item %q failed: %v", p.request.Address, errors[i])
-			results[p.index] = &TagValue{
-				Name:  p.request.Address,
-				Error: errors[i],
-			}
 		} else {
-			dataBytes := data[i]
-			if dataBytes == nil {
-				dataBytes = []byte{} // Ensure non-nil for successful reads with no data
-			}
-			logging.DebugLog("S7", "Batch item %q success: got %d bytes", p.request.Address, len(dataBytes))
-			results[p.index] = &TagValue{
-				Name:     p.request.Address,
-				DataType: p.addr.DataType,
-				Bytes:    dataBytes,
-				BitNum:   p.addr.BitNum,
-				Count:    p.addr.Count,
-				Error:    nil,
-			}
+			logging.DebugLog("S7", "Batch item %q success: got %d bytes", p.request.Address, len(data[i]))
 		}
+		retry = append(retry, storeRead(results, p, data[i], errors[i])...)
 	}
 
 	logging.DebugLog("S7", "Batch read complete: %d items", len(batch))
+	return retry
 }
 
 // readAddress reads data from a specific S7 address.
@@ -645,10 +715,42 @@ func (c *Client) WriteWithType(address string, value interface{}, typeHint strin
 
 	logging.DebugLog("S7", "Write: address=%q value=%v (type %T) typeHint=%q", address, value, value, typeHint)
 
-	addr, err := ParseAddress(address)
+	addr, err := c.resolveWriteAddress(address, value, typeHint)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := c.encodeValue(addr, value)
+	if err != nil {
+		logging.DebugLog("S7", "Write: encodeValue failed: %v", err)
+		return err
+	}
+
+	logging.DebugLog("S7", "Write: encoded %d bytes: %x", len(data), data)
+
+	err = c.writeAddress(addr, data)
+	if err != nil {
+		logging.DebugLog("S7", "Write: writeAddress failed: %v", err)
+		return err
+	}
+
+	logging.DebugLog("S7", "Write: success")
+	return nil
+}
+
+// resolveWriteAddress parses a write address and settles its data type from
+// the address, the type hint or, failing both, the Go value.
+func (c *Client) resolveWriteAddress(address string, value interface{}, typeHint string) (*Address, error) {
+	addr, err := c.Layout().ParseAddress(address)
 	if err != nil {
 		logging.DebugLog("S7", "Write: ParseAddress failed: %v", err)
-		return fmt.Errorf("Write: %w", err)
+		return nil, fmt.Errorf("Write: %w", err)
+	}
+	if addr.Area == AreaPI {
+		return nil, fmt.Errorf("Write: %s: peripheral inputs cannot be written", address)
 	}
 
 	logging.DebugLog("S7", "Write: parsed addr area=%s db=%d offset=%d dataType=%s size=%d",
@@ -677,25 +779,7 @@ func (c *Client) WriteWithType(address string, value interface{}, typeHint strin
 		logging.DebugLog("S7", "Write: BOOL type without bit number, defaulting to bit 0")
 	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	data, err := c.encodeValue(addr, value)
-	if err != nil {
-		logging.DebugLog("S7", "Write: encodeValue failed: %v", err)
-		return err
-	}
-
-	logging.DebugLog("S7", "Write: encoded %d bytes: %x", len(data), data)
-
-	err = c.writeAddress(addr, data)
-	if err != nil {
-		logging.DebugLog("S7", "Write: writeAddress failed: %v", err)
-		return err
-	}
-
-	logging.DebugLog("S7", "Write: success")
-	return nil
+	return addr, nil
 }
 
 // writeAddress writes data to a specific S7 address.
@@ -799,6 +883,11 @@ func (c *Client) encodeValue(addr *Address, value interface{}) ([]byte, error) {
 		return encodeBool(value)
 	case TypeByte, TypeSInt:
 		return encodeByte(value)
+	case TypeChar:
+		if s, ok := value.(string); ok {
+			return encodeChars(s, addr.Count)
+		}
+		return encodeByte(value)
 	case TypeWord:
 		return encodeWord(value)
 	case TypeInt:
@@ -815,6 +904,8 @@ func (c *Client) encodeValue(addr *Address, value interface{}) ([]byte, error) {
 		return encodeLInt(value)
 	case TypeULInt:
 		return encodeULInt(value)
+	case TypeS5Time, TypeTime, TypeTimeOfDay, TypeDate, TypeDateAndTime, TypeDTL:
+		return encodeTimeValue(baseType, value)
 	case TypeString:
 		return c.encodeStringWithRead(addr, value)
 	case TypeWString:
@@ -829,7 +920,8 @@ func isSliceValue(value interface{}) bool {
 	switch value.(type) {
 	case []int, []int8, []int16, []int32, []int64,
 		[]uint, []uint8, []uint16, []uint32, []uint64,
-		[]float32, []float64, []bool, []string:
+		[]float32, []float64, []bool, []string,
+		[]time.Duration, []time.Time:
 		return true
 	default:
 		return false
@@ -917,6 +1009,22 @@ func (c *Client) encodeArrayValue(addr *Address, value interface{}) ([]byte, err
 			}
 			result = append(result, encoded...)
 		}
+	case []time.Duration:
+		for _, elem := range v {
+			encoded, err := encodeTimeValue(baseType, elem)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, encoded...)
+		}
+	case []time.Time:
+		for _, elem := range v {
+			encoded, err := encodeTimeValue(baseType, elem)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, encoded...)
+		}
 	case []string:
 		if baseType != TypeString && baseType != TypeWString {
 			return nil, fmt.Errorf("cannot encode []string as %s", TypeName(addr.DataType))
@@ -1303,11 +1411,144 @@ func encodeULInt(value interface{}) ([]byte, error) {
 	return buf, nil
 }
 
+// encodeChars encodes a string as an ARRAY OF CHAR of count elements,
+// padded with NULs.
+func encodeChars(s string, count int) ([]byte, error) {
+	if count < 1 {
+		count = 1
+	}
+	if len(s) > count {
+		return nil, fmt.Errorf("string length %d exceeds CHAR array length %d", len(s), count)
+	}
+	buf := make([]byte, count)
+	copy(buf, s)
+	return buf, nil
+}
+
+// encodeTimeValue encodes a time.Duration or time.Time as one of the S7
+// date/time types. Integers are accepted as milliseconds for the duration
+// types. time.Time values are written using their own wall clock fields.
+func encodeTimeValue(dataType uint16, value interface{}) ([]byte, error) {
+	switch dataType {
+	case TypeS5Time:
+		d, err := durationValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if d < 0 || d > 999*s5TimeBases[3] {
+			return nil, fmt.Errorf("S5TIME out of range: %v", d)
+		}
+		// Use the finest time base that can hold the value in three digits
+		base := 0
+		for d/s5TimeBases[base] > 999 {
+			base++
+		}
+		units := int(d / s5TimeBases[base])
+		raw := uint16(base)<<12 | uint16(units/100)<<8 | uint16(units/10%10)<<4 | uint16(units%10)
+		buf := make([]byte, 2)
+		binary.BigEndian.PutUint16(buf, raw)
+		return buf, nil
+	case TypeTime:
+		d, err := durationValue(value)
+		if err != nil {
+			return nil, err
+		}
+		ms := d.Milliseconds()
+		if ms < math.MinInt32 || ms > math.MaxInt32 {
+			return nil, fmt.Errorf("TIME out of range: %v", d)
+		}
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, uint32(int32(ms)))
+		return buf, nil
+	case TypeTimeOfDay:
+		var d time.Duration
+		if t, ok := value.(time.Time); ok {
+			d = t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+		} else {
+			var err error
+			if d, err = durationValue(value); err != nil {
+				return nil, err
+			}
+		}
+		if d < 0 || d >= 24*time.Hour {
+			return nil, fmt.Errorf("TIME_OF_DAY out of range: %v", d)
+		}
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, uint32(d.Milliseconds()))
+		return buf, nil
+	}
+
+	t, ok := value.(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %T to %s", value, TypeName(dataType))
+	}
+
+	switch dataType {
+	case TypeDate:
+		days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Sub(s7Epoch) / (24 * time.Hour)
+		if days < 0 || days > math.MaxUint16 {
+			return nil, fmt.Errorf("DATE out of range: %s", t.Format("2006-01-02"))
+		}
+		buf := make([]byte, 2)
+		binary.BigEndian.PutUint16(buf, uint16(days))
+		return buf, nil
+	case TypeDateAndTime:
+		if t.Year() < 1990 || t.Year() > 2089 {
+			return nil, fmt.Errorf("DATE_AND_TIME out of range: %v", t)
+		}
+		ms := t.Nanosecond() / int(time.Millisecond)
+		return []byte{
+			toBCD(t.Year() % 100), toBCD(int(t.Month())), toBCD(t.Day()),
+			toBCD(t.Hour()), toBCD(t.Minute()), toBCD(t.Second()),
+			toBCD(ms / 10), byte(ms%10)<<4 | byte(t.Weekday()+1), // Weekday 1 = Sunday
+		}, nil
+	case TypeDTL:
+		if t.Year() < 1970 || t.Year() > 2262 {
+			return nil, fmt.Errorf("DTL out of range: %v", t)
+		}
+		buf := make([]byte, 12)
+		binary.BigEndian.PutUint16(buf[0:2], uint16(t.Year()))
+		buf[2] = byte(t.Month())
+		buf[3] = byte(t.Day())
+		buf[4] = byte(t.Weekday() + 1) // 1 = Sunday
+		buf[5] = byte(t.Hour())
+		buf[6] = byte(t.Minute())
+		buf[7] = byte(t.Second())
+		binary.BigEndian.PutUint32(buf[8:12], uint32(t.Nanosecond()))
+		return buf, nil
+	}
+	return nil, fmt.Errorf("unsupported data type: %s", TypeName(dataType))
+}
+
+// durationValue converts a time.Duration or a millisecond count to a duration.
+func durationValue(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, nil
+	case int:
+		return time.Duration(v) * time.Millisecond, nil
+	case int32:
+		return time.Duration(v) * time.Millisecond, nil
+	case int64:
+		return time.Duration(v) * time.Millisecond, nil
+	case uint32:
+		return time.Duration(v) * time.Millisecond, nil
+	case float64:
+		return time.Duration(v * float64(time.Millisecond)), nil
+	default:
+		return 0, fmt.Errorf("cannot convert %T to duration", value)
+	}
+}
+
 // inferTypeFromValue infers the S7 data type from a Go value.
 func inferTypeFromValue(value interface{}) uint16 {
 	switch value.(type) {
 	case bool:
 		return TypeBool
+	case time.Duration:
+		return TypeTime
+	case time.Time:
+		return TypeDateAndTime
 	case int8, uint8:
 		return TypeByte
 	case int16:
@@ -1332,29 +1573,48 @@ func inferTypeFromValue(value interface{}) uint16 {
 	}
 }
 
-// GetCPUInfo returns information about the connected CPU.
-// Note: CPU info retrieval is not yet implemented in native protocol.
+// GetCPUInfo returns information about the connected CPU, read from the
+// component identification (SZL 0x001C) and module identification
+// (SZL 0x0011) lists. CPUs that do not provide a list (e.g. some S7-1200
+// firmware) leave the corresponding fields empty; an error is returned only
+// when the connection fails.
 func (c *Client) GetCPUInfo() (*CPUInfo, error) {
 	if c == nil || c.transport == nil {
 		return nil, fmt.Errorf("GetCPUInfo: nil client")
 	}
 
-	// CPU info retrieval requires UserData PDU type which is more complex
-	// Return a placeholder for now
-	return &CPUInfo{
-		ModuleTypeName: "S7 PLC",
-		SerialNumber:   "",
-		ASName:         "",
-		Copyright:      "",
-		ModuleName:     "",
-	}, nil
+	info := &CPUInfo{}
+
+	if szl, err := c.ReadSZL(SZLComponentID, 0x0000); err == nil {
+		info.applyComponentID(szl)
+	} else if !c.transport.isConnected() {
+		return nil, fmt.Errorf("GetCPUInfo: %w", err)
+	} else {
+		logging.DebugLog("S7", "GetCPUInfo: component identification unavailable: %v", err)
+	}
+
+	if szl, err := c.ReadSZL(SZLModuleID, 0x0000); err == nil {
+		info.applyModuleID(szl)
+	} else if !c.transport.isConnected() {
+		return nil, fmt.Errorf("GetCPUInfo: %w", err)
+	} else {
+		logging.DebugLog("S7", "GetCPUInfo: module identification unavailable: %v", err)
+	}
+
+	if info.ModuleTypeName == "" {
+		info.ModuleTypeName = "S7 PLC"
+	}
+
+	return info, nil
 }
 
 // CPUInfo contains information about the S7 CPU.
 type CPUInfo struct {
-	ModuleTypeName string
-	SerialNumber   string
-	ASName         string
-	Copyright      string
-	ModuleName     string
+	ModuleTypeName  string // CPU type, e.g. "CPU 315-2 PN/DP"
+	SerialNumber    string
+	ASName          string // Automation system (station) name
+	Copyright       string
+	ModuleName      string // Module name from the hardware configuration
+	OrderCode       string // Order number, e.g. "6ES7 315-2EH14-0AB0"
+	FirmwareVersion string // Firmware version, e.g. "V3.2.6"
 }
@@ -0,0 +1,94 @@
+package s7
+
+import (
+	"context"
+
+	"github.com/yatesdr/plcio/internal/ctxerr"
+)
+
+// BindContext ties the client's socket I/O to ctx until the returned release
+// function is called. While bound, each exchange's deadline is the earlier of
+// the client timeout and the ctx deadline, and cancelling ctx aborts a blocked
+// send or receive. An aborted exchange leaves the ISO-on-TCP stream mid-frame,
+// so the transport is marked disconnected and the caller must Reconnect.
+//
+// Bindings are serialized: a second BindContext waits until the first is
+// released, or returns ctx.Err() if ctx ends first. Do not call the *Context methods while holding a binding.
+func (c *Client) BindContext(ctx context.Context) (release func(), err error) {
+	if c == nil || ctx == nil {
+		return func() {}, nil
+	}
+	if err := c.ctxMu.Lock(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.ctx = ctx
+	if c.transport != nil {
+		c.transport.setContext(ctx)
+	}
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		c.ctx = nil
+		if c.transport != nil {
+			c.transport.setContext(nil)
+		}
+		c.mu.Unlock()
+		c.ctxMu.Unlock()
+	}, nil
+}
+
+// ReadContext is like Read but honors ctx cancellation and deadline.
+func (c *Client) ReadContext(ctx context.Context, addresses ...string) ([]*TagValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	values, err := c.Read(addresses...)
+	return values, ctxerr.Wrap(ctx, err)
+}
+
+// ReadWithTypesContext is like ReadWithTypes but honors ctx cancellation and deadline.
+func (c *Client) ReadWithTypesContext(ctx context.Context, requests []TagRequest) ([]*TagValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	values, err := c.ReadWithTypes(requests)
+	return values, ctxerr.Wrap(ctx, err)
+}
+
+// WriteContext is like WriteWithType but honors ctx cancellation and deadline.
+func (c *Client) WriteContext(ctx context.Context, address string, value interface{}, typeHint string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return ctxerr.Wrap(ctx, c.WriteWithType(address, value, typeHint))
+}
+
+// WriteManyContext is like WriteMany but honors ctx cancellation and deadline.
+func (c *Client) WriteManyContext(ctx context.Context, writes []TagWrite) ([]error, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	errs, err := c.WriteMany(writes)
+	return errs, ctxerr.Wrap(ctx, err)
+}
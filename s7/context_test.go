@@ -0,0 +1,56 @@
+package s7
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// Cancelling the bound context must unblock a read that is waiting on a
+// silent peer well before the transport timeout expires.
+func TestBindContextAbortsBlockedRead(t *testing.T) {
+	local, peer := net.Pipe()
+	defer peer.Close()
+	go io.Copy(io.Discard, peer) // accept the request, never reply
+
+	c := &Client{transport: &transport{conn: local, timeout: 10 * time.Second, connected: true}}
+	ctx, cancel := context.WithCancel(context.Background())
+	release, err := c.BindContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = c.transport.sendReceive([]byte{0x32, 0x01})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected errors.Is(err, context.Canceled), got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("read was not aborted promptly: %v", elapsed)
+	}
+}
+
+// A caller waiting for another caller's binding must give up when its own
+// context ends.
+func TestBindContextWaitHonorsContext(t *testing.T) {
+	c := &Client{}
+	release, err := c.BindContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.BindContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded while bound, got %v", err)
+	}
+	if _, err := c.ReadContext(ctx, "DB1.DBW0"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected ReadContext to give up, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package s7
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// ErrControlNotAllowed is returned by Stop, HotStart and ColdStart unless the
+// client was connected with WithAllowControl.
+var ErrControlNotAllowed = errors.New("s7: PLC control not enabled (use WithAllowControl)")
+
+// Stop switches the CPU to STOP. The mode switch on the CPU must not be in
+// STOP. Requires WithAllowControl.
+func (c *Client) Stop() error {
+	return c.control("Stop", s7FuncPLCStop, func(ref uint16) []byte {
+		return buildPLCStopRequest(ref)
+	})
+}
+
+// HotStart switches the CPU from STOP to RUN with a warm restart: retentive
+// data is kept and process images are re-initialized. Requires
+// WithAllowControl.
+func (c *Client) HotStart() error {
+	return c.control("HotStart", s7FuncPIService, func(ref uint16) []byte {
+		return buildPIServiceRequest(piProgram, "", ref)
+	})
+}
+
+// ColdStart switches the CPU from STOP to RUN with a cold restart: all data
+// blocks, including retentive data, are reset to their initial values.
+// Requires WithAllowControl.
+func (c *Client) ColdStart() error {
+	return c.control("ColdStart", s7FuncPIService, func(ref uint16) []byte {
+		return buildPIServiceRequest(piProgram, "C ", ref)
+	})
+}
+
+// control sends a control job and checks its acknowledgement.
+func (c *Client) control(op string, function byte, build func(pduRef uint16) []byte) error {
+	if c == nil || c.transport == nil {
+		return fmt.Errorf("%s: nil client", op)
+	}
+	if !c.allowControl {
+		return ErrControlNotAllowed
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	logging.DebugLog("S7", "%s: sending control job (function 0x%02X)", op, function)
+
+	response, err := c.transport.sendReceive(build(c.nextPDURef()))
+	if err != nil {
+		return err
+	}
+	if err := parseControlResponse(response, function); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package s7
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestControlRequiresAllowControl(t *testing.T) {
+	c := &Client{transport: &transport{connected: true}}
+	for name, fn := range map[string]func() error{"Stop": c.Stop, "HotStart": c.HotStart, "ColdStart": c.ColdStart} {
+		if err := fn(); !errors.Is(err, ErrControlNotAllowed) {
+			t.Errorf("%s: expected ErrControlNotAllowed, got %v", name, err)
+		}
+	}
+}
+
+func TestColdStartRequestAndError(t *testing.T) {
+	local, plc := net.Pipe()
+	defer plc.Close()
+
+	requests := make(chan []byte, 2)
+	go func() {
+		for {
+			req, err := readFakeS7(plc)
+			if err != nil {
+				return
+			}
+			requests <- req
+			resp := []byte{s7ProtocolID, s7MsgAckData, 0x00, 0x00, req[4], req[5], 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, req[10]}
+			if req[10] == s7FuncPLCStop {
+				resp[10], resp[11] = 0xD6, 0x03 // Protection level
+			}
+			writeFakeS7(plc, resp)
+		}
+	}()
+
+	c := &Client{
+		transport:    &transport{conn: local, timeout: 2 * time.Second, connected: true},
+		allowControl: true,
+	}
+
+	if err := c.ColdStart(); err != nil {
+		t.Fatal(err)
+	}
+	req := <-requests
+	wantParams := append([]byte{s7FuncPIService, 0, 0, 0, 0, 0, 0, 0xFD, 0x00, 0x02, 'C', ' ', 9}, "P_PROGRAM"...)
+	if !bytes.Equal(req[10:], wantParams) || int(req[7]) != len(wantParams) {
+		t.Errorf("unexpected cold start request % X", req)
+	}
+
+	err := c.Stop()
+	var s7Err S7Error
+	if !errors.As(err, &s7Err) || s7Err.Class != errClassProtection {
+		t.Errorf("expected protection error, got %v", err)
+	}
+}
@@ -11,8 +11,26 @@ const (
 	errClassService     = 0x84
 	errClassNoResource  = 0x85 // No resource available (often PDU size exceeded)
 	errClassAccess      = 0x87
+	errClassUserData    = 0xD4 // UserData (SZL) function error
+	errClassProtection  = 0xD6 // Protection level / password
+	errClassDataItem    = 0xFF // Not sent by the PLC: Code is a data item return code
 )
 
+// s7ErrorCodes holds messages for specific class/code pairs, mostly
+// returned by control jobs (PI services and PLC stop).
+var s7ErrorCodes = map[uint16]string{
+	0x8104: "service not implemented on the module or frame error",
+	0x8402: "service cannot execute in the current CPU state",
+	0x8404: "function cannot be performed",
+	0x8500: "request exceeds PDU size",
+	0x8503: "service cancelled prematurely",
+	0xD201: "syntax error in block name",
+	0xD209: "block does not exist",
+	0xD241: "CPU is password protected",
+	0xD602: "password incorrect",
+	0xD603: "not possible at current protection level (password required)",
+}
+
 // S7 Data Item Return Codes
 const (
 	dataItemSuccess         = 0xFF
@@ -37,6 +55,10 @@ func (e S7Error) Error() string {
 
 // s7ErrorMessage returns a human-readable message for an S7 error.
 func s7ErrorMessage(class, code byte) string {
+	if msg, ok := s7ErrorCodes[uint16(class)<<8|uint16(code)]; ok {
+		return msg
+	}
+
 	switch class {
 	case errClassNoError:
 		return "no error"
@@ -52,6 +74,19 @@ func s7ErrorMessage(class, code byte) string {
 		return fmt.Sprintf("no resource available - request may exceed PDU size (code %d)", code)
 	case errClassAccess:
 		return fmt.Sprintf("access error (code %d)", code)
+	case errClassProtection:
+		return fmt.Sprintf("protection error (code 0x%02X)", code)
+	case errClassDataItem:
+		return dataItemError(code)
+	case errClassUserData:
+		switch code {
+		case 0x01:
+			return "SZL ID not available on this CPU"
+		case 0x02:
+			return "SZL index not available on this CPU"
+		default:
+			return fmt.Sprintf("system function error (code 0x%02X)", code)
+		}
 	default:
 		return fmt.Sprintf("S7 error class 0x%02X code %d", class, code)
 	}
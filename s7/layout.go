@@ -0,0 +1,1180 @@
+package s7
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+)
+
+// Layout maps symbolic data block names to S7 addresses. It is built from
+// STEP 7 / TIA Portal DB sources (.db, .scl declarations or .awl) exported
+// from the project, so that "DB_Motor.Speed" resolves to the offset and type
+// of Speed in DB_Motor.
+//
+// Only blocks with standard (non-optimized) access have fixed offsets; DBs
+// declared with S7_Optimized_Access := 'TRUE' are rejected.
+type Layout struct {
+	mu     sync.RWMutex
+	types  map[string]*declType // UDTs by upper-case name
+	blocks map[string]*DataBlock
+	order  []string // Block keys in load order
+}
+
+// DataBlock is a data block loaded into a Layout.
+type DataBlock struct {
+	Name   string // Symbolic name (e.g. "DB_Motor", or "DB10" for numbered AWL sources)
+	Number int    // DB number; 0 if the source did not specify one (see SetNumber)
+	Size   int    // Size in bytes
+
+	decl    *declType
+	root    *layoutNode
+	pending error // Why root is nil, e.g. a type not loaded yet
+}
+
+// LayoutField is a variable of a data block with its absolute position.
+type LayoutField struct {
+	Name       string   // Path within the block, e.g. "Axis.Speed"
+	TypeName   string   // Declared type, e.g. "Real" or "Array[0..9] of Int"
+	Offset     int      // Byte offset within the block
+	BitNum     int      // Bit number for BOOL, -1 otherwise
+	DataType   uint16   // S7 type code of the element
+	Size       int      // Element size in bytes
+	Count      int      // Number of elements (1 for scalars)
+	Dimensions []uint32 // Array dimensions (empty for scalars)
+}
+
+// NewLayout returns an empty layout.
+func NewLayout() *Layout {
+	return &Layout{
+		types:  make(map[string]*declType),
+		blocks: make(map[string]*DataBlock),
+	}
+}
+
+// LoadFile parses a DB source file and adds its types and data blocks.
+func (l *Layout) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := l.Load(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Load parses DB source text and adds its types and data blocks. Types
+// (TYPE ... END_TYPE) may be loaded before or after the blocks that use them:
+// a block using a type not loaded yet is kept and laid out once a later Load
+// supplies the type. A source that does not parse changes nothing.
+func (l *Layout) Load(r io.Reader) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	tokens, err := tokenizeSource(string(src))
+	if err != nil {
+		return err
+	}
+
+	p := &sourceParser{tokens: tokens}
+	types, blocks, err := p.parse()
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for name, t := range types {
+		l.types[name] = t
+	}
+	for _, db := range blocks {
+		key := strings.ToUpper(db.Name)
+		if _, exists := l.blocks[key]; !exists {
+			l.order = append(l.order, key)
+		}
+		l.blocks[key] = db
+	}
+
+	// Recompute all blocks, since new types may complete earlier blocks.
+	// Blocks that still use unknown types wait for a later Load.
+	for _, key := range l.order {
+		db := l.blocks[key]
+		root, err := layoutType(db.decl, l.types, 0)
+		db.root, db.pending = root, err
+		if root != nil {
+			db.Size = root.size
+		}
+	}
+	return nil
+}
+
+// SetNumber assigns the DB number of a block. TIA Portal sources name blocks
+// symbolically and do not carry the number, so it must be supplied here.
+func (l *Layout) SetNumber(block string, number int) error {
+	if number < 1 {
+		return fmt.Errorf("invalid DB number %d", number)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	db, ok := l.blocks[strings.ToUpper(strings.Trim(block, `"`))]
+	if !ok {
+		return fmt.Errorf("data block %q not loaded", block)
+	}
+	db.Number = number
+	return nil
+}
+
+// Blocks returns the loaded data blocks in load order.
+func (l *Layout) Blocks() []*DataBlock {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	blocks := make([]*DataBlock, 0, len(l.order))
+	for _, key := range l.order {
+		blocks = append(blocks, l.blocks[key])
+	}
+	return blocks
+}
+
+// Len returns the number of loaded data blocks.
+func (l *Layout) Len() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.blocks)
+}
+
+// Fields returns the variables of the block in declaration order. Structures
+// are expanded into their members; arrays are returned as a whole.
+func (db *DataBlock) Fields() []LayoutField {
+	if db.root == nil {
+		return nil
+	}
+	var fields []LayoutField
+	var walk func(n *layoutNode, path string, base int)
+	walk = func(n *layoutNode, path string, base int) {
+		for _, child := range n.children {
+			name := child.name
+			if path != "" {
+				name = path + "." + child.name
+			}
+			if child.children != nil {
+				walk(child, name, base+child.bitPos)
+				continue
+			}
+			fields = append(fields, child.field(name, base+child.bitPos))
+		}
+	}
+	walk(db.root, "", 0)
+	return fields
+}
+
+// Resolve returns the address of a symbolic name such as "DB_Motor.Speed",
+// "DB_Motor.Values[3]" or "\"DB_Motor\".Axis.Position".
+func (l *Layout) Resolve(symbol string) (*Address, error) {
+	if l == nil {
+		return nil, fmt.Errorf("no layout loaded")
+	}
+	parts, err := splitSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	db, ok := l.blocks[strings.ToUpper(parts[0].name)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errUnknownBlock, parts[0].name)
+	}
+	if db.root == nil {
+		return nil, fmt.Errorf("data block %s has unresolved types: %w", db.Name, db.pending)
+	}
+	if db.Number < 1 {
+		return nil, fmt.Errorf("DB number of %s is unknown", db.Name)
+	}
+	if len(parts) < 2 || len(parts[0].index) > 0 {
+		return nil, fmt.Errorf("symbol %q does not name a variable of %s", symbol, db.Name)
+	}
+
+	// Walk the path accumulating the bit position
+	node := db.root
+	bitPos := 0
+	for _, part := range parts[1:] {
+		child := node.child(part.name)
+		if child == nil {
+			return nil, fmt.Errorf("%s has no member %q", db.Name, part.name)
+		}
+		node = child
+		bitPos += node.bitPos
+
+		if len(part.index) > 0 {
+			if node.elem == nil {
+				return nil, fmt.Errorf("%q is not an array", part.name)
+			}
+			flat, err := node.flatIndex(part.index)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", part.name, err)
+			}
+			bitPos += flat * node.elemBits()
+			node = node.elem
+		}
+	}
+
+	f := node.field("", bitPos)
+	return &Address{
+		Area:     AreaDB,
+		DBNumber: db.Number,
+		Offset:   f.Offset,
+		BitNum:   f.BitNum,
+		DataType: f.DataType,
+		Size:     f.Size,
+		Count:    f.Count,
+	}, nil
+}
+
+// SetLayout makes the client resolve symbolic names against layout before
+// the layouts added with RegisterLayout. A nil layout removes it.
+func (c *Client) SetLayout(layout *Layout) {
+	c.layout.Store(layout)
+}
+
+// Layout returns the layout set with SetLayout or WithLayout, or nil.
+func (c *Client) Layout() *Layout {
+	return c.layout.Load()
+}
+
+// errUnknownBlock is returned when a symbol names no loaded data block.
+var errUnknownBlock = errors.New("unknown data block")
+
+// ParseAddress parses an absolute address, or resolves a symbolic name
+// against the layout, then against registered layouts.
+func (l *Layout) ParseAddress(addr string) (*Address, error) {
+	a, err := parseAbsoluteAddress(addr)
+	if err == nil || !isSymbolic(addr) {
+		return a, err
+	}
+	if l != nil {
+		sym, symErr := l.Resolve(addr)
+		if symErr == nil {
+			return sym, nil
+		}
+		if !errors.Is(symErr, errUnknownBlock) {
+			return nil, symErr
+		}
+	}
+	if sym, found, symErr := resolveRegistered(addr); found {
+		if symErr == nil {
+			return sym, nil
+		}
+		if !errors.Is(symErr, errUnknownBlock) {
+			return nil, symErr
+		}
+	}
+	return nil, err
+}
+
+// Registered layouts consulted by ParseAddress for symbolic names.
+var registeredLayouts atomic.Pointer[[]*Layout]
+
+// RegisterLayout makes ParseAddress resolve the symbolic names of l.
+// Layouts registered earlier take precedence for duplicate block names.
+// Clients with their own layout (see Client.SetLayout) consult it first.
+func RegisterLayout(l *Layout) {
+	for {
+		old := registeredLayouts.Load()
+		var layouts []*Layout
+		if old != nil {
+			layouts = append(layouts, *old...)
+		}
+		layouts = append(layouts, l)
+		if registeredLayouts.CompareAndSwap(old, &layouts) {
+			return
+		}
+	}
+}
+
+// UnregisterLayout removes a layout added with RegisterLayout.
+func UnregisterLayout(l *Layout) {
+	for {
+		old := registeredLayouts.Load()
+		if old == nil {
+			return
+		}
+		var layouts []*Layout
+		for _, r := range *old {
+			if r != l {
+				layouts = append(layouts, r)
+			}
+		}
+		if registeredLayouts.CompareAndSwap(old, &layouts) {
+			return
+		}
+	}
+}
+
+// resolveRegistered resolves a symbolic name against the registered layouts.
+func resolveRegistered(symbol string) (*Address, bool, error) {
+	layouts := registeredLayouts.Load()
+	if layouts == nil {
+		return nil, false, nil
+	}
+	var firstErr error
+	for _, l := range *layouts {
+		addr, err := l.Resolve(symbol)
+		if err == nil {
+			return addr, true, nil
+		}
+		if firstErr == nil || errors.Is(firstErr, errUnknownBlock) {
+			firstErr = err
+		}
+	}
+	return nil, len(*layouts) > 0, firstErr
+}
+
+// isSymbolic reports whether addr looks like a symbolic name (block.member)
+// rather than a malformed absolute address.
+func isSymbolic(addr string) bool {
+	return strings.ContainsAny(addr, `."`)
+}
+
+// symbolPart is one component of a symbolic path.
+type symbolPart struct {
+	name  string
+	index []int
+}
+
+// splitSymbol splits "DB_Motor.Axis[1].Speed" into its components.
+func splitSymbol(symbol string) ([]symbolPart, error) {
+	s := strings.TrimSpace(symbol)
+	var parts []symbolPart
+	for len(s) > 0 {
+		var part symbolPart
+		if s[0] == '"' {
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in %q", symbol)
+			}
+			part.name = s[1 : end+1]
+			s = s[end+2:]
+		} else {
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			part.name = strings.TrimSpace(s[:end])
+			s = s[end:]
+		}
+		if part.name == "" {
+			return nil, fmt.Errorf("empty name in %q", symbol)
+		}
+		if strings.HasPrefix(s, "[") {
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in %q", symbol)
+			}
+			for _, idx := range strings.Split(s[1:end], ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(idx))
+				if err != nil {
+					return nil, fmt.Errorf("invalid index in %q", symbol)
+				}
+				part.index = append(part.index, n)
+			}
+			s = s[end+1:]
+		}
+		parts = append(parts, part)
+		if len(s) > 0 {
+			if s[0] != '.' {
+				return nil, fmt.Errorf("invalid symbol %q", symbol)
+			}
+			s = s[1:]
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty symbol")
+	}
+	return parts, nil
+}
+
+// Declared types
+
+// declType is a parsed type declaration.
+type declType struct {
+	name     string       // Elementary type or UDT name (upper case for lookups)
+	text     string       // Type as written in the source
+	strLen   int          // STRING/WSTRING length
+	members  []declMember // STRUCT members
+	dims     []ArrayDim   // ARRAY dimensions
+	elem     *declType    // ARRAY element type
+	isStruct bool
+}
+
+// declMember is a named member of a STRUCT.
+type declMember struct {
+	name string
+	typ  *declType
+}
+
+// ArrayDim is one dimension of a declared array.
+type ArrayDim struct {
+	Low  int
+	High int
+}
+
+// elementaryTypes maps elementary type names to their size in bytes; BOOL is
+// a single bit.
+var elementaryTypes = map[string]int{
+	"BOOL": 0,
+	"BYTE": 1, "CHAR": 1, "SINT": 1, "USINT": 1,
+	"WORD": 2, "INT": 2, "UINT": 2, "DATE": 2, "S5TIME": 2, "WCHAR": 2,
+	"DWORD": 4, "DINT": 4, "UDINT": 4, "REAL": 4, "TIME": 4, "TIME_OF_DAY": 4, "TOD": 4,
+	"LWORD": 8, "LINT": 8, "ULINT": 8, "LREAL": 8, "LTIME": 8,
+	"DATE_AND_TIME": 8, "DT": 8, "LTOD": 8, "LDT": 8,
+	"DTL": 12,
+}
+
+// Layout computation
+
+// layoutNode is a type placed in memory. Positions are in bits relative to
+// the parent node.
+type layoutNode struct {
+	name     string
+	text     string
+	bitPos   int
+	size     int // Size in bytes (for BOOL: 1)
+	bits     int // Size in bits (BOOL: 1, otherwise size*8)
+	dataType uint16
+	isBool   bool
+
+	children []*layoutNode // STRUCT members
+	elem     *layoutNode   // ARRAY element
+	dims     []ArrayDim
+}
+
+func (n *layoutNode) child(name string) *layoutNode {
+	for _, c := range n.children {
+		if strings.EqualFold(c.name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// elemBits returns the stride of array elements in bits.
+func (n *layoutNode) elemBits() int {
+	if n.elem.isBool {
+		return 1
+	}
+	return n.elem.size * 8
+}
+
+// elemCount returns the total number of array elements.
+func (n *layoutNode) elemCount() int {
+	count := 1
+	for _, d := range n.dims {
+		count *= d.High - d.Low + 1
+	}
+	return count
+}
+
+// flatIndex converts array indices to a flat element index (row-major).
+func (n *layoutNode) flatIndex(index []int) (int, error) {
+	if len(index) != len(n.dims) {
+		return 0, fmt.Errorf("expected %d indices, got %d", len(n.dims), len(index))
+	}
+	flat := 0
+	for i, d := range n.dims {
+		if index[i] < d.Low || index[i] > d.High {
+			return 0, fmt.Errorf("index %d out of range [%d..%d]", index[i], d.Low, d.High)
+		}
+		flat = flat*(d.High-d.Low+1) + index[i] - d.Low
+	}
+	return flat, nil
+}
+
+// field describes the node at absolute bit position pos as an addressable
+// variable. Structures and BOOL arrays are read as raw bytes.
+func (n *layoutNode) field(name string, pos int) LayoutField {
+	f := LayoutField{
+		Name:     name,
+		TypeName: n.text,
+		Offset:   pos / 8,
+		BitNum:   -1,
+		DataType: n.dataType,
+		Size:     n.size,
+		Count:    1,
+	}
+	switch {
+	case n.isBool:
+		f.BitNum = pos % 8
+	case n.elem != nil:
+		for _, d := range n.dims {
+			f.Dimensions = append(f.Dimensions, uint32(d.High-d.Low+1))
+		}
+		if n.elem.isBool || n.elem.children != nil {
+			f.DataType, f.Size, f.Count = TypeByte, 1, n.size
+		} else {
+			f.DataType, f.Size, f.Count = n.elem.dataType, n.elem.size, n.elemCount()
+		}
+	case n.children != nil || n.dataType == 0:
+		f.DataType, f.Size, f.Count = TypeByte, 1, n.size
+	}
+	return f
+}
+
+// layoutType places a declared type using the rules for standard access
+// blocks: BOOLs are packed bitwise, byte-sized types are byte aligned, and
+// all other types, arrays and structures start on an even byte. Arrays and
+// structures occupy an even number of bytes.
+func layoutType(t *declType, types map[string]*declType, depth int) (*layoutNode, error) {
+	if depth > 32 {
+		return nil, fmt.Errorf("type nesting too deep (recursive UDT?)")
+	}
+	n := &layoutNode{text: t.text}
+
+	switch {
+	case t.isStruct:
+		n.children = []*layoutNode{}
+		pos := 0
+		for _, m := range t.members {
+			child, err := layoutType(m.typ, types, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", m.name, err)
+			}
+			child.name = m.name
+			switch {
+			case child.isBool:
+			case child.size == 1 && child.elem == nil && child.children == nil:
+				pos = alignBits(pos, 8)
+			default:
+				pos = alignBits(pos, 16)
+			}
+			child.bitPos = pos
+			pos += child.bits
+			n.children = append(n.children, child)
+		}
+		n.size = alignBits(pos, 16) / 8
+		n.bits = n.size * 8
+
+	case t.elem != nil:
+		elem, err := layoutType(t.elem, types, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		n.elem = elem
+		n.dims = t.dims
+		n.dataType = elem.dataType
+		count := n.elemCount()
+		if count < 1 {
+			return nil, fmt.Errorf("invalid array bounds in %s", t.text)
+		}
+		bits := count * elem.bits
+		n.size = alignBits(bits, 16) / 8
+		n.bits = n.size * 8
+
+	default:
+		name := t.name
+		if size, ok := elementaryTypes[name]; ok {
+			if name == "BOOL" {
+				n.isBool = true
+				n.size, n.bits = 1, 1
+				n.dataType = TypeBool
+				break
+			}
+			n.size = size
+			n.bits = size * 8
+			if code, ok := TypeCodeFromName(name); ok {
+				n.dataType = code
+			}
+			break
+		}
+		switch name {
+		case "STRING":
+			n.size = t.strLen + 2
+			n.dataType = TypeString
+		case "WSTRING":
+			n.size = 2*t.strLen + 4
+			n.dataType = TypeWString
+		default:
+			udt, ok := types[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown type %s", t.text)
+			}
+			resolved, err := layoutType(udt, types, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", t.text, err)
+			}
+			resolved.text = t.text
+			return resolved, nil
+		}
+		// Strings are word aligned and padded to an even length
+		n.bits = alignBits(n.size*8, 16)
+		n.size = n.bits / 8
+	}
+	return n, nil
+}
+
+func alignBits(pos, align int) int {
+	return (pos + align - 1) / align * align
+}
+
+// Source tokenizer
+
+type tokenKind int
+
+const (
+	tokIdent  tokenKind = iota // Identifier or keyword
+	tokQuoted                  // "Quoted name"
+	tokString                  // 'string literal'
+	tokNumber
+	tokPunct
+	tokAttr // { attribute block }
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// tokenizeSource splits source text into tokens, dropping comments.
+func tokenizeSource(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == 0xEF || c == 0xBB || c == 0xBF:
+			i++ // Whitespace and UTF-8 byte order mark
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "(*"), strings.HasPrefix(src[i:], "/*"):
+			closing := "*)"
+			if c == '/' {
+				closing = "*/"
+			}
+			end := strings.Index(src[i+2:], closing)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '{':
+			depth := 0
+			start := i
+			for i < len(src) {
+				switch src[i] {
+				case '{':
+					depth++
+				case '}':
+					depth--
+				case '\'':
+					if end := strings.IndexByte(src[i+1:], '\''); end >= 0 {
+						i += end + 1
+					}
+				}
+				i++
+				if depth == 0 {
+					break
+				}
+			}
+			if depth != 0 {
+				return nil, fmt.Errorf("line %d: unterminated attribute block", line)
+			}
+			tokens = append(tokens, token{kind: tokAttr, text: src[start+1 : i-1], line: line})
+			line += strings.Count(src[start:i], "\n")
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quote", line)
+			}
+			kind := tokQuoted
+			if c == '\'' {
+				kind = tokString
+			}
+			tokens = append(tokens, token{kind: kind, text: src[i+1 : i+1+end], line: line})
+			line += strings.Count(src[i+1:i+1+end], "\n")
+			i += end + 2
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (isIdentByte(src[i]) || src[i] == '#' ||
+				(src[i] == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9')) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: src[start:i], line: line})
+		case isIdentByte(c) || c == '#' || c >= 0x80:
+			start := i
+			for i < len(src) && (isIdentByte(src[i]) || src[i] == '#' || src[i] >= 0x80) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[start:i], line: line})
+		case strings.HasPrefix(src[i:], ":="), strings.HasPrefix(src[i:], ".."):
+			tokens = append(tokens, token{kind: tokPunct, text: src[i : i+2], line: line})
+			i += 2
+		default:
+			tokens = append(tokens, token{kind: tokPunct, text: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c < 0x80 && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)))
+}
+
+// Source parser
+
+type sourceParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *sourceParser) peek() *token {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *sourceParser) next() (token, error) {
+	if p.pos >= len(p.tokens) {
+		return token{}, fmt.Errorf("unexpected end of source")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+// isKeyword reports whether the next token is the given keyword.
+func (p *sourceParser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t != nil && t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+func (p *sourceParser) isPunct(s string) bool {
+	t := p.peek()
+	return t != nil && t.kind == tokPunct && t.text == s
+}
+
+func (p *sourceParser) expectPunct(s string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != tokPunct || t.text != s {
+		return fmt.Errorf("line %d: expected %q, got %q", t.line, s, t.text)
+	}
+	return nil
+}
+
+func (p *sourceParser) expectKeyword(kw string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != tokIdent || !strings.EqualFold(t.text, kw) {
+		return fmt.Errorf("line %d: expected %s, got %q", t.line, kw, t.text)
+	}
+	return nil
+}
+
+// skipLine skips the remaining tokens on the line of token t.
+func (p *sourceParser) skipLine(line int) {
+	for t := p.peek(); t != nil && t.line == line; t = p.peek() {
+		p.pos++
+	}
+}
+
+// skipTo skips tokens until the keyword, leaving it as the next token.
+func (p *sourceParser) skipTo(kw string) error {
+	for !p.isKeyword(kw) {
+		if _, err := p.next(); err != nil {
+			return fmt.Errorf("missing %s", kw)
+		}
+	}
+	return nil
+}
+
+// parse parses all TYPE and DATA_BLOCK declarations. Other blocks (FB, FC,
+// OB) are skipped.
+func (p *sourceParser) parse() (map[string]*declType, []*DataBlock, error) {
+	types := make(map[string]*declType)
+	var blocks []*DataBlock
+
+	for p.peek() != nil {
+		t, _ := p.next()
+		if t.kind != tokIdent {
+			continue
+		}
+		switch strings.ToUpper(t.text) {
+		case "TYPE":
+			name, decl, err := p.parseTypeBlock()
+			if err != nil {
+				return nil, nil, err
+			}
+			types[strings.ToUpper(name)] = decl
+		case "DATA_BLOCK":
+			db, err := p.parseDataBlock()
+			if err != nil {
+				return nil, nil, err
+			}
+			blocks = append(blocks, db)
+		case "FUNCTION_BLOCK", "FUNCTION", "ORGANIZATION_BLOCK":
+			end := "END_" + strings.ToUpper(t.text)
+			if err := p.skipTo(end); err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", t.line, err)
+			}
+			p.pos++
+		}
+	}
+
+	if len(types) == 0 && len(blocks) == 0 {
+		return nil, nil, fmt.Errorf("no TYPE or DATA_BLOCK declarations found")
+	}
+	return types, blocks, nil
+}
+
+// parseBlockName parses a block name: "Name", Name, DB 10, DB10 or UDT 5.
+func (p *sourceParser) parseBlockName() (string, int, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", 0, err
+	}
+	if t.kind == tokQuoted {
+		return t.text, 0, nil
+	}
+	if t.kind != tokIdent {
+		return "", 0, fmt.Errorf("line %d: expected block name, got %q", t.line, t.text)
+	}
+
+	// "DB 10" / "UDT 5" with a separate number
+	upper := strings.ToUpper(t.text)
+	if (upper == "DB" || upper == "UDT") && p.peek() != nil && p.peek().kind == tokNumber {
+		n, _ := p.next()
+		num, err := strconv.Atoi(n.text)
+		if err != nil {
+			return "", 0, fmt.Errorf("line %d: invalid block number %q", n.line, n.text)
+		}
+		return upper + n.text, num, nil
+	}
+	for _, prefix := range []string{"DB", "UDT"} {
+		if strings.HasPrefix(upper, prefix) {
+			if num, err := strconv.Atoi(upper[len(prefix):]); err == nil {
+				return upper, num, nil
+			}
+		}
+	}
+	return t.text, 0, nil
+}
+
+// skipAttrs skips { attribute } blocks and reports whether one of them
+// enables optimized block access.
+func (p *sourceParser) skipAttrs() (optimized bool) {
+	for t := p.peek(); t != nil && t.kind == tokAttr; t = p.peek() {
+		attrs := strings.ToUpper(strings.Join(strings.Fields(t.text), ""))
+		if strings.Contains(attrs, "S7_OPTIMIZED_ACCESS:='TRUE'") {
+			optimized = true
+		}
+		p.pos++
+	}
+	return optimized
+}
+
+// skipHeader skips block header lines (TITLE, VERSION, ...), flags and
+// attributes, and reports whether the block uses optimized access.
+func (p *sourceParser) skipHeader() (optimized bool) {
+	for t := p.peek(); t != nil && (t.kind == tokIdent || t.kind == tokAttr); t = p.peek() {
+		if t.kind == tokAttr {
+			optimized = p.skipAttrs() || optimized
+			continue
+		}
+		switch strings.ToUpper(t.text) {
+		case "TITLE", "VERSION", "AUTHOR", "FAMILY", "NAME":
+			if len(p.tokens) > p.pos+1 {
+				if next := p.tokens[p.pos+1]; next.kind == tokPunct && (next.text == "=" || next.text == ":") {
+					p.skipLine(t.line)
+					continue
+				}
+			}
+			return optimized
+		case "NON_RETAIN", "KNOW_HOW_PROTECT", "READ_ONLY", "UNLINKED", "CODE_VERSION1":
+			p.pos++
+		default:
+			return optimized
+		}
+	}
+	return optimized
+}
+
+// parseTypeBlock parses TYPE name ... STRUCT ... END_STRUCT ... END_TYPE.
+func (p *sourceParser) parseTypeBlock() (string, *declType, error) {
+	name, _, err := p.parseBlockName()
+	if err != nil {
+		return "", nil, err
+	}
+	p.skipHeader()
+
+	decl, err := p.parseType()
+	if err != nil {
+		return "", nil, fmt.Errorf("type %s: %w", name, err)
+	}
+	if p.isPunct(";") {
+		p.pos++
+	}
+	if err := p.skipTo("END_TYPE"); err != nil {
+		return "", nil, fmt.Errorf("type %s: %w", name, err)
+	}
+	p.pos++
+	return name, decl, nil
+}
+
+// parseDataBlock parses DATA_BLOCK name ... END_DATA_BLOCK.
+func (p *sourceParser) parseDataBlock() (*DataBlock, error) {
+	startLine := 0
+	if t := p.peek(); t != nil {
+		startLine = t.line
+	}
+	name, number, err := p.parseBlockName()
+	if err != nil {
+		return nil, err
+	}
+	db := &DataBlock{Name: name, Number: number}
+
+	// Optimized blocks have no fixed offsets and cannot be read by address
+	if p.skipHeader() {
+		return nil, fmt.Errorf("data block %s uses optimized block access; disable it to address members by offset", name)
+	}
+
+	switch {
+	case p.isKeyword("STRUCT"):
+		db.decl, err = p.parseType()
+	case p.isKeyword("FB"), p.isKeyword("SFB"):
+		err = fmt.Errorf("instance data blocks are not supported")
+	default:
+		// DB derived from a UDT: DATA_BLOCK "DB" "UDT_Name" or UDT 5
+		var typeName string
+		var typeNum int
+		typeName, typeNum, err = p.parseBlockName()
+		if err == nil {
+			if typeNum > 0 {
+				typeName = fmt.Sprintf("UDT%d", typeNum)
+			}
+			db.decl = &declType{name: strings.ToUpper(typeName), text: typeName}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("data block %s (line %d): %w", name, startLine, err)
+	}
+
+	if err := p.skipTo("END_DATA_BLOCK"); err != nil {
+		return nil, fmt.Errorf("data block %s: %w", name, err)
+	}
+	p.pos++
+	return db, nil
+}
+
+// parseType parses a type: STRUCT ... END_STRUCT, ARRAY [..] OF type,
+// STRING[n], an elementary type or a UDT name.
+func (p *sourceParser) parseType() (*declType, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if t.kind == tokQuoted {
+		return &declType{name: strings.ToUpper(t.text), text: `"` + t.text + `"`}, nil
+	}
+	if t.kind != tokIdent {
+		return nil, fmt.Errorf("line %d: expected type, got %q", t.line, t.text)
+	}
+
+	upper := strings.ToUpper(t.text)
+	switch upper {
+	case "STRUCT":
+		decl := &declType{isStruct: true, text: "Struct"}
+		for p.skipAttrs(); !p.isKeyword("END_STRUCT"); p.skipAttrs() {
+			if p.peek() == nil {
+				return nil, fmt.Errorf("line %d: missing END_STRUCT", t.line)
+			}
+			m, err := p.parseMember()
+			if err != nil {
+				return nil, err
+			}
+			decl.members = append(decl.members, m)
+		}
+		p.pos++
+		return decl, nil
+
+	case "ARRAY":
+		return p.parseArray(t)
+
+	case "STRING", "WSTRING":
+		decl := &declType{name: upper, text: t.text, strLen: 254}
+		if p.isPunct("[") {
+			p.pos++
+			n, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			length, err := strconv.Atoi(n.text)
+			if err != nil || length < 1 || length > 16382 {
+				return nil, fmt.Errorf("line %d: invalid string length %q", n.line, n.text)
+			}
+			if err := p.expectPunct("]"); err != nil {
+				return nil, err
+			}
+			decl.strLen = length
+			decl.text = fmt.Sprintf("%s[%d]", t.text, length)
+		}
+		return decl, nil
+
+	case "UDT":
+		n, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		return &declType{name: "UDT" + n.text, text: "UDT " + n.text}, nil
+	}
+
+	if _, ok := elementaryTypes[upper]; !ok {
+		if strings.HasPrefix(upper, "UDT") {
+			return &declType{name: upper, text: t.text}, nil
+		}
+		return nil, fmt.Errorf("line %d: unsupported type %q", t.line, t.text)
+	}
+	return &declType{name: upper, text: t.text}, nil
+}
+
+// parseArray parses the remainder of ARRAY [lo..hi, ...] OF type.
+func (p *sourceParser) parseArray(start token) (*declType, error) {
+	if err := p.expectPunct("["); err != nil {
+		return nil, err
+	}
+	var dims []ArrayDim
+	for {
+		low, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(".."); err != nil {
+			return nil, err
+		}
+		high, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		if high < low {
+			return nil, fmt.Errorf("line %d: invalid array bounds %d..%d", start.line, low, high)
+		}
+		dims = append(dims, ArrayDim{Low: low, High: high})
+		if p.isPunct(",") {
+			p.pos++
+			continue
+		}
+		break
+	}
+	if err := p.expectPunct("]"); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("OF"); err != nil {
+		return nil, err
+	}
+	elem, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := make([]string, len(dims))
+	for i, d := range dims {
+		bounds[i] = fmt.Sprintf("%d..%d", d.Low, d.High)
+	}
+	return &declType{
+		text: fmt.Sprintf("Array[%s] of %s", strings.Join(bounds, ", "), elem.text),
+		dims: dims,
+		elem: elem,
+	}, nil
+}
+
+// parseInt parses an optionally negative integer.
+func (p *sourceParser) parseInt() (int, error) {
+	neg := false
+	if p.isPunct("-") {
+		neg = true
+		p.pos++
+	}
+	t, err := p.next()
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(t.text)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: expected integer, got %q", t.line, t.text)
+	}
+	if neg {
+		n = -n
+	}
+	return n, nil
+}
+
+// parseMember parses name [{ attributes }] : type [:= initial value] ;
+func (p *sourceParser) parseMember() (declMember, error) {
+	p.skipAttrs()
+	t, err := p.next()
+	if err != nil {
+		return declMember{}, err
+	}
+	if t.kind != tokIdent && t.kind != tokQuoted {
+		return declMember{}, fmt.Errorf("line %d: expected member name, got %q", t.line, t.text)
+	}
+	p.skipAttrs()
+	if err := p.expectPunct(":"); err != nil {
+		return declMember{}, err
+	}
+	typ, err := p.parseType()
+	if err != nil {
+		return declMember{}, fmt.Errorf("%s: %w", t.text, err)
+	}
+
+	// Skip the initial value
+	depth := 0
+	for {
+		next := p.peek()
+		if next == nil {
+			return declMember{}, fmt.Errorf("line %d: missing ';' after %s", t.line, t.text)
+		}
+		if next.kind == tokPunct {
+			switch next.text {
+			case "(", "[":
+				depth++
+			case ")", "]":
+				depth--
+			case ";":
+				if depth == 0 {
+					p.pos++
+					return declMember{name: t.text, typ: typ}, nil
+				}
+			}
+		}
+		if depth == 0 && next.kind == tokIdent && strings.EqualFold(next.text, "END_STRUCT") {
+			// Last member without a terminating semicolon
+			return declMember{name: t.text, typ: typ}, nil
+		}
+		p.pos++
+	}
+}
@@ -0,0 +1,200 @@
+package s7
+
+import (
+	"strings"
+	"testing"
+)
+
+const tiaSource = `TYPE "UDT_Axis"
+VERSION : 0.1
+   STRUCT
+      Position : Real;
+      Homed : Bool;
+   END_STRUCT;
+
+END_TYPE
+
+DATA_BLOCK "DB_Motor"
+{ S7_Optimized_Access := 'FALSE' }
+VERSION : 0.1
+NON_RETAIN
+   STRUCT
+      Running : Bool;   // Motor running
+      Fault : Bool;
+      Mode : Byte;
+      Speed : Real;
+      Count { ExternalAccessible := 'False'} : Int := 5;
+      Name : String[20];
+      Values : Array[0..9] of Int;
+      Flags : Array[0..15] of Bool;
+      Axis : "UDT_Axis";
+      Grid : Array[1..2, 1..3] of DInt;
+   END_STRUCT;
+
+BEGIN
+   Speed := 1.5;
+END_DATA_BLOCK
+`
+
+const awlSource = `DATA_BLOCK DB 10
+TITLE =Tank data
+AUTHOR : plant
+VERSION : 0.1
+
+  STRUCT
+   Setpoint : REAL := 0.000000e+000;	// Level setpoint
+   Enable : BOOL ;
+   Level : INT ;
+  END_STRUCT ;
+BEGIN
+   Setpoint := 0.000000e+000;
+END_DATA_BLOCK
+`
+
+func TestLayoutResolve(t *testing.T) {
+	layout := NewLayout()
+	if err := layout.Load(strings.NewReader(tiaSource)); err != nil {
+		t.Fatal(err)
+	}
+	if err := layout.Load(strings.NewReader(awlSource)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := layout.Resolve("DB_Motor.Speed"); err == nil {
+		t.Errorf("expected error resolving a block without DB number")
+	}
+	if err := layout.SetNumber("DB_Motor", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		symbol   string
+		offset   int
+		bit      int
+		dataType uint16
+		size     int
+		count    int
+	}{
+		{"DB_Motor.Running", 0, 0, TypeBool, 1, 1},
+		{"DB_Motor.Fault", 0, 1, TypeBool, 1, 1},
+		{"DB_Motor.Mode", 1, -1, TypeByte, 1, 1},
+		{"db_motor.speed", 2, -1, TypeReal, 4, 1},
+		{`"DB_Motor".Count`, 6, -1, TypeInt, 2, 1},
+		{"DB_Motor.Name", 8, -1, TypeString, 22, 1},
+		{"DB_Motor.Values", 30, -1, TypeInt, 2, 10},
+		{"DB_Motor.Values[3]", 36, -1, TypeInt, 2, 1},
+		{"DB_Motor.Flags[10]", 51, 2, TypeBool, 1, 1},
+		{"DB_Motor.Axis.Position", 52, -1, TypeReal, 4, 1},
+		{"DB_Motor.Axis.Homed", 56, 0, TypeBool, 1, 1},
+		{"DB_Motor.Axis", 52, -1, TypeByte, 1, 6},
+		{"DB_Motor.Grid[2,1]", 70, -1, TypeDInt, 4, 1},
+		{"DB10.Setpoint", 0, -1, TypeReal, 4, 1},
+		{"DB10.Enable", 4, 0, TypeBool, 1, 1},
+		{"DB10.Level", 6, -1, TypeInt, 2, 1},
+	}
+	for _, tt := range tests {
+		addr, err := layout.Resolve(tt.symbol)
+		if err != nil {
+			t.Errorf("%s: %v", tt.symbol, err)
+			continue
+		}
+		if addr.Offset != tt.offset || addr.BitNum != tt.bit || addr.DataType != tt.dataType ||
+			addr.Size != tt.size || addr.Count != tt.count {
+			t.Errorf("%s: got offset=%d bit=%d type=%s size=%d count=%d", tt.symbol,
+				addr.Offset, addr.BitNum, TypeName(addr.DataType), addr.Size, addr.Count)
+		}
+	}
+
+	for _, bad := range []string{"DB_Motor.Missing", "DB_Motor.Values[10]", "DB_Motor.Speed[1]", "DB_Motor.Grid[1]"} {
+		if _, err := layout.Resolve(bad); err == nil {
+			t.Errorf("%s: expected error", bad)
+		}
+	}
+
+	blocks := layout.Blocks()
+	if len(blocks) != 2 || blocks[0].Size != 82 {
+		t.Fatalf("unexpected blocks: %d, first size %d", len(blocks), blocks[0].Size)
+	}
+	fields := blocks[0].Fields()
+	if len(fields) != 11 || fields[9].Name != "Axis.Homed" || fields[9].Offset != 56 {
+		t.Errorf("unexpected fields: %+v", fields)
+	}
+}
+
+func TestParseAddressRegisteredLayout(t *testing.T) {
+	layout := NewLayout()
+	if err := layout.Load(strings.NewReader(awlSource)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseAddress("DB10.Level"); err == nil {
+		t.Fatalf("expected error before the layout is registered")
+	}
+
+	RegisterLayout(layout)
+	defer UnregisterLayout(layout)
+
+	addr, err := ParseAddress("DB10.Level")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.DBNumber != 10 || addr.Offset != 6 || addr.DataType != TypeInt {
+		t.Errorf("unexpected address %+v", addr)
+	}
+
+	// Absolute addresses are unaffected
+	if addr, err := ParseAddress("DB10.DBW6"); err != nil || addr.DataType != TypeWord {
+		t.Errorf("absolute address: %+v, %v", addr, err)
+	}
+}
+
+func TestLayoutRejectsOptimizedBlock(t *testing.T) {
+	src := strings.Replace(tiaSource, "'FALSE'", "'TRUE'", 1)
+	if err := NewLayout().Load(strings.NewReader(src)); err == nil || !strings.Contains(err.Error(), "optimized") {
+		t.Errorf("expected optimized access error, got %v", err)
+	}
+}
+
+func TestLayoutTypeLoadedAfterBlock(t *testing.T) {
+	split := strings.Index(tiaSource, "DATA_BLOCK")
+	udt, db := tiaSource[:split], tiaSource[split:]
+
+	layout := NewLayout()
+	if err := layout.Load(strings.NewReader(db)); err != nil {
+		t.Fatalf("block before its type: %v", err)
+	}
+	if err := layout.SetNumber("DB_Motor", 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := layout.Resolve("DB_Motor.Speed"); err == nil || !strings.Contains(err.Error(), "UDT_Axis") {
+		t.Errorf("expected unresolved type error, got %v", err)
+	}
+
+	if err := layout.Load(strings.NewReader(udt)); err != nil {
+		t.Fatal(err)
+	}
+	addr, err := layout.Resolve("DB_Motor.Axis.Homed")
+	if err != nil || addr.DBNumber != 5 || addr.Offset != 56 || addr.BitNum != 0 {
+		t.Errorf("DB_Motor.Axis.Homed after loading the type: %+v, %v", addr, err)
+	}
+	if blocks := layout.Blocks(); len(blocks) != 1 || blocks[0].Size != 82 {
+		t.Errorf("unexpected blocks: %+v", blocks)
+	}
+}
+
+func TestLayoutParseErrorKeepsLayout(t *testing.T) {
+	layout := NewLayout()
+	if err := layout.Load(strings.NewReader(awlSource)); err != nil {
+		t.Fatal(err)
+	}
+
+	broken := strings.Replace(awlSource, "END_STRUCT", "END_STUFF", 1)
+	if err := layout.Load(strings.NewReader(broken)); err == nil {
+		t.Fatal("expected parse error")
+	}
+
+	addr, err := layout.Resolve("DB10.Level")
+	if err != nil || addr.Offset != 6 || addr.DataType != TypeInt {
+		t.Errorf("DB10.Level after failed load: %+v, %v", addr, err)
+	}
+}
@@ -0,0 +1,169 @@
+package s7
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// planReads merges requests for nearby bytes of the same memory area into
+// single byte-range reads, so that e.g. 200 consecutive DB words cost a few
+// S7ANY items instead of 200. Requests are merged when the unused gap
+// between them is at most c.readGap bytes and the block stays within
+// maxSize bytes, so each block fits one response. Requests that cannot be
+// merged are returned unchanged; a negative readGap disables merging.
+func (c *Client) planReads(parsed []parsedRequest, maxSize int) []parsedRequest {
+	if c.readGap < 0 {
+		return parsed
+	}
+
+	var plan, candidates []parsedRequest
+	for _, p := range parsed {
+		if mergeable(p, maxSize) {
+			candidates = append(candidates, p)
+		} else {
+			plan = append(plan, p)
+		}
+	}
+	if len(candidates) < 2 {
+		return parsed
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].readAddr, candidates[j].readAddr
+		if a.Area != b.Area {
+			return a.Area < b.Area
+		}
+		if a.DBNumber != b.DBNumber {
+			return a.DBNumber < b.DBNumber
+		}
+		return a.Offset < b.Offset
+	})
+
+	var block *parsedRequest
+	start, end := 0, 0
+	flush := func() {
+		if block == nil {
+			return
+		}
+		if len(block.members) == 1 {
+			plan = append(plan, block.members[0])
+		} else {
+			block.readAddr = &Address{
+				Area:     block.members[0].readAddr.Area,
+				DBNumber: block.members[0].readAddr.DBNumber,
+				Offset:   start,
+				BitNum:   -1,
+				DataType: TypeByte,
+				Size:     end - start,
+				Count:    1,
+			}
+			block.addr = block.readAddr
+			block.request.Address = blockName(block.readAddr)
+			plan = append(plan, *block)
+		}
+		block = nil
+	}
+
+	for _, p := range candidates {
+		a := p.readAddr
+		pStart, pEnd := a.Offset, a.Offset+readSpan(a)
+		if block != nil {
+			first := block.members[0].readAddr
+			if a.Area == first.Area && a.DBNumber == first.DBNumber &&
+				pStart <= end+c.readGap && max(end, pEnd)-start <= maxSize {
+				block.members = append(block.members, p)
+				end = max(end, pEnd)
+				continue
+			}
+		}
+		flush()
+		block = &parsedRequest{index: -1, members: []parsedRequest{p}}
+		start, end = pStart, pEnd
+	}
+	flush()
+
+	logging.DebugLog("S7", "Read plan: %d requests in %d reads", len(parsed), len(plan))
+	return plan
+}
+
+// mergeable reports whether p may be served from a merged block read.
+// Peripheral I/O is excluded because gap bytes may not exist on the bus,
+// and timers and counters use their own transport sizes.
+func mergeable(p parsedRequest, maxSize int) bool {
+	if p.err != nil || p.addr == nil || p.readAddr == nil || p.readAddr.Size <= 0 {
+		return false
+	}
+	switch p.readAddr.Area {
+	case AreaDB, AreaDI, AreaI, AreaQ, AreaM, AreaV:
+		return readSpan(p.readAddr) <= maxSize
+	default:
+		return false
+	}
+}
+
+// readSpan returns the number of bytes addr covers; a bit occupies its byte.
+func readSpan(addr *Address) int {
+	if addr.BitNum >= 0 {
+		return 1
+	}
+	return addr.Size
+}
+
+// blockName describes a merged block read for logging.
+func blockName(addr *Address) string {
+	area := addr.Area.String()
+	if addr.Area == AreaDB || addr.Area == AreaDI {
+		area = fmt.Sprintf("%s%d", area, addr.DBNumber)
+	}
+	return fmt.Sprintf("%s bytes %d-%d", area, addr.Offset, addr.Offset+addr.Size-1)
+}
+
+// storeRead records the result of reading p in results. A merged block is
+// sliced back into one TagValue per member. If the block read failed, its
+// members are returned so they can be read individually instead.
+func storeRead(results []*TagValue, p parsedRequest, data []byte, err error) []parsedRequest {
+	if p.members != nil {
+		if err != nil {
+			return p.members
+		}
+		for _, m := range p.members {
+			from := m.readAddr.Offset - p.readAddr.Offset
+			to := from + readSpan(m.readAddr)
+			if to > len(data) {
+				storeRead(results, m, nil, fmt.Errorf("short read: got %d of %d bytes", len(data), p.readAddr.Size))
+				continue
+			}
+			storeRead(results, m, append([]byte(nil), data[from:to]...), nil)
+		}
+		return nil
+	}
+
+	// Bounds check for safety
+	if p.index < 0 || p.index >= len(results) {
+		logging.DebugLog("S7", "Read item has invalid index %d (results len=%d)", p.index, len(results))
+		return nil
+	}
+
+	if err != nil {
+		results[p.index] = &TagValue{
+			Name:  p.request.Address,
+			Error: err,
+		}
+		return nil
+	}
+
+	if data == nil {
+		data = []byte{} // Ensure non-nil for successful reads with no data
+	}
+	results[p.index] = &TagValue{
+		Name:     p.request.Address,
+		DataType: p.addr.DataType,
+		Bytes:    data,
+		BitNum:   p.addr.BitNum,
+		Count:    p.addr.Count,
+		Error:    nil,
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package s7
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeMemory serves Read Variable jobs from a 64 byte DB1 and M area whose
+// bytes hold their own offset. Items beyond the end fail with an address
+// error. Every job's item count is sent on jobs.
+func fakeMemory(t *testing.T, jobs chan<- int) *Client {
+	t.Helper()
+	local, plc := net.Pipe()
+	t.Cleanup(func() { plc.Close() })
+
+	const memSize = 64
+	go func() {
+		for {
+			req, err := readFakeS7(plc)
+			if err != nil {
+				return
+			}
+			n := int(req[11])
+			jobs <- n
+
+			var data []byte
+			for i := 0; i < n; i++ {
+				item := req[12+i*12 : 24+i*12]
+				count := int(binary.BigEndian.Uint16(item[4:6]))
+				bitAddr := int(item[9])<<16 | int(item[10])<<8 | int(item[11])
+
+				var value []byte
+				transport := byte(0x04)
+				switch item[3] {
+				case tsBIT:
+					transport = 0x03
+					if bitAddr/8 < memSize {
+						value = []byte{byte(bitAddr/8) >> (bitAddr % 8) & 1}
+					}
+				default:
+					size := count * map[byte]int{tsBYTE: 1, tsCHAR: 1, tsWORD: 2, tsINT: 2, tsDWORD: 4, tsDINT: 4, tsREAL: 4}[item[3]]
+					if bitAddr/8+size <= memSize {
+						for b := bitAddr / 8; b < bitAddr/8+size; b++ {
+							value = append(value, byte(b))
+						}
+					}
+				}
+
+				if value == nil {
+					data = append(data, dataItemAddressError)
+					if i < n-1 {
+						data = append(data, 0x00, 0x00, 0x00)
+					}
+					continue
+				}
+				bits := len(value) * 8
+				if transport == 0x03 {
+					bits = 1
+				}
+				data = append(data, dataItemSuccess, transport, byte(bits>>8), byte(bits))
+				data = append(data, value...)
+				if i < n-1 && len(value)%2 == 1 {
+					data = append(data, 0x00)
+				}
+			}
+
+			resp := []byte{s7ProtocolID, s7MsgAckData, 0x00, 0x00, req[4], req[5], 0x00, 0x02,
+				byte(len(data) >> 8), byte(len(data)), 0x00, 0x00, s7FuncRead, byte(n)}
+			writeFakeS7(plc, append(resp, data...))
+		}
+	}()
+
+	return &Client{transport: &transport{conn: local, timeout: 2 * time.Second, connected: true, pduSize: 240}}
+}
+
+func TestReadPlannerMergesAdjacentAddresses(t *testing.T) {
+	jobs := make(chan int, 16)
+	c := fakeMemory(t, jobs)
+
+	var addresses []string
+	for i := 0; i < 20; i++ {
+		addresses = append(addresses, fmt.Sprintf("DB1.DBW%d", i*2))
+	}
+	addresses = append(addresses, "M5.2", "MB5", "MD6", "DB1.DBB50")
+
+	values, err := c.Read(addresses...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// DB1 bytes 0-39, M bytes 5-9 and DB1 byte 50 (not adjacent)
+	if n := <-jobs; n != 3 {
+		t.Errorf("merged read sent %d items, want 3", n)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("expected a single job, got %d more", len(jobs))
+	}
+
+	for i := 0; i < 20; i++ {
+		if got, _ := values[i].Uint(); got != uint64(i*2)<<8|uint64(i*2+1) {
+			t.Errorf("%s = %#x", addresses[i], got)
+		}
+	}
+	if got, _ := values[20].Bool(); !got {
+		t.Errorf("M5.2 = %v", got)
+	}
+	if got, _ := values[21].Uint(); got != 5 {
+		t.Errorf("MB5 = %d", got)
+	}
+	if got, _ := values[22].Uint(); got != 0x06070809 {
+		t.Errorf("MD6 = %#x", got)
+	}
+	if got, _ := values[23].Uint(); got != 50 || values[23].Name != "DB1.DBB50" {
+		t.Errorf("DB1.DBB50 = %d (%s)", got, values[23].Name)
+	}
+
+	// With a gap of 10 bytes DB1.DBB50 joins the DB1 block
+	c.readGap = 10
+	if _, err := c.Read("DB1.DBW38", "DB1.DBB50"); err != nil {
+		t.Fatal(err)
+	}
+	if n := <-jobs; n != 1 {
+		t.Errorf("gap read sent %d items, want 1", n)
+	}
+
+	// Merging disabled
+	c.readGap = -1
+	if _, err := c.Read("DB1.DBW0", "DB1.DBW2"); err != nil {
+		t.Fatal(err)
+	}
+	if n := <-jobs; n != 2 {
+		t.Errorf("unmerged read sent %d items, want 2", n)
+	}
+}
+
+func TestReadPlannerRetriesFailedBlock(t *testing.T) {
+	jobs := make(chan int, 16)
+	c := fakeMemory(t, jobs)
+
+	// DB1 is 64 bytes, so the merged block 62-65 fails as a whole
+	values, err := c.Read("DB1.DBW62", "DB1.DBW64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := values[0].Uint(); err != nil || got != 62<<8|63 {
+		t.Errorf("DB1.DBW62 = %#x, %v", got, err)
+	}
+	if values[1].Error == nil {
+		t.Errorf("DB1.DBW64: expected address error")
+	}
+}
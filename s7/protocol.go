@@ -11,14 +11,17 @@ const (
 	s7ProtocolID = 0x32
 
 	// Message Types
-	s7MsgJob     = 0x01
-	s7MsgAck     = 0x02 // Acknowledgement without data
-	s7MsgAckData = 0x03 // Acknowledgement with data
+	s7MsgJob      = 0x01
+	s7MsgAck      = 0x02 // Acknowledgement without data
+	s7MsgAckData  = 0x03 // Acknowledgement with data
+	s7MsgUserData = 0x07 // UserData (SZL, clock, block functions)
 
 	// Functions
 	s7FuncSetupComm = 0xF0
 	s7FuncRead      = 0x04
 	s7FuncWrite     = 0x05
+	s7FuncPIService = 0x28 // Program invocation (start)
+	s7FuncPLCStop   = 0x29
 
 	// Area Codes (for S7ANY addressing)
 	s7AreaSysInfo = 0x03 // System info
@@ -29,6 +32,7 @@ const (
 	s7AreaT       = 0x1D // Timers (S7-200/300)
 	s7AreaC200    = 0x1E // IEC counters (S7-200)
 	s7AreaT200    = 0x1F // IEC timers (S7-200)
+	s7AreaP       = 0x80 // Direct peripheral access (inputs on read, outputs on write)
 	s7AreaI       = 0x81 // Inputs
 	s7AreaQ       = 0x82 // Outputs
 	s7AreaM       = 0x83 // Markers/Flags
@@ -298,14 +302,18 @@ func parseReadResponse(data []byte, count int) ([][]byte, []error) {
 
 // buildWriteRequest creates an S7 Write Variable request PDU.
 func buildWriteRequest(addr *Address, writeData []byte, pduRef uint16) []byte {
+	return buildWriteManyRequest([]*Address{addr}, [][]byte{writeData}, pduRef)
+}
+
+// buildWriteManyRequest creates an S7 Write Variable request PDU carrying one
+// item per address; data[i] is written to addrs[i].
+func buildWriteManyRequest(addrs []*Address, data [][]byte, pduRef uint16) []byte {
+	itemCount := len(addrs)
+
 	// S7 Header (10 bytes for Job)
-	paramLen := 2 + 12 // function + count + 1 item
-	// Data: return code (1) + transport size (1) + length (2) + data
-	dataLen := 4 + len(writeData)
-	// Pad to even length
-	if len(writeData)%2 == 1 {
-		dataLen++
-	}
+	paramLen := 2 + itemCount*12 // function + count + items
+	// Data: per item return code (1) + transport size (1) + length (2) + data
+	dataLen := writeDataLen(data)
 
 	header := []byte{
 		s7ProtocolID,              // Protocol ID
@@ -316,91 +324,330 @@ func buildWriteRequest(addr *Address, writeData []byte, pduRef uint16) []byte {
 		byte(dataLen >> 8), byte(dataLen), // Data length
 	}
 
-	// Parameters: function + count + item
+	// Parameters: function + count + items
 	params := []byte{
-		s7FuncWrite, // Function: Write Variable
-		0x01,        // Item count: 1
+		s7FuncWrite,     // Function: Write Variable
+		byte(itemCount), // Item count
 	}
-	params = append(params, addressToS7Any(addr)...)
-
-	// Data section transport size differs from parameter section:
-	// - 0x03 for BIT access (length in bits)
-	// - 0x04 for BYTE/WORD/DWORD access (length in bits)
-	// - 0x09 for OCTET STRING (length in bytes)
-	var dataTransportSize byte
-	var bitLen int
-	if addr.BitNum >= 0 {
-		dataTransportSize = 0x03 // BIT
-		bitLen = 1
-	} else {
-		dataTransportSize = 0x04 // BYTE/WORD/DWORD
-		bitLen = len(writeData) * 8
+	for _, addr := range addrs {
+		params = append(params, addressToS7Any(addr)...)
 	}
 
-	dataSection := []byte{
-		0x00,                                // Return code placeholder
-		dataTransportSize,                   // Transport size for data section
-		byte(bitLen >> 8), byte(bitLen),     // Bit length
-	}
-	dataSection = append(dataSection, writeData...)
+	result := append(header, params...)
+	for i, addr := range addrs {
+		// Data section transport size differs from parameter section:
+		// - 0x03 for BIT access (length in bits)
+		// - 0x04 for BYTE/WORD/DWORD access (length in bits)
+		// - 0x09 for OCTET STRING (length in bytes)
+		var dataTransportSize byte
+		var bitLen int
+		if addr.BitNum >= 0 {
+			dataTransportSize = 0x03 // BIT
+			bitLen = 1
+		} else {
+			dataTransportSize = 0x04 // BYTE/WORD/DWORD
+			bitLen = len(data[i]) * 8
+		}
+
+		result = append(result,
+			0x00,                          // Return code placeholder
+			dataTransportSize,             // Transport size for data section
+			byte(bitLen>>8), byte(bitLen), // Bit length
+		)
+		result = append(result, data[i]...)
 
-	// Pad to even length
-	if len(writeData)%2 == 1 {
-		dataSection = append(dataSection, 0x00)
+		// Pad each item to even length
+		if len(data[i])%2 == 1 {
+			result = append(result, 0x00)
+		}
 	}
 
-	result := append(header, params...)
-	result = append(result, dataSection...)
 	return result
 }
 
+// writeDataLen returns the data section length of a Write Variable request
+// carrying the given items.
+func writeDataLen(data [][]byte) int {
+	n := 0
+	for _, d := range data {
+		n += 4 + len(d) + len(d)%2
+	}
+	return n
+}
+
 // parseWriteResponse parses an S7 Write Variable response.
 func parseWriteResponse(data []byte) error {
+	return parseWriteManyResponse(data, 1)[0]
+}
+
+// parseWriteManyResponse parses an S7 Write Variable response for count
+// items. A job-level error is reported for every item; otherwise each item
+// gets its data item return code as an S7Error of class errClassDataItem.
+func parseWriteManyResponse(data []byte, count int) []error {
+	errs := make([]error, count)
+	fail := func(err error) []error {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
 	// Minimum header size check
 	if len(data) < 12 {
-		return fmt.Errorf("response too short")
+		return fail(fmt.Errorf("response too short"))
 	}
 
 	// Check protocol ID
 	if data[0] != s7ProtocolID {
-		return fmt.Errorf("invalid protocol ID: 0x%02X", data[0])
+		return fail(fmt.Errorf("invalid protocol ID: 0x%02X", data[0]))
 	}
 
 	// Check message type - accept both Ack (0x02) and AckData (0x03)
 	msgType := data[1]
 	if msgType != s7MsgAckData && msgType != s7MsgAck {
-		return fmt.Errorf("unexpected message type: 0x%02X", msgType)
+		return fail(fmt.Errorf("unexpected message type: 0x%02X", msgType))
 	}
 
 	// Check error class/code (same position for both Ack and AckData)
 	if data[10] != 0 || data[11] != 0 {
 		err := S7Error{Class: data[10], Code: data[11]}
 		logging.DebugLog("S7", "parseWriteResponse: S7 error class=0x%02X code=0x%02X: %v", data[10], data[11], err)
-		return err
+		return fail(err)
 	}
 
 	// For Ack messages (0x02), no data section - success if no error
 	if msgType == s7MsgAck {
-		return nil
+		return errs
 	}
 
-	// For AckData messages (0x03), check the data item return code
+	// For AckData messages (0x03), check the data item return codes
 	paramLen := binary.BigEndian.Uint16(data[6:8])
 	dataStart := 12 + int(paramLen)
 
 	if dataStart >= len(data) {
-		return fmt.Errorf("no data in response")
+		return fail(fmt.Errorf("no data in response"))
+	}
+
+	for i := range errs {
+		pos := dataStart + i
+		if pos >= len(data) {
+			errs[i] = fmt.Errorf("missing return code (item %d of %d)", i+1, count)
+			continue
+		}
+		if returnCode := data[pos]; returnCode != dataItemSuccess {
+			errs[i] = S7Error{Class: errClassDataItem, Code: returnCode}
+		}
+	}
+
+	return errs
+}
+
+// piProgram is the PI service that starts the user program.
+const piProgram = "P_PROGRAM"
+
+// buildPIServiceRequest creates a PI service job that invokes service with
+// the given argument block (e.g. "C " for a cold start, empty for a hot
+// start).
+func buildPIServiceRequest(service, arg string, pduRef uint16) []byte {
+	paramLen := 10 + len(arg) + 1 + len(service)
+	header := []byte{
+		s7ProtocolID, // Protocol ID
+		s7MsgJob,     // Message type: Job
+		0x00, 0x00,   // Reserved
+		byte(pduRef >> 8), byte(pduRef), // PDU reference
+		byte(paramLen >> 8), byte(paramLen), // Parameter length
+		0x00, 0x00, // Data length: 0
 	}
 
-	// Check data item return code
-	returnCode := data[dataStart]
-	if returnCode != dataItemSuccess {
-		return fmt.Errorf("%s", dataItemError(returnCode))
+	params := []byte{
+		s7FuncPIService,                          // Function: PI service
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFD, // Unknown (fixed)
+		byte(len(arg) >> 8), byte(len(arg)), // Argument block length
+	}
+	params = append(params, arg...)
+	params = append(params, byte(len(service)))
+	params = append(params, service...)
+
+	return append(header, params...)
+}
+
+// buildPLCStopRequest creates a PLC stop job.
+func buildPLCStopRequest(pduRef uint16) []byte {
+	paramLen := 7 + len(piProgram)
+	header := []byte{
+		s7ProtocolID, // Protocol ID
+		s7MsgJob,     // Message type: Job
+		0x00, 0x00,   // Reserved
+		byte(pduRef >> 8), byte(pduRef), // PDU reference
+		byte(paramLen >> 8), byte(paramLen), // Parameter length
+		0x00, 0x00, // Data length: 0
+	}
+
+	params := []byte{
+		s7FuncPLCStop,                // Function: PLC stop
+		0x00, 0x00, 0x00, 0x00, 0x00, // Unknown (fixed)
+		byte(len(piProgram)),
+	}
+	params = append(params, piProgram...)
+
+	return append(header, params...)
+}
+
+// parseControlResponse parses the acknowledgement of a PI service or PLC
+// stop job.
+func parseControlResponse(data []byte, function byte) error {
+	// Ack Data header is 12 bytes (includes error class/code)
+	if len(data) < 12 {
+		return fmt.Errorf("response too short: %d bytes", len(data))
+	}
+	if data[0] != s7ProtocolID {
+		return fmt.Errorf("invalid protocol ID: 0x%02X", data[0])
+	}
+	if data[1] != s7MsgAckData && data[1] != s7MsgAck {
+		return fmt.Errorf("unexpected message type: 0x%02X", data[1])
+	}
+	if data[10] != 0 || data[11] != 0 {
+		return S7Error{Class: data[10], Code: data[11]}
+	}
+
+	paramLen := int(binary.BigEndian.Uint16(data[6:8]))
+	if paramLen > 0 {
+		if len(data) < 13 {
+			return fmt.Errorf("response too short: %d bytes", len(data))
+		}
+		if data[12] != function {
+			return fmt.Errorf("unexpected function in response: 0x%02X", data[12])
+		}
 	}
 
 	return nil
 }
 
+// UserData parameter constants
+const (
+	udMethodRequest  = 0x11
+	udMethodResponse = 0x12
+	udTypeRequest    = 0x40 // Upper nibble of type/group byte
+	udGroupCPU       = 0x04 // CPU functions (SZL)
+	udSubReadSZL     = 0x01
+)
+
+// buildSZLRequest creates a UserData request that reads a system status
+// list (SZL) by ID and index.
+func buildSZLRequest(szlID, szlIndex uint16, pduRef uint16) []byte {
+	header := []byte{
+		s7ProtocolID,  // Protocol ID
+		s7MsgUserData, // Message type: UserData
+		0x00, 0x00,    // Reserved
+		byte(pduRef >> 8), byte(pduRef), // PDU reference
+		0x00, 0x08, // Parameter length: 8 bytes
+		0x00, 0x08, // Data length: 8 bytes
+	}
+
+	params := []byte{
+		0x00, 0x01, 0x12, // Parameter head
+		0x04,                       // Parameter length
+		udMethodRequest,            // Method: request
+		udTypeRequest | udGroupCPU, // Type: request, group: CPU functions
+		udSubReadSZL,               // Subfunction: Read SZL
+		0x00,                       // Sequence number
+	}
+
+	data := []byte{
+		dataItemSuccess, // Return code
+		0x09,            // Transport size: octet string
+		0x00, 0x04,      // Length
+		byte(szlID >> 8), byte(szlID),
+		byte(szlIndex >> 8), byte(szlIndex),
+	}
+
+	result := append(header, params...)
+	return append(result, data...)
+}
+
+// buildSZLFollowUp creates a UserData request for the next fragment of an
+// SZL response that did not fit in one PDU.
+func buildSZLFollowUp(seq byte, pduRef uint16) []byte {
+	header := []byte{
+		s7ProtocolID,  // Protocol ID
+		s7MsgUserData, // Message type: UserData
+		0x00, 0x00,    // Reserved
+		byte(pduRef >> 8), byte(pduRef), // PDU reference
+		0x00, 0x0C, // Parameter length: 12 bytes
+		0x00, 0x04, // Data length: 4 bytes
+	}
+
+	params := []byte{
+		0x00, 0x01, 0x12, // Parameter head
+		0x08,                       // Parameter length
+		udMethodResponse,           // Method: follow-up
+		udTypeRequest | udGroupCPU, // Type: request, group: CPU functions
+		udSubReadSZL,               // Subfunction: Read SZL
+		seq,                        // Sequence number from the previous fragment
+		0x00,                       // Data unit reference
+		0x00,                       // Last data unit
+		0x00, 0x00,                 // Error code
+	}
+
+	data := []byte{
+		0x0A,       // Return code: object does not exist (no data)
+		0x00,       // Transport size
+		0x00, 0x00, // Length
+	}
+
+	result := append(header, params...)
+	return append(result, data...)
+}
+
+// parseUserDataResponse parses a UserData response and returns its data
+// payload, the sequence number for follow-up requests, and whether more
+// fragments follow.
+func parseUserDataResponse(data []byte) (payload []byte, seq byte, more bool, err error) {
+	// UserData header is 10 bytes (no error class/code)
+	if len(data) < 10 {
+		return nil, 0, false, fmt.Errorf("response too short: %d bytes", len(data))
+	}
+	if data[0] != s7ProtocolID {
+		return nil, 0, false, fmt.Errorf("invalid protocol ID: 0x%02X", data[0])
+	}
+	if data[1] != s7MsgUserData {
+		if data[1] == s7MsgAck && len(data) >= 12 && (data[10] != 0 || data[11] != 0) {
+			return nil, 0, false, S7Error{Class: data[10], Code: data[11]}
+		}
+		return nil, 0, false, fmt.Errorf("unexpected message type: 0x%02X", data[1])
+	}
+
+	paramLen := int(binary.BigEndian.Uint16(data[6:8]))
+	dataLen := int(binary.BigEndian.Uint16(data[8:10]))
+	if paramLen < 12 || 10+paramLen+dataLen > len(data) {
+		return nil, 0, false, fmt.Errorf("invalid response lengths: param=%d data=%d total=%d", paramLen, dataLen, len(data))
+	}
+
+	// Params: [head 3][len 1][method 1][type/group 1][subfunction 1][seq 1]
+	// [data unit ref 1][last data unit 1][error code 2]
+	params := data[10 : 10+paramLen]
+	seq = params[7]
+	more = params[9] != 0
+	if errCode := binary.BigEndian.Uint16(params[10:12]); errCode != 0 {
+		return nil, 0, false, S7Error{Class: params[10], Code: params[11]}
+	}
+
+	// Data: [return code 1][transport size 1][length 2][payload]
+	body := data[10+paramLen : 10+paramLen+dataLen]
+	if len(body) < 4 {
+		return nil, 0, false, fmt.Errorf("no data in response")
+	}
+	if body[0] != dataItemSuccess {
+		return nil, 0, false, fmt.Errorf("%s", dataItemError(body[0]))
+	}
+	length := int(binary.BigEndian.Uint16(body[2:4]))
+	if 4+length > len(body) {
+		return nil, 0, false, fmt.Errorf("data truncated: need %d bytes, have %d", length, len(body)-4)
+	}
+
+	return body[4 : 4+length], seq, more, nil
+}
+
 // addressToS7Any converts an Address to S7ANY item bytes.
 func addressToS7Any(addr *Address) []byte {
 	// Determine area code
@@ -412,8 +659,12 @@ func addressToS7Any(addr *Address) []byte {
 		areaCode = s7AreaQ
 	case AreaM:
 		areaCode = s7AreaM
-	case AreaDB:
+	case AreaDB, AreaV:
 		areaCode = s7AreaDB
+	case AreaDI:
+		areaCode = s7AreaDI
+	case AreaPI, AreaPQ:
+		areaCode = s7AreaP
 	case AreaT:
 		areaCode = s7AreaT
 	case AreaC:
@@ -448,7 +699,7 @@ func addressToS7Any(addr *Address) []byte {
 	}
 
 	dbNumber := addr.DBNumber
-	if addr.Area != AreaDB {
+	if addr.Area != AreaDB && addr.Area != AreaDI && addr.Area != AreaV {
 		dbNumber = 0
 	}
 
@@ -479,7 +730,7 @@ func getTransportSize(dataType uint16, isBit bool) byte {
 		return tsBIT
 	case TypeByte, TypeSInt, TypeChar:
 		return tsBYTE
-	case TypeWord, TypeInt, TypeDate, TypeWChar:
+	case TypeWord, TypeInt, TypeDate, TypeWChar, TypeS5Time:
 		return tsWORD
 	case TypeDWord, TypeDInt, TypeTime, TypeTimeOfDay:
 		return tsDWORD
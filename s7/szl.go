@@ -0,0 +1,211 @@
+package s7
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// Well-known SZL (system status list) IDs
+const (
+	SZLModuleID    uint16 = 0x0011 // Module identification (order number, firmware)
+	SZLComponentID uint16 = 0x001C // Component identification (names, serial number)
+	SZLCPUState    uint16 = 0x0424 // Current CPU mode
+)
+
+// maxSZLFragments bounds the follow-up requests for one SZL read.
+const maxSZLFragments = 64
+
+// SZL is a system status list read from the CPU.
+type SZL struct {
+	ID        uint16   // SZL ID
+	Index     uint16   // SZL index
+	RecordLen uint16   // Length of each record in bytes
+	Records   [][]byte // Data records
+}
+
+// CPUState is the operating mode of the CPU.
+type CPUState byte
+
+// CPU operating modes (SZL 0x0424)
+const (
+	CPUStateUnknown CPUState = 0x00
+	CPUStateStop    CPUState = 0x04
+	CPUStateStartup CPUState = 0x06
+	CPUStateRun     CPUState = 0x08
+	CPUStateHold    CPUState = 0x0A
+	CPUStateDefect  CPUState = 0x0D
+)
+
+// String returns the mode name as shown by STEP 7.
+func (s CPUState) String() string {
+	switch s {
+	case CPUStateStop:
+		return "STOP"
+	case CPUStateStartup:
+		return "STARTUP"
+	case CPUStateRun:
+		return "RUN"
+	case CPUStateHold:
+		return "HOLD"
+	case CPUStateDefect:
+		return "DEFECT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// cpuStateFromMode maps the mode nibble of SZL 0x0424 to a CPUState.
+func cpuStateFromMode(mode byte) CPUState {
+	switch mode {
+	case 0x01, 0x02, 0x03, 0x04:
+		return CPUStateStop
+	case 0x05, 0x06, 0x07:
+		return CPUStateStartup
+	case 0x08, 0x09:
+		return CPUStateRun
+	case 0x0A:
+		return CPUStateHold
+	case 0x0D:
+		return CPUStateDefect
+	default:
+		return CPUStateUnknown
+	}
+}
+
+// ReadSZL reads a system status list using the UserData CPU functions
+// (function group 4). Responses larger than one PDU are fetched with
+// follow-up requests and reassembled.
+func (c *Client) ReadSZL(id, index uint16) (*SZL, error) {
+	if c == nil || c.transport == nil {
+		return nil, fmt.Errorf("ReadSZL: nil client")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	logging.DebugLog("S7", "ReadSZL: id=0x%04X index=0x%04X", id, index)
+
+	response, err := c.transport.sendReceive(buildSZLRequest(id, index, c.nextPDURef()))
+	if err != nil {
+		return nil, err
+	}
+	payload, seq, more, err := parseUserDataResponse(response)
+	if err != nil {
+		return nil, fmt.Errorf("ReadSZL 0x%04X: %w", id, err)
+	}
+
+	data := append([]byte(nil), payload...)
+	for fragments := 1; more; fragments++ {
+		if fragments >= maxSZLFragments {
+			return nil, fmt.Errorf("ReadSZL 0x%04X: exceeded maximum fragments (%d)", id, maxSZLFragments)
+		}
+		response, err = c.transport.sendReceive(buildSZLFollowUp(seq, c.nextPDURef()))
+		if err != nil {
+			return nil, err
+		}
+		payload, seq, more, err = parseUserDataResponse(response)
+		if err != nil {
+			return nil, fmt.Errorf("ReadSZL 0x%04X: %w", id, err)
+		}
+		data = append(data, payload...)
+	}
+
+	return parseSZL(data)
+}
+
+// parseSZL parses reassembled SZL data.
+// Format: [SZL ID 2] [Index 2] [Record length 2] [Record count 2] [Records...]
+func parseSZL(data []byte) (*SZL, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("SZL data too short: %d bytes", len(data))
+	}
+
+	szl := &SZL{
+		ID:        binary.BigEndian.Uint16(data[0:2]),
+		Index:     binary.BigEndian.Uint16(data[2:4]),
+		RecordLen: binary.BigEndian.Uint16(data[4:6]),
+	}
+	count := int(binary.BigEndian.Uint16(data[6:8]))
+
+	recordLen := int(szl.RecordLen)
+	if recordLen == 0 {
+		return szl, nil
+	}
+	offset := 8
+	for i := 0; i < count && offset+recordLen <= len(data); i++ {
+		szl.Records = append(szl.Records, data[offset:offset+recordLen])
+		offset += recordLen
+	}
+	if len(szl.Records) < count {
+		logging.DebugLog("S7", "SZL 0x%04X: expected %d records, got %d", szl.ID, count, len(szl.Records))
+	}
+
+	return szl, nil
+}
+
+// GetCPUState reads the current operating mode of the CPU (SZL 0x0424).
+func (c *Client) GetCPUState() (CPUState, error) {
+	szl, err := c.ReadSZL(SZLCPUState, 0x0000)
+	if err != nil {
+		return CPUStateUnknown, err
+	}
+	if len(szl.Records) == 0 || len(szl.Records[0]) < 4 {
+		return CPUStateUnknown, fmt.Errorf("GetCPUState: no state record")
+	}
+
+	// Record: [Event ID 2] [Info 1] [Mode 1] ...; the low nibble of the
+	// mode byte is the current mode.
+	return cpuStateFromMode(szl.Records[0][3] & 0x0F), nil
+}
+
+// szlString trims padding from a string field of an SZL record.
+func szlString(b []byte) string {
+	if i := strings.IndexByte(string(b), 0); i >= 0 {
+		b = b[:i]
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// applyComponentID fills info from SZL 0x001C records.
+// Record: [Index 2] [Name 32]
+func (info *CPUInfo) applyComponentID(szl *SZL) {
+	for _, rec := range szl.Records {
+		if len(rec) < 4 {
+			continue
+		}
+		value := szlString(rec[2:])
+		switch binary.BigEndian.Uint16(rec[0:2]) {
+		case 0x0001:
+			info.ASName = value
+		case 0x0002:
+			info.ModuleName = value
+		case 0x0004:
+			info.Copyright = value
+		case 0x0005:
+			info.SerialNumber = value
+		case 0x0007:
+			info.ModuleTypeName = value
+		}
+	}
+}
+
+// applyModuleID fills info from SZL 0x0011 records.
+// Record: [Index 2] [Order number 20] [Module type 2] [Version 2] [Version 2]
+func (info *CPUInfo) applyModuleID(szl *SZL) {
+	for _, rec := range szl.Records {
+		if len(rec) < 28 {
+			continue
+		}
+		switch binary.BigEndian.Uint16(rec[0:2]) {
+		case 0x0001: // Module
+			info.OrderCode = szlString(rec[2:22])
+		case 0x0007: // Firmware
+			if rec[24] == 'V' {
+				info.FirmwareVersion = fmt.Sprintf("V%d.%d.%d", rec[25], rec[26], rec[27])
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package s7
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// readFakeS7 reads one TPKT frame and returns the S7 PDU it carries.
+func readFakeS7(r io.Reader) ([]byte, error) {
+	header := make([]byte, tpktHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	frame := make([]byte, int(binary.BigEndian.Uint16(header[2:4]))-tpktHeaderSize)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	return frame[3:], nil // Skip COTP DT header
+}
+
+// writeFakeS7 writes an S7 PDU in COTP DT and TPKT framing.
+func writeFakeS7(w io.Writer, pdu []byte) {
+	length := tpktHeaderSize + 3 + len(pdu)
+	frame := append([]byte{tpktVersion, 0x00, byte(length >> 8), byte(length), 0x02, cotpDT, 0x80}, pdu...)
+	w.Write(frame)
+}
+
+// fakeUserDataResponse builds a UserData response carrying payload.
+func fakeUserDataResponse(seq byte, more bool, payload []byte) []byte {
+	last := byte(0x00)
+	if more {
+		last = 0x01
+	}
+	params := []byte{0x00, 0x01, 0x12, 0x08, 0x12, 0x84, 0x01, seq, 0x00, last, 0x00, 0x00}
+	data := []byte{0xFF, 0x09, byte(len(payload) >> 8), byte(len(payload))}
+	data = append(data, payload...)
+
+	pdu := []byte{s7ProtocolID, s7MsgUserData, 0x00, 0x00, 0x00, 0x01,
+		byte(len(params) >> 8), byte(len(params)), byte(len(data) >> 8), byte(len(data))}
+	pdu = append(pdu, params...)
+	return append(pdu, data...)
+}
+
+// fakeSZL builds SZL data with the given records.
+func fakeSZL(id uint16, recordLen int, records ...[]byte) []byte {
+	data := binary.BigEndian.AppendUint16(nil, id)
+	data = binary.BigEndian.AppendUint16(data, 0x0000)
+	data = binary.BigEndian.AppendUint16(data, uint16(recordLen))
+	data = binary.BigEndian.AppendUint16(data, uint16(len(records)))
+	for _, rec := range records {
+		data = append(data, rec...)
+	}
+	return data
+}
+
+// szlRecord builds a record of length n starting with index and text.
+func szlRecord(n int, index uint16, text string) []byte {
+	rec := make([]byte, n)
+	binary.BigEndian.PutUint16(rec[0:2], index)
+	copy(rec[2:], text)
+	return rec
+}
+
+func TestGetCPUInfoFromSZL(t *testing.T) {
+	local, plc := net.Pipe()
+	defer plc.Close()
+
+	firmware := szlRecord(28, 0x0007, "")
+	copy(firmware[24:], []byte{'V', 3, 2, 6})
+	moduleID := fakeSZL(SZLModuleID, 28, szlRecord(28, 0x0001, "6ES7 315-2EH14-0AB0 "), firmware)
+
+	componentID := fakeSZL(SZLComponentID, 34,
+		szlRecord(34, 0x0001, "Line 4"),
+		szlRecord(34, 0x0002, "PLC_1"),
+		szlRecord(34, 0x0005, "S C-X4U421302009"),
+		szlRecord(34, 0x0007, "CPU 315-2 PN/DP"))
+
+	cpuState := fakeSZL(SZLCPUState, 20, []byte{0x43, 0x02, 0xFF, 0x48, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+
+	go func() {
+		for {
+			req, err := readFakeS7(plc)
+			if err != nil {
+				return
+			}
+			if req[1] != s7MsgUserData {
+				return
+			}
+			if req[14] == udMethodResponse {
+				// Follow-up for the second half of the component list
+				writeFakeS7(plc, fakeUserDataResponse(req[17], false, componentID[40:]))
+				continue
+			}
+			switch binary.BigEndian.Uint16(req[22:24]) {
+			case SZLComponentID:
+				// Split across two fragments
+				writeFakeS7(plc, fakeUserDataResponse(7, true, componentID[:40]))
+			case SZLModuleID:
+				writeFakeS7(plc, fakeUserDataResponse(0, false, moduleID))
+			case SZLCPUState:
+				writeFakeS7(plc, fakeUserDataResponse(0, false, cpuState))
+			}
+		}
+	}()
+
+	c := &Client{transport: &transport{conn: local, timeout: 2 * time.Second, connected: true, pduSize: defaultPDUSize}}
+
+	info, err := c.GetCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CPUInfo{
+		ModuleTypeName:  "CPU 315-2 PN/DP",
+		SerialNumber:    "S C-X4U421302009",
+		ASName:          "Line 4",
+		ModuleName:      "PLC_1",
+		OrderCode:       "6ES7 315-2EH14-0AB0",
+		FirmwareVersion: "V3.2.6",
+	}
+	if *info != want {
+		t.Errorf("got %+v, want %+v", *info, want)
+	}
+
+	state, err := c.GetCPUState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != CPUStateRun {
+		t.Errorf("expected RUN, got %s", state)
+	}
+}
+
+func TestParseUserDataResponseError(t *testing.T) {
+	resp := fakeUserDataResponse(0, false, nil)
+	resp[20], resp[21] = 0xD4, 0x01 // Parameter error code: SZL ID not available
+
+	if _, _, _, err := parseUserDataResponse(resp); err == nil {
+		t.Errorf("expected error for non-zero parameter error code")
+	}
+}
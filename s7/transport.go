@@ -1,6 +1,7 @@
 package s7
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -44,6 +45,14 @@ type transport struct {
 	timeout   time.Duration
 	pduSize   uint16
 	connected bool
+
+	// Explicit TSAPs; remoteTSAP 0 derives the remote TSAP from rack/slot
+	localTSAP  uint16
+	remoteTSAP uint16
+
+	// ctx, when set, bounds socket deadlines and aborts blocked I/O on
+	// cancellation (see Client.BindContext). Guarded by mu.
+	ctx context.Context
 }
 
 // newTransport creates a new transport instance.
@@ -55,10 +64,17 @@ func newTransport() *transport {
 }
 
 // connect establishes connection to an S7 PLC.
-func (t *transport) connect(address string, rack, slot int) error {
+// A non-nil ctx bounds the TCP dial and the COTP/S7 setup handshake.
+func (t *transport) connect(ctx context.Context, address string, rack, slot int) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	t.ctx = ctx
+	defer func() { t.ctx = nil }()
+
 	// Add default port if not specified
 	host, port, err := net.SplitHostPort(address)
 	if err != nil {
@@ -73,10 +89,15 @@ func (t *transport) connect(address string, rack, slot int) error {
 	t.slot = slot
 
 	logging.DebugConnect("S7", address)
-	logging.DebugLog("S7", "Connection params: rack=%d, slot=%d", rack, slot)
+	if t.remoteTSAP != 0 {
+		logging.DebugLog("S7", "Connection params: local TSAP=%04X, remote TSAP=%04X", t.localTSAP, t.remoteTSAP)
+	} else {
+		logging.DebugLog("S7", "Connection params: rack=%d, slot=%d", rack, slot)
+	}
 
 	// TCP connect
-	conn, err := net.DialTimeout("tcp", address, t.timeout)
+	d := net.Dialer{Timeout: t.timeout}
+	conn, err := d.DialContext(ctx, "tcp", address)
 	if err != nil {
 		logging.DebugConnectError("S7", address, err)
 		return fmt.Errorf("TCP connect failed: %w", err)
@@ -85,8 +106,12 @@ func (t *transport) connect(address string, rack, slot int) error {
 
 	logging.DebugLog("S7", "TCP connection established to %s", address)
 
+	// Abort the handshake if ctx is cancelled
+	stop := t.watchContext()
+	defer stop()
+
 	// Set read/write deadlines
-	if err := t.conn.SetDeadline(time.Now().Add(t.timeout)); err != nil {
+	if err := t.conn.SetDeadline(t.ioDeadline()); err != nil {
 		t.conn.Close()
 		logging.DebugError("S7", "set deadline", err)
 		return fmt.Errorf("failed to set deadline: %w", err)
@@ -96,7 +121,7 @@ func (t *transport) connect(address string, rack, slot int) error {
 	if err := t.cotpConnect(); err != nil {
 		t.conn.Close()
 		logging.DebugError("S7", "COTP connect", err)
-		return fmt.Errorf("COTP connect failed: %w", err)
+		return fmt.Errorf("COTP connect failed: %w", t.withContextErr(err))
 	}
 
 	logging.DebugLog("S7", "COTP connection established")
@@ -106,7 +131,7 @@ func (t *transport) connect(address string, rack, slot int) error {
 	if err != nil {
 		t.conn.Close()
 		logging.DebugError("S7", "S7 setup communication", err)
-		return fmt.Errorf("S7 setup failed: %w", err)
+		return fmt.Errorf("S7 setup failed: %w", t.withContextErr(err))
 	}
 	t.pduSize = pduSize
 
@@ -160,8 +185,16 @@ func (t *transport) sendReceive(s7Request []byte) ([]byte, error) {
 		return nil, fmt.Errorf("not connected")
 	}
 
+	if err := t.contextErr(); err != nil {
+		return nil, err
+	}
+
+	// Abort blocked I/O if the bound context is cancelled mid-exchange
+	stop := t.watchContext()
+	defer stop()
+
 	// Set deadline for this operation
-	if err := t.conn.SetDeadline(time.Now().Add(t.timeout)); err != nil {
+	if err := t.conn.SetDeadline(t.ioDeadline()); err != nil {
 		t.connected = false
 		logging.DebugError("S7", "sendReceive set deadline", err)
 		return nil, fmt.Errorf("failed to set deadline: %w", err)
@@ -175,7 +208,7 @@ func (t *transport) sendReceive(s7Request []byte) ([]byte, error) {
 	if err := t.sendTPKT(payload); err != nil {
 		t.connected = false
 		logging.DebugDisconnect("S7", t.address, fmt.Sprintf("send failed: %v", err))
-		return nil, err
+		return nil, t.withContextErr(err)
 	}
 
 	// Receive response
@@ -183,7 +216,7 @@ func (t *transport) sendReceive(s7Request []byte) ([]byte, error) {
 	if err != nil {
 		t.connected = false
 		logging.DebugDisconnect("S7", t.address, fmt.Sprintf("recv failed: %v", err))
-		return nil, err
+		return nil, t.withContextErr(err)
 	}
 
 	// Skip COTP DT header (3 bytes)
@@ -199,6 +232,55 @@ func (t *transport) sendReceive(s7Request []byte) ([]byte, error) {
 	return response[3:], nil
 }
 
+// setContext binds ctx to subsequent exchanges; nil clears the binding.
+func (t *transport) setContext(ctx context.Context) {
+	t.mu.Lock()
+	t.ctx = ctx
+	t.mu.Unlock()
+}
+
+// contextErr returns the bound context's error, if any. Caller must hold t.mu.
+func (t *transport) contextErr() error {
+	if t.ctx == nil {
+		return nil
+	}
+	return t.ctx.Err()
+}
+
+// withContextErr attaches the bound context's error to an I/O failure caused
+// by cancellation so callers can match it with errors.Is. Caller must hold t.mu.
+func (t *transport) withContextErr(err error) error {
+	if ctxErr := t.contextErr(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	return err
+}
+
+// ioDeadline returns the transport timeout, shortened to the bound context's
+// deadline if that is sooner. Caller must hold t.mu.
+func (t *transport) ioDeadline() time.Time {
+	deadline := time.Now().Add(t.timeout)
+	if t.ctx != nil {
+		if d, ok := t.ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+	}
+	return deadline
+}
+
+// watchContext unblocks pending socket I/O when the bound context is
+// cancelled. The returned function stops the watch. Caller must hold t.mu.
+func (t *transport) watchContext() (stop func()) {
+	if t.ctx == nil || t.ctx.Done() == nil || t.conn == nil {
+		return func() {}
+	}
+	conn := t.conn
+	stopWatch := context.AfterFunc(t.ctx, func() {
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	})
+	return func() { stopWatch() }
+}
+
 // sendTPKT sends data with TPKT framing.
 func (t *transport) sendTPKT(data []byte) error {
 	length := len(data) + tpktHeaderSize
@@ -255,9 +337,14 @@ func (t *transport) recvTPKT() ([]byte, error) {
 // cotpConnect performs COTP connection request/confirm exchange.
 func (t *transport) cotpConnect() error {
 	// Build COTP Connection Request
-	// TSAP format: local = 01 00, remote = 01 (rack<<5 | slot)
+	// TSAP format: local = 01 00, remote = 01 (rack<<5 | slot), unless
+	// given explicitly
 	srcTSAP := []byte{0x01, 0x00}
 	dstTSAP := []byte{0x01, byte(t.rack<<5 | t.slot)}
+	if t.remoteTSAP != 0 {
+		srcTSAP = []byte{byte(t.localTSAP >> 8), byte(t.localTSAP)}
+		dstTSAP = []byte{byte(t.remoteTSAP >> 8), byte(t.remoteTSAP)}
+	}
 
 	// COTP CR PDU
 	cr := []byte{
@@ -24,6 +24,8 @@ const (
 	TypeDate    uint16 = 0x000A // 16 bits (days since 1990-01-01)
 	TypeTime    uint16 = 0x000B // 32 bits (milliseconds)
 	TypeTimeOfDay uint16 = 0x000C // 32 bits (milliseconds since midnight)
+	TypeS5Time  uint16 = 0x000D // 16 bits BCD (time base + 3 digits)
+	TypeDateAndTime uint16 = 0x000E // 8 bytes BCD (year..milliseconds, weekday)
 	TypeLWord   uint16 = 0x0010 // 64 bits unsigned (S7-1500)
 	TypeULInt   uint16 = 0x0010 // 64 bits unsigned (alias for LWORD)
 	TypeLInt    uint16 = 0x0011 // 64 bits signed (S7-1500)
@@ -31,6 +33,7 @@ const (
 	TypeWChar   uint16 = 0x0013 // 16 bits wide character
 	TypeString  uint16 = 0x0014 // S7 String (max 254 chars)
 	TypeWString uint16 = 0x0015 // Wide string (S7-1500)
+	TypeDTL     uint16 = 0x0016 // 12 bytes date and time with nanoseconds (S7-1200/1500)
 
 	// Array flag - when set, indicates an array of the base type
 	TypeArrayFlag uint16 = 0x1000
@@ -47,12 +50,14 @@ func TypeSize(dataType uint16) int {
 		return 1 // Stored as 1 byte
 	case TypeByte, TypeChar, TypeSInt: // TypeUSInt == TypeByte
 		return 1
-	case TypeWord, TypeInt, TypeDate, TypeWChar: // TypeUInt == TypeWord
+	case TypeWord, TypeInt, TypeDate, TypeWChar, TypeS5Time: // TypeUInt == TypeWord
 		return 2
 	case TypeDWord, TypeDInt, TypeReal, TypeTime, TypeTimeOfDay: // TypeUDInt == TypeDWord
 		return 4
-	case TypeLWord, TypeLInt, TypeLReal: // TypeULInt == TypeLWord
+	case TypeLWord, TypeLInt, TypeLReal, TypeDateAndTime: // TypeULInt == TypeLWord
 		return 8
+	case TypeDTL:
+		return 12
 	case TypeString, TypeWString:
 		return 0 // Variable length
 	default:
@@ -106,6 +111,12 @@ func TypeName(dataType uint16) string {
 		name = "TIME"
 	case TypeTimeOfDay:
 		name = "TIME_OF_DAY"
+	case TypeS5Time:
+		name = "S5TIME"
+	case TypeDateAndTime:
+		name = "DATE_AND_TIME"
+	case TypeDTL:
+		name = "DTL"
 	case TypeLWord:
 		name = "LWORD"
 	case TypeLInt:
@@ -167,6 +178,12 @@ func TypeCodeFromName(name string) (uint16, bool) {
 		typeCode, ok = TypeTime, true
 	case "TIME_OF_DAY", "TOD":
 		typeCode, ok = TypeTimeOfDay, true
+	case "S5TIME":
+		typeCode, ok = TypeS5Time, true
+	case "DATE_AND_TIME", "DT":
+		typeCode, ok = TypeDateAndTime, true
+	case "DTL":
+		typeCode, ok = TypeDTL, true
 	case "LWORD", "ULINT":
 		typeCode, ok = TypeLWord, true
 	case "LINT":
@@ -195,8 +212,9 @@ func SupportedTypeNames() []string {
 	return []string{
 		"BOOL", "BYTE", "CHAR", "SINT", "USINT",
 		"WORD", "INT", "UINT",
-		"DWORD", "DINT", "UDINT", "REAL", "TIME",
+		"DWORD", "DINT", "UDINT", "REAL",
 		"LWORD", "LINT", "ULINT", "LREAL",
+		"S5TIME", "TIME", "TIME_OF_DAY", "DATE", "DATE_AND_TIME", "DTL",
 		"STRING", "WSTRING",
 	}
 }
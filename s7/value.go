@@ -4,6 +4,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"strings"
+	"time"
 )
 
 // TagValue represents the result of reading an S7 address with type conversion helpers.
@@ -126,15 +128,49 @@ func (v *TagValue) Float() (float64, error) {
 	}
 }
 
+// Time returns the tag value as a time.Time (UTC).
+// Works for DATE, DATE_AND_TIME and DTL types.
+func (v *TagValue) Time() (time.Time, error) {
+	if v.Error != nil {
+		return time.Time{}, v.Error
+	}
+	switch v.DataType {
+	case TypeDate, TypeDateAndTime, TypeDTL:
+		if t, ok := decodeTimeValue(v.DataType, v.Bytes).(time.Time); ok {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("insufficient data for %s", v.TypeName())
+	default:
+		return time.Time{}, fmt.Errorf("type mismatch: expected date, got %s", v.TypeName())
+	}
+}
+
+// Duration returns the tag value as a time.Duration.
+// Works for S5TIME, TIME and TIME_OF_DAY (time since midnight) types.
+func (v *TagValue) Duration() (time.Duration, error) {
+	if v.Error != nil {
+		return 0, v.Error
+	}
+	switch v.DataType {
+	case TypeS5Time, TypeTime, TypeTimeOfDay:
+		if d, ok := decodeTimeValue(v.DataType, v.Bytes).(time.Duration); ok {
+			return d, nil
+		}
+		return 0, fmt.Errorf("insufficient data for %s", v.TypeName())
+	default:
+		return 0, fmt.Errorf("type mismatch: expected duration, got %s", v.TypeName())
+	}
+}
+
 // GoValue returns the tag value converted to an appropriate Go type.
 // Returns nil if there's an error. The returned type depends on the tag's data type:
 //   - BOOL -> bool (or []bool for arrays)
 //   - SINT, INT, DINT, LINT -> int64 (or []int64 for arrays)
 //   - BYTE, WORD, DWORD, ULINT -> uint64 (or []uint64 for arrays)
 //   - REAL, LREAL -> float64 (or []float64 for arrays)
-//   - CHAR, WCHAR -> uint64 (character code)
-//   - TIME, TIME_OF_DAY -> int64 (milliseconds)
-//   - DATE -> int64 (days since 1990-01-01)
+//   - CHAR, WCHAR -> uint64 (character code); arrays of CHAR -> string
+//   - S5TIME, TIME, TIME_OF_DAY -> time.Duration (TIME_OF_DAY since midnight)
+//   - DATE, DATE_AND_TIME, DTL -> time.Time (UTC)
 //   - Unknown -> []int (byte array for JSON compatibility)
 //
 // Note: S7 uses big-endian byte order natively.
@@ -196,9 +232,9 @@ func (v *TagValue) parseScalar(baseType uint16) interface{} {
 		if len(v.Bytes) >= 2 {
 			return uint64(binary.BigEndian.Uint16(v.Bytes))
 		}
-	case TypeDate:
-		if len(v.Bytes) >= 2 {
-			return int64(binary.BigEndian.Uint16(v.Bytes)) // Days since 1990-01-01
+	case TypeDate, TypeS5Time, TypeTime, TypeTimeOfDay, TypeDateAndTime, TypeDTL:
+		if value := decodeTimeValue(baseType, v.Bytes); value != nil {
+			return value
 		}
 	case TypeDInt:
 		if len(v.Bytes) >= 4 {
@@ -213,10 +249,6 @@ func (v *TagValue) parseScalar(baseType uint16) interface{} {
 			bits := binary.BigEndian.Uint32(v.Bytes)
 			return float64(math.Float32frombits(bits))
 		}
-	case TypeTime, TypeTimeOfDay:
-		if len(v.Bytes) >= 4 {
-			return int64(binary.BigEndian.Uint32(v.Bytes)) // Milliseconds
-		}
 	case TypeLInt:
 		if len(v.Bytes) >= 8 {
 			return int64(binary.BigEndian.Uint64(v.Bytes))
@@ -282,6 +314,24 @@ func (v *TagValue) parseArray(baseType uint16) interface{} {
 	}
 
 	switch baseType {
+	case TypeChar:
+		// ARRAY OF CHAR holds fixed-length text padded with NULs
+		return strings.TrimRight(string(v.Bytes[:count]), "\x00")
+
+	case TypeS5Time, TypeTime, TypeTimeOfDay:
+		result := make([]time.Duration, count)
+		for i := 0; i < count; i++ {
+			result[i], _ = decodeTimeValue(baseType, v.Bytes[i*elemSize:]).(time.Duration)
+		}
+		return result
+
+	case TypeDate, TypeDateAndTime, TypeDTL:
+		result := make([]time.Time, count)
+		for i := 0; i < count; i++ {
+			result[i], _ = decodeTimeValue(baseType, v.Bytes[i*elemSize:]).(time.Time)
+		}
+		return result
+
 	case TypeBool:
 		result := make([]bool, count)
 		for i := 0; i < count; i++ {
@@ -296,7 +346,7 @@ func (v *TagValue) parseArray(baseType uint16) interface{} {
 		}
 		return result
 
-	case TypeByte, TypeChar:
+	case TypeByte:
 		result := make([]uint64, count)
 		for i := 0; i < count; i++ {
 			result[i] = uint64(v.Bytes[i])
@@ -311,7 +361,7 @@ func (v *TagValue) parseArray(baseType uint16) interface{} {
 		}
 		return result
 
-	case TypeWord, TypeDate, TypeWChar: // TypeUInt is an alias for TypeWord
+	case TypeWord, TypeWChar: // TypeUInt is an alias for TypeWord
 		result := make([]uint64, count)
 		for i := 0; i < count; i++ {
 			offset := i * 2
@@ -327,7 +377,7 @@ func (v *TagValue) parseArray(baseType uint16) interface{} {
 		}
 		return result
 
-	case TypeDWord, TypeTime, TypeTimeOfDay: // TypeUDInt is an alias for TypeDWord
+	case TypeDWord: // TypeUDInt is an alias for TypeDWord
 		result := make([]uint64, count)
 		for i := 0; i < count; i++ {
 			offset := i * 4
@@ -377,7 +427,10 @@ func (v *TagValue) parseArray(baseType uint16) interface{} {
 // parseStringArray parses an array of S7 STRING values.
 // Each S7 STRING is 256 bytes: 1 byte max length, 1 byte actual length, 254 chars.
 func (v *TagValue) parseStringArray() []string {
-	const elemSize = 256 // Standard S7 STRING size
+	elemSize := 256 // Standard S7 STRING size
+	if v.Count > 1 && len(v.Bytes)%v.Count == 0 {
+		elemSize = len(v.Bytes) / v.Count // Declared length, e.g. STRING[20]
+	}
 	count := len(v.Bytes) / elemSize
 	if count == 0 {
 		// Try to parse as a single string if we have at least the header
@@ -398,8 +451,8 @@ func (v *TagValue) parseStringArray() []string {
 			break
 		}
 		strLen := int(v.Bytes[offset+1]) // Actual length byte
-		if strLen > 254 {
-			strLen = 254
+		if strLen > elemSize-2 {
+			strLen = elemSize - 2
 		}
 		if offset+2+strLen > len(v.Bytes) {
 			strLen = len(v.Bytes) - offset - 2
@@ -414,7 +467,10 @@ func (v *TagValue) parseStringArray() []string {
 // parseWStringArray parses an array of S7 WSTRING values.
 // Each S7 WSTRING is 512 bytes: 2 bytes max length, 2 bytes actual length, 508 bytes UTF-16BE chars.
 func (v *TagValue) parseWStringArray() []string {
-	const elemSize = 512 // Standard S7 WSTRING size
+	elemSize := 512 // Standard S7 WSTRING size
+	if v.Count > 1 && len(v.Bytes)%v.Count == 0 {
+		elemSize = len(v.Bytes) / v.Count // Declared length, e.g. WSTRING[20]
+	}
 	count := len(v.Bytes) / elemSize
 	if count == 0 {
 		// Try to parse as a single wstring if we have at least the header
@@ -440,8 +496,8 @@ func (v *TagValue) parseWStringArray() []string {
 			break
 		}
 		strLen := int(binary.BigEndian.Uint16(v.Bytes[offset+2:offset+4])) * 2 // UTF-16 char count * 2
-		if strLen > 508 {
-			strLen = 508
+		if strLen > elemSize-4 {
+			strLen = elemSize - 4
 		}
 		if offset+4+strLen > len(v.Bytes) {
 			strLen = len(v.Bytes) - offset - 4
@@ -469,3 +525,56 @@ func (v *TagValue) bytesToIntArray() []int {
 func (v *TagValue) TypeName() string {
 	return TypeName(v.DataType)
 }
+
+// s7Epoch is day 0 of the S7 DATE type.
+var s7Epoch = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// s5TimeBases are the S5TIME time bases selected by bits 12-13.
+var s5TimeBases = [4]time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second, 10 * time.Second}
+
+// decodeTimeValue decodes a date/time type from big-endian bytes. Returns
+// time.Duration or time.Time, or nil if b is too short.
+func decodeTimeValue(dataType uint16, b []byte) interface{} {
+	if len(b) < TypeSize(dataType) {
+		return nil
+	}
+	switch BaseType(dataType) {
+	case TypeS5Time:
+		// [00 base 2][BCD hundreds 4][BCD tens 4][BCD ones 4]
+		raw := binary.BigEndian.Uint16(b)
+		value := int(raw>>8&0x0F)*100 + int(raw>>4&0x0F)*10 + int(raw&0x0F)
+		return time.Duration(value) * s5TimeBases[raw>>12&0x03]
+	case TypeTime:
+		return time.Duration(int32(binary.BigEndian.Uint32(b))) * time.Millisecond
+	case TypeTimeOfDay:
+		return time.Duration(binary.BigEndian.Uint32(b)) * time.Millisecond
+	case TypeDate:
+		return s7Epoch.AddDate(0, 0, int(binary.BigEndian.Uint16(b)))
+	case TypeDateAndTime:
+		// BCD: [year][month][day][hour][minute][second][ms hundreds+tens][ms ones, weekday]
+		year := fromBCD(b[0])
+		if year < 90 {
+			year += 2000
+		} else {
+			year += 1900
+		}
+		ms := fromBCD(b[6])*10 + int(b[7]>>4)
+		return time.Date(year, time.Month(fromBCD(b[1])), fromBCD(b[2]),
+			fromBCD(b[3]), fromBCD(b[4]), fromBCD(b[5]), ms*int(time.Millisecond), time.UTC)
+	case TypeDTL:
+		// [year 2][month][day][weekday][hour][minute][second][nanoseconds 4]
+		return time.Date(int(binary.BigEndian.Uint16(b[0:2])), time.Month(b[2]), int(b[3]),
+			int(b[5]), int(b[6]), int(b[7]), int(binary.BigEndian.Uint32(b[8:12])), time.UTC)
+	}
+	return nil
+}
+
+// fromBCD decodes a two-digit BCD byte.
+func fromBCD(b byte) int {
+	return int(b>>4)*10 + int(b&0x0F)
+}
+
+// toBCD encodes 0-99 as a two-digit BCD byte.
+func toBCD(n int) byte {
+	return byte(n/10<<4 | n%10)
+}
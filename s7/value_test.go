@@ -0,0 +1,69 @@
+package s7
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestDateTimeRoundTrip(t *testing.T) {
+	when := time.Date(2024, 2, 29, 13, 45, 7, 123000000, time.UTC) // Thursday
+
+	tests := []struct {
+		dataType uint16
+		value    interface{}
+		raw      []byte
+	}{
+		{TypeS5Time, 2500 * time.Millisecond, []byte{0x02, 0x50}},
+		{TypeS5Time, 90 * time.Second, []byte{0x19, 0x00}},
+		{TypeTime, -1500 * time.Millisecond, []byte{0xFF, 0xFF, 0xFA, 0x24}},
+		{TypeTimeOfDay, 13*time.Hour + 45*time.Minute + 7123*time.Millisecond, []byte{0x02, 0xF3, 0x6B, 0x33}},
+		{TypeDate, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), []byte{0x30, 0xBD}},
+		{TypeDateAndTime, when, []byte{0x24, 0x02, 0x29, 0x13, 0x45, 0x07, 0x12, 0x35}},
+		{TypeDTL, when, []byte{0x07, 0xE8, 2, 29, 5, 13, 45, 7, 0x07, 0x54, 0xD4, 0xC0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(TypeName(tt.dataType), func(t *testing.T) {
+			encoded, err := encodeTimeValue(tt.dataType, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(encoded, tt.raw) {
+				t.Errorf("encoded % X, want % X", encoded, tt.raw)
+			}
+
+			v := &TagValue{DataType: tt.dataType, Bytes: tt.raw, Count: 1}
+			if got := v.GoValue(); got != tt.value {
+				t.Errorf("decoded %v, want %v", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestEncodeTimeValueRange(t *testing.T) {
+	if _, err := encodeTimeValue(TypeS5Time, 10000*time.Second); err == nil {
+		t.Error("expected S5TIME range error")
+	}
+	if _, err := encodeTimeValue(TypeDateAndTime, time.Date(2090, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected DATE_AND_TIME range error")
+	}
+	if _, err := encodeTimeValue(TypeDate, 5); err == nil {
+		t.Error("expected type error for DATE from int")
+	}
+}
+
+func TestCharArrayValue(t *testing.T) {
+	v := &TagValue{DataType: MakeArrayType(TypeChar), Bytes: []byte("PUMP\x00\x00\x00\x00"), Count: 8}
+	if got := v.GoValue(); got != "PUMP" {
+		t.Errorf("got %q, want %q", got, "PUMP")
+	}
+
+	encoded, err := encodeChars("PUMP", 8)
+	if err != nil || !bytes.Equal(encoded, v.Bytes) {
+		t.Errorf("encodeChars: % X, %v", encoded, err)
+	}
+	if _, err := encodeChars("TOO LONG", 4); err == nil {
+		t.Error("expected length error")
+	}
+}
@@ -0,0 +1,135 @@
+package s7
+
+import (
+	"fmt"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// TagWrite is one value for WriteMany.
+type TagWrite struct {
+	Address  string      // S7 address (e.g., "DB1.0" or "DB1.DBD0")
+	Value    interface{} // Value to write
+	TypeHint string      // Optional type name (e.g., "DINT") - used when address doesn't specify type
+}
+
+// maxWriteItems caps the items per Write Variable job. S7-300/400 CPUs
+// accept up to 20 items per job; one below that matches the read batching
+// in ReadWithTypes and leaves room for CPUs that reject a full 20.
+const maxWriteItems = 19
+
+// pendingWrite is an encoded write waiting to be packed into a job.
+type pendingWrite struct {
+	index int
+	addr  *Address
+	data  []byte
+}
+
+// WriteMany writes several addresses, packing as many items into each Write
+// Variable job as the negotiated PDU size allows. Values too large for a
+// single PDU are written on their own in chunks, like WriteWithType.
+//
+// The returned slice holds one error per write in request order: nil on
+// success, an S7Error for items the PLC rejected, or the encoding error. The
+// error return is non-nil only when the connection was lost, in which case
+// it wraps ErrConnectionLost.
+func (c *Client) WriteMany(writes []TagWrite) ([]error, error) {
+	if c == nil || c.transport == nil {
+		return nil, fmt.Errorf("WriteMany: nil client")
+	}
+
+	errs := make([]error, len(writes))
+	if len(writes) == 0 {
+		return errs, nil
+	}
+
+	addrs := make([]*Address, len(writes))
+	for i, w := range writes {
+		addrs[i], errs[i] = c.resolveWriteAddress(w.Address, w.Value, w.TypeHint)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pduSize := int(c.transport.getPDUSize())
+	if pduSize < 50 {
+		pduSize = 240 // Fallback to minimum S7 PDU if not set
+	}
+	// Request: 10 byte header + function and item count, then per item a
+	// 12 byte S7ANY parameter and a 4 byte data header plus padded data.
+	maxPayload := pduSize - 12
+
+	var batch []pendingWrite
+	var batchSize int
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		c.writeBatch(batch, errs)
+		batch = nil
+		batchSize = 0
+	}
+
+	for i, w := range writes {
+		if errs[i] != nil {
+			continue
+		}
+		if !c.transport.isConnected() {
+			errs[i] = fmt.Errorf("write incomplete: %w", ErrConnectionLost)
+			continue
+		}
+
+		data, err := c.encodeValue(addrs[i], w.Value)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		itemSize := 12 + 4 + len(data) + len(data)%2
+		if itemSize > maxPayload {
+			// Too large to share a job - write it on its own in chunks
+			flush()
+			errs[i] = c.writeAddress(addrs[i], data)
+			continue
+		}
+
+		if batchSize+itemSize > maxPayload || len(batch) >= maxWriteItems {
+			flush()
+		}
+		batch = append(batch, pendingWrite{index: i, addr: addrs[i], data: data})
+		batchSize += itemSize
+	}
+	flush()
+
+	if !c.transport.isConnected() {
+		return errs, fmt.Errorf("write incomplete: %w", ErrConnectionLost)
+	}
+	return errs, nil
+}
+
+// writeBatch sends one Write Variable job for batch and records each item's
+// result in errs.
+func (c *Client) writeBatch(batch []pendingWrite, errs []error) {
+	addrs := make([]*Address, len(batch))
+	data := make([][]byte, len(batch))
+	for i, p := range batch {
+		addrs[i] = p.addr
+		data[i] = p.data
+	}
+
+	logging.DebugLog("S7", "Batch write %d items (%d data bytes)", len(batch), writeDataLen(data))
+
+	response, err := c.transport.sendReceive(buildWriteManyRequest(addrs, data, c.nextPDURef()))
+	if err != nil {
+		logging.DebugLog("S7", "Batch write failed: %v", err)
+		for _, p := range batch {
+			errs[p.index] = err
+		}
+		return
+	}
+
+	for i, err := range parseWriteManyResponse(response, len(batch)) {
+		errs[batch[i].index] = err
+	}
+}
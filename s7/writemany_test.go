@@ -0,0 +1,99 @@
+package s7
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWriteManySplitsAcrossPDUs(t *testing.T) {
+	local, plc := net.Pipe()
+	defer plc.Close()
+
+	itemCounts := make(chan int, 4)
+	go func() {
+		for {
+			req, err := readFakeS7(plc)
+			if err != nil {
+				return
+			}
+			n := int(req[11])
+			itemCounts <- n
+
+			resp := []byte{s7ProtocolID, s7MsgAckData, 0x00, 0x00, req[4], req[5], 0x00, 0x02, 0x00, byte(n), 0x00, 0x00, s7FuncWrite, byte(n)}
+			for i := 0; i < n; i++ {
+				code := byte(dataItemSuccess)
+				// Reject DB1.DBD12, the fourth item of the first job
+				if req[12+i*12+9] == 0 && req[12+i*12+10] == 0 && req[12+i*12+11] == 12*8 {
+					code = dataItemAddressError
+				}
+				resp = append(resp, code)
+			}
+			writeFakeS7(plc, resp)
+		}
+	}()
+
+	c := &Client{transport: &transport{conn: local, timeout: 2 * time.Second, connected: true, pduSize: 240}}
+
+	var writes []TagWrite
+	for i := 0; i < 20; i++ {
+		writes = append(writes, TagWrite{Address: fmt.Sprintf("DB1.DBD%d", i*4), Value: int32(i)})
+	}
+	writes = append(writes,
+		TagWrite{Address: "bogus", Value: 1},
+		TagWrite{Address: "DB1.200", Value: "text", TypeHint: "REAL"},
+	)
+
+	errs, err := c.WriteMany(writes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != len(writes) {
+		t.Fatalf("got %d errors, want %d", len(errs), len(writes))
+	}
+
+	// 20 DWORD items at 20 bytes each fit 11 to a 240 byte PDU
+	if a, b := <-itemCounts, <-itemCounts; a != 11 || b != 9 {
+		t.Errorf("items per job = %d, %d; want 11, 9", a, b)
+	}
+
+	for i, e := range errs {
+		switch i {
+		case 3:
+			var s7Err S7Error
+			if !errors.As(e, &s7Err) || s7Err.Class != errClassDataItem || s7Err.Code != dataItemAddressError {
+				t.Errorf("item %d: expected address error, got %v", i, e)
+			}
+		case 20, 21:
+			if e == nil {
+				t.Errorf("item %d: expected error", i)
+			}
+		default:
+			if e != nil {
+				t.Errorf("item %d: unexpected error %v", i, e)
+			}
+		}
+	}
+}
+
+func TestBuildWriteManyRequest(t *testing.T) {
+	addrs := []*Address{
+		{Area: AreaDB, DBNumber: 1, Offset: 0, BitNum: 3, DataType: TypeBool, Size: 1, Count: 1},
+		{Area: AreaM, Offset: 10, BitNum: -1, DataType: TypeWord, Size: 2, Count: 1},
+	}
+	req := buildWriteManyRequest(addrs, [][]byte{{0x01}, {0x12, 0x34}}, 1)
+
+	if req[10] != s7FuncWrite || req[11] != 2 {
+		t.Fatalf("unexpected parameter header % X", req[10:12])
+	}
+	data := req[12+2*12:]
+	want := []byte{0x00, 0x03, 0x00, 0x01, 0x01, 0x00, 0x00, 0x04, 0x00, 0x10, 0x12, 0x34}
+	if string(data) != string(want) {
+		t.Errorf("data section % X, want % X", data, want)
+	}
+	if dataLen := int(req[8])<<8 | int(req[9]); dataLen != len(want) {
+		t.Errorf("data length %d, want %d", dataLen, len(want))
+	}
+}
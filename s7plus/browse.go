@@ -0,0 +1,264 @@
+package s7plus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// AccessID addresses a variable by its position in the PLC's type
+// information rather than by byte offset, which is how optimized data blocks
+// must be accessed. Area is the data block's relation ID (0x8A0E0000 | DB
+// number) and LIDs is the path of local IDs from the block down to the
+// variable. SymbolCRC may be zero, which skips the PLC's check that the
+// variable's declaration has not changed.
+type AccessID struct {
+	Area      uint32
+	LIDs      []uint32
+	SymbolCRC uint32
+}
+
+// String formats the access ID as hex fields separated by dots, e.g.
+// "8A0E0005.A.3" for LIDs 10 and 3 in DB5.
+func (a AccessID) String() string {
+	parts := []string{fmt.Sprintf("%08X", a.Area)}
+	for _, lid := range a.LIDs {
+		parts = append(parts, fmt.Sprintf("%X", lid))
+	}
+	return strings.Join(parts, ".")
+}
+
+// ParseAccessID parses the format produced by AccessID.String.
+func ParseAccessID(s string) (AccessID, error) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) < 2 || len(parts[0]) != 8 {
+		return AccessID{}, fmt.Errorf("invalid access ID %q", s)
+	}
+	area, err := strconv.ParseUint(parts[0], 16, 32)
+	if err != nil || area>>16 != ridDB {
+		return AccessID{}, fmt.Errorf("invalid access ID %q: area must be 8A0Exxxx", s)
+	}
+	id := AccessID{Area: uint32(area)}
+	for _, p := range parts[1:] {
+		lid, err := strconv.ParseUint(p, 16, 32)
+		if err != nil {
+			return AccessID{}, fmt.Errorf("invalid access ID %q: %w", s, err)
+		}
+		id.LIDs = append(id.LIDs, uint32(lid))
+	}
+	return id, nil
+}
+
+// Symbol is a variable found by Browse.
+type Symbol struct {
+	Name         string   // Full name, e.g. "Motor.Speed" or "Recipe.Steps"
+	AccessID     AccessID // Address used to read the variable
+	Softdatatype byte     // S7CommPlus type code, see TypeName
+	TypeName     string   // IEC type name, e.g. "REAL"
+	Count        int      // Number of elements; 1 unless the variable is an array
+}
+
+// Offset info types in the attribute flags of a type description
+const (
+	offsetStd        = 1
+	offsetString     = 2
+	offsetArray1Dim  = 3
+	offsetArrayMDim  = 4
+	offsetStruct     = 5
+	offsetStruct1Dim = 6
+	offsetStructMDim = 7
+)
+
+// vartype describes one variable of a type information object.
+type vartype struct {
+	lid          uint32
+	symbolCRC    uint32
+	softdatatype byte
+	offsetInfo   byte
+	count        int    // Array element count
+	relation     uint32 // Type of a struct member
+}
+
+// readVarnameList decodes the variable names of a type information object.
+// Names come in blocks, each prefixed with its length; the list ends with a
+// zero length.
+func (r *reader) readVarnameList() []string {
+	var names []string
+	for r.err == nil {
+		n := int(r.uint16())
+		if n == 0 {
+			break
+		}
+		block := &reader{b: r.bytes(n)}
+		for block.err == nil && block.remaining() > 0 {
+			l := int(block.uint8())
+			names = append(names, string(block.bytes(l)))
+			block.uint8() // Terminating NUL
+		}
+		if block.err != nil {
+			r.fail("s7plus: invalid variable name list: %v", block.err)
+		}
+	}
+	return names
+}
+
+// readVartypeList decodes the variable descriptions of a type information
+// object. Like names they come in length-prefixed blocks; each block starts
+// with the LID of its first variable.
+func (r *reader) readVartypeList() []vartype {
+	var types []vartype
+	for r.err == nil {
+		n := int(r.uint16())
+		if n == 0 {
+			break
+		}
+		block := &reader{b: r.bytes(n)}
+		lid := block.uint32LE()
+		for block.err == nil && block.remaining() > 0 {
+			vt := vartype{lid: lid, count: 1}
+			vt.symbolCRC = block.uint32LE()
+			vt.softdatatype = block.uint8()
+			flags := block.uint16()
+			vt.offsetInfo = byte(flags >> 12)
+			block.uint8() // Bit offset
+
+			// Optimized and classic offsets, unused for symbolic access
+			block.bytes(12)
+			switch vt.offsetInfo {
+			case offsetStd, offsetString:
+			case offsetArray1Dim:
+				block.uint32LE() // Lower bound
+				vt.count = int(block.uint32LE())
+			case offsetStruct:
+				vt.relation = block.uint32LE()
+				block.bytes(12)
+			case offsetStruct1Dim:
+				block.uint32LE()
+				vt.count = int(block.uint32LE())
+				vt.relation = block.uint32LE()
+				block.bytes(12)
+			default:
+				block.fail("s7plus: unsupported offset info type %d for LID %d", vt.offsetInfo, lid)
+			}
+			types = append(types, vt)
+			lid++
+		}
+		if block.err != nil {
+			r.fail("%v", block.err)
+		}
+	}
+	return types
+}
+
+// Browse lists the variables of all data blocks in the PLC program. The
+// result is cached for the life of the session. Arrays of structs and
+// multi-dimensional arrays are listed as a whole but cannot be read.
+func (c *Client) Browse() ([]Symbol, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.symbols != nil {
+		return c.symbols, nil
+	}
+
+	programs, err := c.explore(idPLCProgram, idObjectVariableTypeName, idBlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("browse program: %w", err)
+	}
+	typeInfos, err := c.explore(idTypeInfoContainer, idObjectVariableTypeName)
+	if err != nil {
+		return nil, fmt.Errorf("browse type information: %w", err)
+	}
+
+	types := map[uint32]*object{}
+	typesByName := map[string]*object{}
+	for _, o := range typeInfos {
+		o.walk(func(o *object) {
+			if o.vartypes == nil {
+				return
+			}
+			types[o.rid] = o
+			if name := o.attrString(idObjectVariableTypeName); name != "" {
+				typesByName[strings.ToLower(name)] = o
+			}
+		})
+	}
+
+	var symbols []Symbol
+	for _, p := range programs {
+		p.walk(func(db *object) {
+			if db.rid>>16 != ridDB {
+				return
+			}
+			name := db.attrString(idObjectVariableTypeName)
+			ti := types[ridTypeInfo<<16|db.rid&0xFFFF]
+			if ti == nil {
+				ti = typesByName[strings.ToLower(name)]
+			}
+			if name == "" || ti == nil {
+				logging.DebugLog("S7Plus", "No type information for DB%d %q", db.rid&0xFFFF, name)
+				return
+			}
+			symbols = appendSymbols(symbols, types, ti, name, AccessID{Area: db.rid}, 0)
+		})
+	}
+
+	c.symbols = symbols
+	c.byName = make(map[string]Symbol, len(symbols))
+	for _, s := range symbols {
+		c.byName[strings.ToLower(s.Name)] = s
+	}
+	logging.DebugLog("S7Plus", "Browse found %d symbols", len(symbols))
+	return symbols, nil
+}
+
+// appendSymbols adds the variables of type information ti below prefix.
+// Struct members are added recursively with the struct's LID in their path.
+func appendSymbols(symbols []Symbol, types map[uint32]*object, ti *object, prefix string, parent AccessID, depth int) []Symbol {
+	if depth > 8 {
+		return symbols
+	}
+	for i, vt := range ti.vartypes {
+		if i >= len(ti.varnames) {
+			break
+		}
+		name := prefix + "." + ti.varnames[i]
+		id := AccessID{
+			Area:      parent.Area,
+			LIDs:      append(append([]uint32(nil), parent.LIDs...), vt.lid),
+			SymbolCRC: vt.symbolCRC,
+		}
+		if vt.offsetInfo == offsetStruct {
+			if nested := types[vt.relation]; nested != nil {
+				symbols = appendSymbols(symbols, types, nested, name, id, depth+1)
+				continue
+			}
+		}
+		symbols = append(symbols, Symbol{
+			Name:         name,
+			AccessID:     id,
+			Softdatatype: vt.softdatatype,
+			TypeName:     TypeName(vt.softdatatype),
+			Count:        vt.count,
+		})
+	}
+	return symbols
+}
+
+// explore returns the objects below id with the given attributes.
+func (c *Client) explore(id uint32, attributes ...uint32) ([]*object, error) {
+	seq := c.nextSeq()
+	resp, err := c.exchange(buildExploreRequest(seq, c.session, id, attributes...), fnExplore, seq)
+	if err != nil {
+		return nil, err
+	}
+	r := resp.body
+	r.uint32() // Explore ID
+	objs := r.readObjects()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return objs, nil
+}
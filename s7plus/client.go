@@ -0,0 +1,302 @@
+// Package s7plus reads variables of Siemens S7-1200 PLCs with firmware
+// before V4 by symbolic access over S7CommPlus, the protocol TIA Portal
+// uses. Unlike classic S7 (package s7) it can read optimized data blocks,
+// which have no fixed byte offsets.
+//
+// Only protocol version 1 is implemented: the session carries no integrity
+// protection and no TLS. Every S7-1500 and every S7-1200 from firmware V4
+// on requires the version 3 session key exchange even without TLS, so they
+// are rejected with ErrUnsupportedProtocol. Access is read-only.
+package s7plus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+// ErrUnsupportedProtocol indicates the PLC requires an S7CommPlus version
+// with integrity protection or TLS, which this package does not implement:
+// any S7-1500, and S7-1200 firmware V4 or later.
+var ErrUnsupportedProtocol = errors.New("s7plus: PLC requires a newer S7CommPlus protocol version")
+
+// maxReadItems is the number of variables read per GetMultiVariables request.
+const maxReadItems = 20
+
+// Client is an S7CommPlus session with a PLC.
+type Client struct {
+	transport *transport
+	session   uint32
+	seq       uint16
+	mu        sync.Mutex
+
+	symbols []Symbol          // Browse result, nil until browsed
+	byName  map[string]Symbol // Lower-case name to symbol
+}
+
+// options holds configuration options for Connect.
+type options struct {
+	timeout time.Duration
+}
+
+// Option is a functional option for Connect.
+type Option func(*options)
+
+// WithTimeout configures the connection and request timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// Connect opens an S7CommPlus session with the PLC at address.
+func Connect(address string, opts ...Option) (*Client, error) {
+	return ConnectContext(context.Background(), address, opts...)
+}
+
+// ConnectContext is like Connect but aborts the TCP dial and COTP handshake
+// when ctx is cancelled or its deadline passes.
+func ConnectContext(ctx context.Context, address string, opts ...Option) (*Client, error) {
+	cfg := &options{timeout: 10 * time.Second}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	t := &transport{timeout: cfg.timeout}
+	if err := t.connect(ctx, address); err != nil {
+		return nil, fmt.Errorf("Connect: %w", err)
+	}
+
+	c := &Client{transport: t}
+	if err := c.createSession(); err != nil {
+		t.close()
+		logging.DebugConnectError("S7Plus", address, err)
+		return nil, fmt.Errorf("Connect: %w", err)
+	}
+
+	logging.DebugConnectSuccess("S7Plus", address, fmt.Sprintf("session=0x%08X", c.session))
+	return c, nil
+}
+
+// createSession creates the server session object and confirms its version.
+func (c *Client) createSession() error {
+	seq := c.nextSeq()
+	resp, err := c.exchange(buildCreateObjectRequest(seq), fnCreateObject, seq)
+	if err != nil {
+		return fmt.Errorf("create session: %w", err)
+	}
+
+	r := resp.body
+	n := int(r.uint8())
+	for i := 0; i < n; i++ {
+		id := r.uvarint32()
+		if i == 0 {
+			c.session = id
+		}
+	}
+	var version []byte
+	for _, o := range r.readObjects() {
+		o.walk(func(o *object) {
+			if v, ok := o.attributes[idServerSessionVersion]; ok && version == nil {
+				version = v.raw
+			}
+		})
+	}
+	if r.err != nil {
+		return fmt.Errorf("create session: %w", r.err)
+	}
+	if n == 0 || version == nil {
+		return fmt.Errorf("create session: no session ID or version in response")
+	}
+
+	seq = c.nextSeq()
+	if _, err := c.exchange(buildSetSessionVersionRequest(seq, c.session, version), fnSetMultiVariables, seq); err != nil {
+		return fmt.Errorf("set session version: %w", err)
+	}
+	return nil
+}
+
+// Close ends the session and closes the connection.
+func (c *Client) Close() error {
+	if c == nil || c.transport == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.transport.isConnected() {
+		seq := c.nextSeq()
+		c.exchange(buildDeleteObjectRequest(seq, c.session), fnDeleteObject, seq)
+	}
+	return c.transport.close()
+}
+
+// IsConnected returns whether the session is open.
+func (c *Client) IsConnected() bool {
+	return c != nil && c.transport != nil && c.transport.isConnected()
+}
+
+// nextSeq returns the next request sequence number.
+func (c *Client) nextSeq() uint16 {
+	c.seq++
+	return c.seq
+}
+
+// exchange sends a request PDU and checks the response header. A response
+// framed with a protocol version above 1 means the PLC wants integrity
+// protection, which ends the session.
+func (c *Client) exchange(pdu []byte, function, seq uint16) (*response, error) {
+	data, err := c.transport.sendReceive(protocolV1, pdu)
+	if err != nil {
+		return nil, err
+	}
+	if c.transport.version != protocolV1 {
+		logging.DebugLog("S7Plus", "PLC answered with protocol version %d", c.transport.version)
+		c.transport.close()
+		return nil, fmt.Errorf("%w (version %d)", ErrUnsupportedProtocol, c.transport.version)
+	}
+	return parseResponse(data, function, seq)
+}
+
+// TagValue is the result of reading one variable.
+type TagValue struct {
+	Name         string      // Name as requested
+	AccessID     AccessID    // Address the value was read from
+	Softdatatype byte        // From Browse; 0 if read by access ID alone
+	Value        interface{} // Decoded value, see Read
+	Error        error       // Per-variable error (nil if successful)
+}
+
+// Read reads variables by name or access ID. Names are resolved against the
+// Browse result (browsing first if needed) and are case-insensitive; quotes
+// as TIA Portal writes them ("Motor".Speed) are ignored. Access IDs use the
+// format of AccessID.String.
+//
+// Values have the Go type of their S7CommPlus encoding (bool, int16,
+// float32, ...). Variables found by Browse are converted further: TIME types
+// to time.Duration, DATE to time.Time, STRING and WSTRING to string, and
+// arrays to typed slices.
+func (c *Client) Read(names ...string) ([]*TagValue, error) {
+	results := make([]*TagValue, len(names))
+	var pending []int
+	for i, name := range names {
+		results[i] = &TagValue{Name: name}
+		id, sym, err := c.resolve(name)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].AccessID = id
+		results[i].Softdatatype = sym.Softdatatype
+		pending = append(pending, i)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for start := 0; start < len(pending); start += maxReadItems {
+		batch := pending[start:min(start+maxReadItems, len(pending))]
+		if err := c.readBatch(results, batch); err != nil {
+			if !c.transport.isConnected() {
+				return results, err
+			}
+			for _, i := range batch {
+				results[i].Error = err
+			}
+		}
+	}
+	return results, nil
+}
+
+// readBatch reads results[i] for each i in batch with one request.
+func (c *Client) readBatch(results []*TagValue, batch []int) error {
+	ids := make([]AccessID, len(batch))
+	for n, i := range batch {
+		ids[n] = results[i].AccessID
+	}
+
+	seq := c.nextSeq()
+	resp, err := c.exchange(buildGetMultiVariablesRequest(seq, c.session, ids), fnGetMultiVariables, seq)
+	if err != nil {
+		return err
+	}
+
+	// Values by item number, then errors by item number
+	r := resp.body
+	done := make([]bool, len(batch))
+	for r.err == nil {
+		item := int(r.uvarint32())
+		if item == 0 {
+			break
+		}
+		v := r.readValue()
+		if item > len(batch) || r.err != nil {
+			break
+		}
+		res := results[batch[item-1]]
+		res.Value = v.v
+		if res.Softdatatype != 0 {
+			res.Value = goValue(v.v, res.Softdatatype)
+		}
+		done[item-1] = true
+	}
+	for r.err == nil {
+		item := int(r.uvarint32())
+		if item == 0 {
+			break
+		}
+		rv := r.uvarint64()
+		if item <= len(batch) {
+			results[batch[item-1]].Error = ReturnError(rv)
+			done[item-1] = true
+		}
+	}
+	if r.err != nil {
+		return fmt.Errorf("read response: %w", r.err)
+	}
+	for n, ok := range done {
+		if !ok {
+			results[batch[n]].Error = fmt.Errorf("s7plus: no value in response")
+		}
+	}
+	return nil
+}
+
+// resolve maps a name or access ID to an access ID.
+func (c *Client) resolve(name string) (AccessID, Symbol, error) {
+	if id, err := ParseAccessID(name); err == nil {
+		return id, Symbol{}, nil
+	}
+	if _, err := c.Browse(); err != nil {
+		return AccessID{}, Symbol{}, err
+	}
+	c.mu.Lock()
+	sym, ok := c.byName[normalizeName(name)]
+	c.mu.Unlock()
+	if !ok {
+		return AccessID{}, Symbol{}, fmt.Errorf("s7plus: unknown symbol %q", name)
+	}
+	return sym.AccessID, sym, nil
+}
+
+// Lookup returns the browsed symbol with the given name, browsing first if
+// needed.
+func (c *Client) Lookup(name string) (Symbol, bool) {
+	if _, err := c.Browse(); err != nil {
+		return Symbol{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sym, ok := c.byName[normalizeName(name)]
+	return sym, ok
+}
+
+// normalizeName lower-cases a name and strips TIA Portal quotes.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), `"`, ""))
+}
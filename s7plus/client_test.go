@@ -0,0 +1,227 @@
+package s7plus
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakePLC answers S7CommPlus requests for DB1 "Motor" with the variables
+// Speed (REAL 1.5), Running (BOOL true) and Counts (Array[0..2] of INT).
+// Responses are framed with the given protocol version.
+type fakePLC struct {
+	conn    net.Conn
+	version byte
+}
+
+func (p *fakePLC) serve() {
+	for {
+		tpdu, err := readFakeTPKT(p.conn)
+		if err != nil {
+			return
+		}
+		if tpdu[1] == cotpCR {
+			writeFakeTPKT(p.conn, []byte{0x06, cotpCC, 0x00, 0x01, 0x00, 0x01, 0x00})
+			continue
+		}
+
+		data := tpdu[3:]
+		n := int(binary.BigEndian.Uint16(data[2:4]))
+		r := &reader{b: data[4 : 4+n]}
+		r.uint8()
+		r.uint16()
+		fn := r.uint16()
+		r.uint16()
+		seq := r.uint16()
+		r.uint32() // Session
+		r.uint8()
+
+		resp := []byte{opResponse, 0, 0, byte(fn >> 8), byte(fn), 0, 0, byte(seq >> 8), byte(seq), 0x00}
+		resp = append(resp, 0x00) // Return value OK
+		switch fn {
+		case fnCreateObject:
+			resp = append(resp, 1)
+			resp = appendUvarint32(resp, 0x3A5)
+			resp = append(resp, elemStartOfObject)
+			resp = appendUint32(resp, 0x3A5)
+			resp = appendUvarint32(resp, idClassServerSession)
+			resp = append(resp, 0x00, 0x00) // Class flags, attribute
+			resp = append(resp, elemAttribute)
+			resp = appendUvarint32(resp, idServerSessionVersion)
+			resp = append(resp, 0x00, dtUDInt, 0x81, 0x02)
+			resp = append(resp, elemTerminatingObject)
+		case fnExplore:
+			id := r.uint32()
+			resp = appendUint32(resp, id)
+			switch id {
+			case idPLCProgram:
+				resp = append(resp, elemStartOfObject)
+				resp = appendUint32(resp, 0x8A0E0001)
+				resp = append(resp, 0x00, 0x00, 0x00)
+				resp = append(resp, elemAttribute)
+				resp = appendUvarint32(resp, idObjectVariableTypeName)
+				resp = append(resp, 0x00, dtWString, 5)
+				resp = append(resp, "Motor"...)
+				resp = append(resp, elemTerminatingObject)
+			case idTypeInfoContainer:
+				resp = append(resp, elemStartOfObject)
+				resp = appendUint32(resp, 0x9EAE0001)
+				resp = append(resp, 0x00, 0x00, 0x00)
+				resp = append(resp, elemVarnameList)
+				resp = append(resp, fakeVarnames("Speed", "Running", "Counts")...)
+				resp = append(resp, elemVartypeList)
+				resp = append(resp, fakeVartypes()...)
+				resp = append(resp, elemTerminatingObject)
+			}
+		case fnGetMultiVariables:
+			r.uint32() // Link ID
+			count := int(r.uvarint32())
+			r.uvarint32()
+			var failed []int
+			for i := 1; i <= count; i++ {
+				r.uvarint32() // CRC
+				area := r.uvarint32()
+				lids := make([]uint32, r.uvarint32())
+				for j := range lids {
+					lids[j] = r.uvarint32()
+				}
+				if area != 0x8A0E0001 || len(lids) != 2 || lids[1] < 0xA || lids[1] > 0xC {
+					failed = append(failed, i)
+					continue
+				}
+				resp = appendUvarint32(resp, uint32(i))
+				switch lids[1] {
+				case 0xA:
+					resp = append(resp, 0x00, dtReal)
+					resp = appendUint32(resp, math.Float32bits(1.5))
+				case 0xB:
+					resp = append(resp, 0x00, dtBool, 0x01)
+				case 0xC:
+					resp = append(resp, flagArray, dtInt, 3, 0x00, 0x01, 0x00, 0x02, 0xFF, 0xFD)
+				}
+			}
+			resp = append(resp, 0x00)
+			for _, i := range failed {
+				resp = appendUvarint32(resp, uint32(i))
+				resp = appendUvarint64(resp, 0x800000000000FFF3) // -13
+			}
+			resp = append(resp, 0x00)
+		}
+		resp = appendUint32(resp, 0)
+
+		writeFakeTPKT(p.conn, append([]byte{0x02, cotpDT, cotpEOT}, frame(p.version, resp)...))
+	}
+}
+
+// fakeVarnames encodes a variable name list with a single block.
+func fakeVarnames(names ...string) []byte {
+	var block []byte
+	for _, n := range names {
+		block = append(block, byte(len(n)))
+		block = append(block, n...)
+		block = append(block, 0x00)
+	}
+	return append(append([]byte{byte(len(block) >> 8), byte(len(block))}, block...), 0x00, 0x00)
+}
+
+// fakeVartypes describes Speed, Running and Counts starting at LID 0xA.
+func fakeVartypes() []byte {
+	block := []byte{0x0A, 0x00, 0x00, 0x00} // First LID
+	elem := func(crc uint32, soft byte, info byte, extra ...byte) {
+		block = binary.LittleEndian.AppendUint32(block, crc)
+		block = append(block, soft, info<<4, 0x00, 0x00)
+		block = append(block, make([]byte, 12)...)
+		block = append(block, extra...)
+	}
+	elem(0x11111111, SoftReal, offsetStd)
+	elem(0x22222222, SoftBool, offsetStd)
+	elem(0x33333333, SoftInt, offsetArray1Dim, 0, 0, 0, 0, 3, 0, 0, 0)
+	return append(append([]byte{byte(len(block) >> 8), byte(len(block))}, block...), 0x00, 0x00)
+}
+
+func readFakeTPKT(conn net.Conn) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, int(binary.BigEndian.Uint16(header[2:4]))-4)
+	_, err := io.ReadFull(conn, payload)
+	return payload, err
+}
+
+func writeFakeTPKT(conn net.Conn, payload []byte) {
+	n := len(payload) + 4
+	conn.Write(append([]byte{tpktVersion, 0x00, byte(n >> 8), byte(n)}, payload...))
+}
+
+// newFakeClient performs the session setup against a fakePLC over net.Pipe.
+func newFakeClient(t *testing.T, version byte) (*Client, error) {
+	t.Helper()
+	local, remote := net.Pipe()
+	t.Cleanup(func() { remote.Close() })
+	plc := &fakePLC{conn: remote, version: version}
+	go plc.serve()
+
+	tr := &transport{conn: local, timeout: 2 * time.Second, connected: true}
+	if err := tr.cotpConnect(); err != nil {
+		return nil, err
+	}
+	c := &Client{transport: tr}
+	if err := c.createSession(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func TestSessionBrowseAndRead(t *testing.T) {
+	c, err := newFakeClient(t, protocolV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.session != 0x3A5 {
+		t.Errorf("session = %#x, want 0x3A5", c.session)
+	}
+
+	symbols, err := c.Browse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 3 {
+		t.Fatalf("browse found %d symbols, want 3: %+v", len(symbols), symbols)
+	}
+	if s := symbols[2]; s.Name != "Motor.Counts" || s.TypeName != "INT" || s.Count != 3 || s.AccessID.String() != "8A0E0001.C" {
+		t.Errorf("unexpected symbol %+v (%s)", s, s.AccessID)
+	}
+
+	values, err := c.Read(`"Motor".Speed`, "motor.running", "Motor.Counts", "8A0E0001.F", "Motor.Missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := values[0]; v.Error != nil || v.Value != float32(1.5) {
+		t.Errorf("Speed = %v, %v", v.Value, v.Error)
+	}
+	if v := values[1]; v.Error != nil || v.Value != true {
+		t.Errorf("Running = %v, %v", v.Value, v.Error)
+	}
+	if v, ok := values[2].Value.([]int16); !ok || len(v) != 3 || v[2] != -3 {
+		t.Errorf("Counts = %#v, %v", values[2].Value, values[2].Error)
+	}
+	var rerr ReturnError
+	if !errors.As(values[3].Error, &rerr) || rerr.Code() != -13 {
+		t.Errorf("8A0E0001.F: expected PLC error -13, got %v", values[3].Error)
+	}
+	if values[4].Error == nil {
+		t.Error("Motor.Missing: expected unknown symbol error")
+	}
+}
+
+func TestSessionRejectsNewerProtocol(t *testing.T) {
+	_, err := newFakeClient(t, protocolV3)
+	if !errors.Is(err, ErrUnsupportedProtocol) {
+		t.Fatalf("expected ErrUnsupportedProtocol, got %v", err)
+	}
+}
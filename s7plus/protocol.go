@@ -0,0 +1,480 @@
+package s7plus
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf16"
+)
+
+// S7CommPlus framing
+const (
+	protocolID = 0x72 // First byte of every S7CommPlus header and trailer
+
+	protocolV1 = 0x01 // No integrity protection (S7-1200 firmware before V4)
+	protocolV2 = 0x02 // Integrity part in every PDU
+	protocolV3 = 0x03 // Session key legitimation (later firmware adds TLS)
+)
+
+// Opcodes
+const (
+	opRequest  = 0x31
+	opResponse = 0x32
+)
+
+// Function codes
+const (
+	fnExplore           = 0x04BB
+	fnCreateObject      = 0x04CA
+	fnDeleteObject      = 0x04D4
+	fnSetMultiVariables = 0x0542
+	fnGetMultiVariables = 0x054C
+)
+
+// Transport flags sent with requests
+const (
+	flagsCreateObject = 0x36
+	flagsRequest      = 0x34
+)
+
+// Element IDs in object and attribute streams
+const (
+	elemStartOfObject     = 0xA1
+	elemTerminatingObject = 0xA2
+	elemAttribute         = 0xA3
+	elemRelation          = 0xA4
+	elemVartypeList       = 0xAB
+	elemVarnameList       = 0xAC
+)
+
+// Well-known object, class and attribute IDs
+const (
+	idNone                         = 0
+	idPLCProgram                   = 3   // NativeObjects.thePLCProgram
+	idObjectVariableTypeName       = 233 // Name of an object
+	idGetNewRIDOnServer            = 211
+	idClassSubscriptions           = 255
+	idObjectServerSessionContainer = 285
+	idClassServerSession           = 287
+	idObjectNullServerSession      = 288
+	idServerSessionClientRID       = 300
+	idServerSessionVersion         = 306
+	idObjectQualifier              = 1256
+	idParentRID                    = 1257
+	idCompositionAID               = 1258
+	idKeyQualifier                 = 1259
+	idTypeInfoContainer            = 537  // ObjectOMSTypeInfoContainer
+	idBlockNumber                  = 2521 // Block.BlockNumber
+	idDBValueActual                = 2550 // DB.ValueActual: current values of a DB
+)
+
+// Relation ID prefixes (high 16 bits)
+const (
+	ridDB       = 0x8A0E // Data block n is 0x8A0E0000 | n
+	ridTypeInfo = 0x9EAE // Type information of data block n is 0x9EAE0000 | n
+)
+
+// Value datatypes
+const (
+	dtNull      = 0x00
+	dtBool      = 0x01
+	dtUSInt     = 0x02
+	dtUInt      = 0x03
+	dtUDInt     = 0x04
+	dtULInt     = 0x05
+	dtSInt      = 0x06
+	dtInt       = 0x07
+	dtDInt      = 0x08
+	dtLInt      = 0x09
+	dtByte      = 0x0A
+	dtWord      = 0x0B
+	dtDWord     = 0x0C
+	dtLWord     = 0x0D
+	dtReal      = 0x0E
+	dtLReal     = 0x0F
+	dtTimestamp = 0x10
+	dtTimespan  = 0x11
+	dtRID       = 0x12
+	dtAID       = 0x13
+	dtBlob      = 0x14
+	dtWString   = 0x15
+	dtStruct    = 0x17
+)
+
+// Value flags
+const (
+	flagArray        = 0x10
+	flagAddressArray = 0x20
+	flagSparseArray  = 0x40
+)
+
+// frame wraps data in an S7CommPlus header and trailer.
+func frame(version byte, data []byte) []byte {
+	out := []byte{protocolID, version, byte(len(data) >> 8), byte(len(data))}
+	out = append(out, data...)
+	return append(out, protocolID, version, 0x00, 0x00)
+}
+
+// requestHeader starts a request PDU.
+func requestHeader(function uint16, seq uint16, session uint32, flags byte) []byte {
+	return []byte{
+		opRequest,
+		0x00, 0x00, // Reserved
+		byte(function >> 8), byte(function),
+		0x00, 0x00, // Reserved
+		byte(seq >> 8), byte(seq),
+		byte(session >> 24), byte(session >> 16), byte(session >> 8), byte(session),
+		flags,
+	}
+}
+
+// appendUint32 appends v big-endian.
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// appendObjectQualifier appends the object qualifier that ends most requests.
+func appendObjectQualifier(b []byte) []byte {
+	b = appendUint32(b, idObjectQualifier)
+	b = appendUvarint32(b, idParentRID)
+	b = append(b, 0x00, dtRID, 0, 0, 0, 0)
+	b = appendUvarint32(b, idCompositionAID)
+	b = append(b, 0x00, dtAID, 0)
+	b = appendUvarint32(b, idKeyQualifier)
+	b = append(b, 0x00, dtUDInt, 0)
+	return append(b, 0x00)
+}
+
+// buildCreateObjectRequest creates the server session object.
+func buildCreateObjectRequest(seq uint16) []byte {
+	b := requestHeader(fnCreateObject, seq, idObjectNullServerSession, flagsCreateObject)
+	b = appendUint32(b, idObjectServerSessionContainer)
+	b = append(b, 0x00, dtUDInt, 0x00) // Request value: UDInt 0
+	b = appendUint32(b, 0)
+
+	// Session object with a subscriptions child
+	b = append(b, elemStartOfObject)
+	b = appendUint32(b, idGetNewRIDOnServer)
+	b = appendUvarint32(b, idClassServerSession)
+	b = appendUvarint32(b, 0) // Class flags
+	b = appendUvarint32(b, idNone)
+	b = append(b, elemAttribute)
+	b = appendUvarint32(b, idServerSessionClientRID)
+	b = append(b, 0x00, dtRID, 0x80, 0xC3, 0xC9, 0x01)
+	b = append(b, elemStartOfObject)
+	b = appendUint32(b, idGetNewRIDOnServer)
+	b = appendUvarint32(b, idClassSubscriptions)
+	b = appendUvarint32(b, 0)
+	b = appendUvarint32(b, idNone)
+	b = append(b, elemTerminatingObject)
+	b = append(b, elemTerminatingObject)
+
+	return appendUint32(b, 0)
+}
+
+// buildSetSessionVersionRequest echoes the server session version back to
+// the PLC, which completes the session setup. version is the raw encoded
+// value from the CreateObject response.
+func buildSetSessionVersionRequest(seq uint16, session uint32, version []byte) []byte {
+	b := requestHeader(fnSetMultiVariables, seq, session, flagsRequest)
+	b = appendUint32(b, session) // Object to write
+	b = appendUvarint32(b, 1)    // Item count
+	b = appendUvarint32(b, 1)    // Address count
+	b = appendUvarint32(b, idServerSessionVersion)
+	b = appendUvarint32(b, 1) // Item number
+	b = append(b, version...)
+	b = append(b, 0x00)
+	b = appendObjectQualifier(b)
+	return appendUint32(b, 0)
+}
+
+// buildDeleteObjectRequest ends the session.
+func buildDeleteObjectRequest(seq uint16, session uint32) []byte {
+	b := requestHeader(fnDeleteObject, seq, session, flagsRequest)
+	b = appendUint32(b, session)
+	return appendUint32(b, 0)
+}
+
+// buildExploreRequest explores the children of object id and returns the
+// given attributes for each.
+func buildExploreRequest(seq uint16, session uint32, id uint32, attributes ...uint32) []byte {
+	b := requestHeader(fnExplore, seq, session, flagsRequest)
+	b = appendUint32(b, id)
+	b = appendUvarint32(b, idNone) // Explore request ID
+	b = append(b, 0x01)            // Children recursive
+	b = append(b, 0x01)            // Unknown, always 1
+	b = append(b, 0x00)            // Explore parents
+	b = append(b, 0x00)            // No filter
+	b = append(b, 0x01)            // Address list follows
+	b = appendUvarint32(b, uint32(len(attributes)))
+	for _, a := range attributes {
+		b = appendUvarint32(b, a)
+	}
+	b = appendObjectQualifier(b)
+	return appendUint32(b, 0)
+}
+
+// buildGetMultiVariablesRequest reads the values at ids.
+func buildGetMultiVariablesRequest(seq uint16, session uint32, ids []AccessID) []byte {
+	b := requestHeader(fnGetMultiVariables, seq, session, flagsRequest)
+	b = appendUint32(b, 0) // Link ID
+	b = appendUvarint32(b, uint32(len(ids)))
+	fields := 0
+	for _, id := range ids {
+		fields += 4 + len(id.LIDs)
+	}
+	b = appendUvarint32(b, uint32(fields))
+	for _, id := range ids {
+		b = appendUvarint32(b, id.SymbolCRC)
+		b = appendUvarint32(b, id.Area)
+		b = appendUvarint32(b, uint32(len(id.LIDs)+1))
+		b = appendUvarint32(b, idDBValueActual)
+		for _, lid := range id.LIDs {
+			b = appendUvarint32(b, lid)
+		}
+	}
+	b = append(b, 0x00)
+	b = appendObjectQualifier(b)
+	return appendUint32(b, 0)
+}
+
+// response is a parsed response PDU header.
+type response struct {
+	function uint16
+	seq      uint16
+	body     *reader // Positioned after the return value
+}
+
+// parseResponse checks a response PDU and reads its return value.
+func parseResponse(data []byte, function, seq uint16) (*response, error) {
+	r := &reader{b: data}
+	op := r.uint8()
+	r.uint16()
+	fn := r.uint16()
+	r.uint16()
+	s := r.uint16()
+	r.uint8() // Transport flags
+	if r.err != nil {
+		return nil, fmt.Errorf("s7plus: response too short")
+	}
+	if op != opResponse {
+		return nil, fmt.Errorf("s7plus: unexpected opcode 0x%02X", op)
+	}
+	if fn != function {
+		return nil, fmt.Errorf("s7plus: unexpected function 0x%04X in response to 0x%04X", fn, function)
+	}
+	if s != seq {
+		return nil, fmt.Errorf("s7plus: response sequence %d, expected %d", s, seq)
+	}
+	if rv := r.uvarint64(); isError(rv) {
+		return nil, ReturnError(rv)
+	} else if r.err != nil {
+		return nil, r.err
+	}
+	return &response{function: fn, seq: s, body: r}, nil
+}
+
+// ReturnError is a negative S7CommPlus return value from the PLC.
+type ReturnError uint64
+
+// Code returns the signed error code in the low 16 bits.
+func (e ReturnError) Code() int16 {
+	return int16(e)
+}
+
+// Error implements the error interface.
+func (e ReturnError) Error() string {
+	return fmt.Sprintf("s7plus: PLC returned error %d (0x%016X)", e.Code(), uint64(e))
+}
+
+// isError reports whether a return value signals an error.
+func isError(rv uint64) bool {
+	return int16(rv) < 0
+}
+
+// value is a decoded attribute or variable value. raw holds its encoding,
+// so values can be written back unchanged.
+type value struct {
+	datatype byte
+	v        interface{}
+	raw      []byte
+}
+
+// readValue decodes one value: flags, datatype and data.
+func (r *reader) readValue() value {
+	start := r.pos
+	flags := r.uint8()
+	dt := r.uint8()
+
+	var v interface{}
+	switch {
+	case flags&flagSparseArray != 0:
+		m := make(map[uint32]interface{})
+		for r.err == nil {
+			key := r.uvarint32()
+			if key == 0 {
+				break
+			}
+			m[key] = r.readScalar(dt)
+		}
+		v = m
+	case flags&(flagArray|flagAddressArray) != 0:
+		n := int(r.uvarint32())
+		if n > r.remaining() {
+			r.fail("s7plus: array of %d elements exceeds data", n)
+			break
+		}
+		arr := make([]interface{}, n)
+		for i := range arr {
+			if flags&flagAddressArray != 0 {
+				arr[i] = r.uvarint32()
+			} else {
+				arr[i] = r.readScalar(dt)
+			}
+		}
+		v = arr
+	default:
+		v = r.readScalar(dt)
+	}
+
+	var raw []byte
+	if r.err == nil {
+		raw = r.b[start:r.pos]
+	}
+	return value{datatype: dt, v: v, raw: raw}
+}
+
+// readScalar decodes a single element of datatype dt.
+func (r *reader) readScalar(dt byte) interface{} {
+	switch dt {
+	case dtNull:
+		return nil
+	case dtBool:
+		return r.uint8() != 0
+	case dtUSInt, dtByte:
+		return r.uint8()
+	case dtSInt:
+		return int8(r.uint8())
+	case dtUInt, dtWord:
+		return r.uint16()
+	case dtInt:
+		return int16(r.uint16())
+	case dtUDInt, dtAID:
+		return r.uvarint32()
+	case dtDInt:
+		return int32(r.varint64())
+	case dtULInt:
+		return r.uvarint64()
+	case dtLInt, dtTimespan:
+		return r.varint64()
+	case dtDWord, dtRID:
+		return r.uint32()
+	case dtLWord, dtTimestamp:
+		return r.uint64()
+	case dtReal:
+		return math.Float32frombits(r.uint32())
+	case dtLReal:
+		return math.Float64frombits(r.uint64())
+	case dtWString:
+		return string(r.bytes(int(r.uvarint32())))
+	case dtBlob:
+		r.uvarint32() // Blob root ID
+		return append([]byte(nil), r.bytes(int(r.uvarint32()))...)
+	case dtStruct:
+		id := r.uint32()
+		fields := map[uint32]interface{}{}
+		for r.err == nil {
+			key := r.uvarint32()
+			if key == 0 {
+				break
+			}
+			fields[key] = r.readValue().v
+		}
+		return structValue{ID: id, Fields: fields}
+	default:
+		r.fail("s7plus: unsupported datatype 0x%02X at offset %d", dt, r.pos)
+		return nil
+	}
+}
+
+// structValue is a decoded struct: its type ID and fields by ID.
+type structValue struct {
+	ID     uint32
+	Fields map[uint32]interface{}
+}
+
+// object is a node of an object stream, as returned by Explore and
+// CreateObject.
+type object struct {
+	rid        uint32
+	classID    uint32
+	attributes map[uint32]value
+	children   []*object
+	varnames   []string
+	vartypes   []vartype
+}
+
+// readObject decodes an object after its start element.
+func (r *reader) readObject() *object {
+	o := &object{
+		rid:        r.uint32(),
+		classID:    r.uvarint32(),
+		attributes: map[uint32]value{},
+	}
+	r.uvarint32() // Class flags
+	r.uvarint32() // Attribute ID
+
+	for r.err == nil {
+		switch tag := r.uint8(); tag {
+		case elemTerminatingObject:
+			return o
+		case elemStartOfObject:
+			o.children = append(o.children, r.readObject())
+		case elemAttribute:
+			id := r.uvarint32()
+			o.attributes[id] = r.readValue()
+		case elemRelation:
+			r.uvarint32() // Relation ID
+			r.uint32()    // Related object
+		case elemVarnameList:
+			o.varnames = r.readVarnameList()
+		case elemVartypeList:
+			o.vartypes = r.readVartypeList()
+		default:
+			r.fail("s7plus: unexpected element 0x%02X at offset %d", tag, r.pos-1)
+		}
+	}
+	return o
+}
+
+// readObjects decodes the objects that follow in a response body.
+func (r *reader) readObjects() []*object {
+	var objs []*object
+	for r.err == nil && r.peek() == elemStartOfObject {
+		r.uint8()
+		objs = append(objs, r.readObject())
+	}
+	return objs
+}
+
+// attrString returns a string attribute, or "".
+func (o *object) attrString(id uint32) string {
+	s, _ := o.attributes[id].v.(string)
+	return s
+}
+
+// walk calls fn for o and all of its descendants.
+func (o *object) walk(fn func(*object)) {
+	fn(o)
+	for _, c := range o.children {
+		c.walk(fn)
+	}
+}
+
+// utf16String decodes big-endian UTF-16, as used by WSTRING variables.
+func utf16String(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(u))
+}
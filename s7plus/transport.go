@@ -0,0 +1,260 @@
+package s7plus
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/yatesdr/plcio/logging"
+)
+
+const (
+	defaultPort = 102
+
+	// TPKT constants (RFC 1006)
+	tpktVersion    = 0x03
+	tpktHeaderSize = 4
+
+	// COTP PDU types (ISO 8073)
+	cotpCR = 0xE0 // Connection Request
+	cotpCC = 0xD0 // Connection Confirm
+	cotpDT = 0xF0 // Data Transfer
+
+	cotpEOT = 0x80 // Last data unit of a message
+
+	// COTP parameter codes
+	cotpParamSrcTSAP  = 0xC1
+	cotpParamDstTSAP  = 0xC2
+	cotpParamTPDUSize = 0xC0
+
+	cotpTPDUSize1024 = 0x0A
+
+	// S7CommPlus connects to a named TSAP instead of a rack and slot
+	localTSAP  = 0x0600
+	remoteTSAP = "SIMATIC-ROOT-HMI"
+)
+
+// transport carries S7CommPlus PDUs over ISO-on-TCP.
+type transport struct {
+	mu        sync.Mutex
+	conn      net.Conn
+	address   string
+	timeout   time.Duration
+	version   byte // Protocol version announced by the PLC
+	connected bool
+}
+
+// connect dials address and performs the COTP handshake with the S7CommPlus
+// TSAP. A non-nil ctx bounds the dial and handshake.
+func (t *transport) connect(ctx context.Context, address string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		address = fmt.Sprintf("%s:%d", address, defaultPort)
+	} else if port == "" {
+		address = fmt.Sprintf("%s:%d", host, defaultPort)
+	}
+	t.address = address
+
+	logging.DebugConnect("S7Plus", address)
+
+	d := net.Dialer{Timeout: t.timeout}
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		logging.DebugConnectError("S7Plus", address, err)
+		return fmt.Errorf("TCP connect failed: %w", err)
+	}
+	t.conn = conn
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	deadline := time.Now().Add(t.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	if err := t.cotpConnect(); err != nil {
+		conn.Close()
+		logging.DebugError("S7Plus", "COTP connect", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("COTP connect failed: %w: %w", ctxErr, err)
+		}
+		return fmt.Errorf("COTP connect failed: %w", err)
+	}
+
+	conn.SetDeadline(time.Time{})
+	t.connected = true
+	return nil
+}
+
+// close closes the connection.
+func (t *transport) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.connected = false
+	if t.conn != nil {
+		logging.DebugDisconnect("S7Plus", t.address, "close requested")
+		err := t.conn.Close()
+		t.conn = nil
+		return err
+	}
+	return nil
+}
+
+// isConnected returns whether the transport is connected.
+func (t *transport) isConnected() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.connected
+}
+
+// sendReceive frames pdu with the given protocol version, sends it and
+// returns the data of the response PDU. The response's protocol version is
+// recorded in t.version.
+func (t *transport) sendReceive(version byte, pdu []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.connected || t.conn == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	if err := t.conn.SetDeadline(time.Now().Add(t.timeout)); err != nil {
+		t.connected = false
+		return nil, fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	payload := append([]byte{0x02, cotpDT, cotpEOT}, frame(version, pdu)...)
+	if err := t.sendTPKT(payload); err != nil {
+		t.connected = false
+		logging.DebugDisconnect("S7Plus", t.address, fmt.Sprintf("send failed: %v", err))
+		return nil, err
+	}
+
+	data, err := t.recvPDU()
+	if err != nil {
+		t.connected = false
+		logging.DebugDisconnect("S7Plus", t.address, fmt.Sprintf("recv failed: %v", err))
+		return nil, err
+	}
+	return data, nil
+}
+
+// recvPDU receives one S7CommPlus PDU. Large PDUs arrive as several
+// fragments, each with its own header; only the last carries a trailer.
+func (t *transport) recvPDU() ([]byte, error) {
+	var message []byte
+	for {
+		tpdu, err := t.recvTPKT()
+		if err != nil {
+			return nil, err
+		}
+		if len(tpdu) < 3 || tpdu[1] != cotpDT {
+			return nil, fmt.Errorf("expected COTP DT")
+		}
+		message = append(message, tpdu[3:]...)
+		if tpdu[2]&cotpEOT != 0 {
+			data, done, err := t.unframe(message)
+			if err != nil || done {
+				return data, err
+			}
+		}
+	}
+}
+
+// unframe strips the headers and trailer of a complete or partial message.
+// done is false if the trailer has not arrived yet.
+func (t *transport) unframe(message []byte) (data []byte, done bool, err error) {
+	for len(message) > 0 {
+		if len(message) < 4 || message[0] != protocolID {
+			return nil, false, fmt.Errorf("invalid S7CommPlus header")
+		}
+		t.version = message[1]
+		n := int(binary.BigEndian.Uint16(message[2:4]))
+		if len(message) < 4+n {
+			return nil, false, nil
+		}
+		data = append(data, message[4:4+n]...)
+		message = message[4+n:]
+		if len(message) >= 4 && message[0] == protocolID && binary.BigEndian.Uint16(message[2:4]) == 0 {
+			return data, true, nil // Trailer
+		}
+	}
+	return nil, false, nil
+}
+
+// sendTPKT sends data with TPKT framing.
+func (t *transport) sendTPKT(data []byte) error {
+	length := len(data) + tpktHeaderSize
+	packet := append([]byte{tpktVersion, 0x00, byte(length >> 8), byte(length)}, data...)
+	logging.DebugTX("S7Plus", packet)
+	_, err := t.conn.Write(packet)
+	return err
+}
+
+// recvTPKT receives a TPKT-framed packet.
+func (t *transport) recvTPKT() ([]byte, error) {
+	header := make([]byte, tpktHeaderSize)
+	if _, err := io.ReadFull(t.conn, header); err != nil {
+		return nil, fmt.Errorf("failed to read TPKT header: %w", err)
+	}
+	if header[0] != tpktVersion {
+		return nil, fmt.Errorf("invalid TPKT version: %d", header[0])
+	}
+	length := int(binary.BigEndian.Uint16(header[2:4]))
+	if length < tpktHeaderSize {
+		return nil, fmt.Errorf("invalid TPKT length: %d", length)
+	}
+	payload := make([]byte, length-tpktHeaderSize)
+	if _, err := io.ReadFull(t.conn, payload); err != nil {
+		return nil, fmt.Errorf("failed to read TPKT payload: %w", err)
+	}
+	logging.DebugRX("S7Plus", append(header, payload...))
+	return payload, nil
+}
+
+// cotpConnect performs the COTP connection request/confirm exchange.
+func (t *transport) cotpConnect() error {
+	cr := []byte{
+		0x00,       // Length (filled later)
+		cotpCR,     // PDU type
+		0x00, 0x00, // Destination reference
+		0x00, 0x01, // Source reference
+		0x00, // Class 0
+	}
+	cr = append(cr, cotpParamTPDUSize, 0x01, cotpTPDUSize1024)
+	cr = append(cr, cotpParamSrcTSAP, 0x02, byte(localTSAP>>8), byte(localTSAP&0xFF))
+	cr = append(cr, cotpParamDstTSAP, byte(len(remoteTSAP)))
+	cr = append(cr, remoteTSAP...)
+	cr[0] = byte(len(cr) - 1)
+
+	if err := t.sendTPKT(cr); err != nil {
+		return fmt.Errorf("failed to send COTP CR: %w", err)
+	}
+	cc, err := t.recvTPKT()
+	if err != nil {
+		return fmt.Errorf("failed to receive COTP CC: %w", err)
+	}
+	if len(cc) < 2 || cc[1] != cotpCC {
+		return fmt.Errorf("connection refused by PLC (no COTP CC)")
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package s7plus
+
+import (
+	"fmt"
+	"time"
+)
+
+// Softdatatypes: the IEC type of a variable in the PLC's type information.
+const (
+	SoftBool    byte = 1
+	SoftByte    byte = 2
+	SoftChar    byte = 3
+	SoftWord    byte = 4
+	SoftInt     byte = 5
+	SoftDWord   byte = 6
+	SoftDInt    byte = 7
+	SoftReal    byte = 8
+	SoftDate    byte = 9
+	SoftTOD     byte = 10
+	SoftTime    byte = 11
+	SoftS5Time  byte = 12
+	SoftDT      byte = 14
+	SoftString  byte = 19
+	SoftLReal   byte = 48
+	SoftULInt   byte = 49
+	SoftLInt    byte = 50
+	SoftLWord   byte = 51
+	SoftUSInt   byte = 52
+	SoftUInt    byte = 53
+	SoftUDInt   byte = 54
+	SoftSInt    byte = 55
+	SoftWChar   byte = 61
+	SoftWString byte = 62
+	SoftLTime   byte = 64
+	SoftLTOD    byte = 65
+	SoftLDT     byte = 66
+	SoftDTL     byte = 67
+)
+
+var softTypeNames = map[byte]string{
+	SoftBool:    "BOOL",
+	SoftByte:    "BYTE",
+	SoftChar:    "CHAR",
+	SoftWord:    "WORD",
+	SoftInt:     "INT",
+	SoftDWord:   "DWORD",
+	SoftDInt:    "DINT",
+	SoftReal:    "REAL",
+	SoftDate:    "DATE",
+	SoftTOD:     "TIME_OF_DAY",
+	SoftTime:    "TIME",
+	SoftS5Time:  "S5TIME",
+	SoftDT:      "DATE_AND_TIME",
+	SoftString:  "STRING",
+	SoftLReal:   "LREAL",
+	SoftULInt:   "ULINT",
+	SoftLInt:    "LINT",
+	SoftLWord:   "LWORD",
+	SoftUSInt:   "USINT",
+	SoftUInt:    "UINT",
+	SoftUDInt:   "UDINT",
+	SoftSInt:    "SINT",
+	SoftWChar:   "WCHAR",
+	SoftWString: "WSTRING",
+	SoftLTime:   "LTIME",
+	SoftLTOD:    "LTIME_OF_DAY",
+	SoftLDT:     "LDT",
+	SoftDTL:     "DTL",
+}
+
+// TypeName returns the IEC name of a softdatatype, or "TYPE(n)" if unknown.
+func TypeName(softdatatype byte) string {
+	if name, ok := softTypeNames[softdatatype]; ok {
+		return name
+	}
+	return fmt.Sprintf("TYPE(%d)", softdatatype)
+}
+
+// dateEpoch is day 0 of the DATE type.
+var dateEpoch = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// goValue converts a decoded value to the natural Go type of the variable's
+// softdatatype: durations for TIME types, time.Time for DATE, strings for
+// character types and typed slices for arrays. Values of other types are
+// returned as decoded.
+func goValue(v interface{}, softdatatype byte) interface{} {
+	if arr, ok := v.([]interface{}); ok {
+		switch softdatatype {
+		case SoftString:
+			// Array of bytes: max length, actual length, characters
+			b := make([]byte, len(arr))
+			for i, e := range arr {
+				b[i] = byte(toInt64(e))
+			}
+			if len(b) >= 2 && int(b[1]) <= len(b)-2 {
+				return string(b[2 : 2+b[1]])
+			}
+			return string(b)
+		case SoftWString:
+			u := make([]byte, 0, 2*len(arr))
+			for _, e := range arr {
+				c := toInt64(e)
+				u = append(u, byte(c>>8), byte(c))
+			}
+			if len(u) >= 4 {
+				n := int(u[2])<<8 | int(u[3])
+				if 2*n <= len(u)-4 {
+					return utf16String(u[4 : 4+2*n])
+				}
+			}
+			return utf16String(u)
+		}
+		out := make([]interface{}, len(arr))
+		for i, e := range arr {
+			out[i] = goValue(e, softdatatype)
+		}
+		return typedSlice(out)
+	}
+
+	switch softdatatype {
+	case SoftString:
+		if b, ok := v.([]byte); ok && len(b) >= 2 && int(b[1]) <= len(b)-2 {
+			return string(b[2 : 2+b[1]])
+		}
+	case SoftChar:
+		if c, ok := v.(uint8); ok {
+			return string(rune(c))
+		}
+	case SoftWChar:
+		if c, ok := v.(uint16); ok {
+			return string(rune(c))
+		}
+	case SoftTime:
+		return time.Duration(toInt64(v)) * time.Millisecond
+	case SoftTOD:
+		return time.Duration(toInt64(v)) * time.Millisecond
+	case SoftLTime, SoftLTOD:
+		return time.Duration(toInt64(v))
+	case SoftDate:
+		return dateEpoch.AddDate(0, 0, int(toInt64(v)))
+	case SoftLDT:
+		return time.Unix(0, toInt64(v)).UTC()
+	}
+	return v
+}
+
+// toInt64 converts any decoded integer to int64.
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case uint8:
+		return int64(n)
+	case int8:
+		return int64(n)
+	case uint16:
+		return int64(n)
+	case int16:
+		return int64(n)
+	case uint32:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case uint64:
+		return int64(n)
+	case int64:
+		return n
+	}
+	return 0
+}
+
+// typedSlice converts a homogeneous array to a slice of its element type.
+func typedSlice(arr []interface{}) interface{} {
+	if len(arr) == 0 {
+		return arr
+	}
+	switch arr[0].(type) {
+	case bool:
+		return convertSlice[bool](arr)
+	case uint8:
+		return convertSlice[uint8](arr)
+	case int8:
+		return convertSlice[int8](arr)
+	case uint16:
+		return convertSlice[uint16](arr)
+	case int16:
+		return convertSlice[int16](arr)
+	case uint32:
+		return convertSlice[uint32](arr)
+	case int32:
+		return convertSlice[int32](arr)
+	case uint64:
+		return convertSlice[uint64](arr)
+	case int64:
+		return convertSlice[int64](arr)
+	case float32:
+		return convertSlice[float32](arr)
+	case float64:
+		return convertSlice[float64](arr)
+	case string:
+		return convertSlice[string](arr)
+	case time.Duration:
+		return convertSlice[time.Duration](arr)
+	case time.Time:
+		return convertSlice[time.Time](arr)
+	}
+	return arr
+}
+
+func convertSlice[T any](arr []interface{}) interface{} {
+	out := make([]T, len(arr))
+	for i, e := range arr {
+		v, ok := e.(T)
+		if !ok {
+			return arr
+		}
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package s7plus
+
+import "fmt"
+
+// S7CommPlus encodes most integers as big-endian variable-length quantities:
+// 7 bits per byte with the high bit set on every byte but the last. 64-bit
+// values use at most 9 bytes, the ninth carrying a full 8 bits. Signed values
+// are two's complement, with bit 6 of the first byte as the sign.
+
+// appendUvarint32 appends v as an unsigned VLQ.
+func appendUvarint32(b []byte, v uint32) []byte {
+	return appendUvarint64(b, uint64(v))
+}
+
+// appendUvarint64 appends v as an unsigned VLQ.
+func appendUvarint64(b []byte, v uint64) []byte {
+	if v >= 1<<56 {
+		// Eight 7-bit groups followed by a full byte
+		for i := 7; i >= 0; i-- {
+			b = append(b, byte(v>>(8+7*uint(i)))&0x7F|0x80)
+		}
+		return append(b, byte(v))
+	}
+	n := 1
+	for n < 8 && v>>(7*uint(n)) != 0 {
+		n++
+	}
+	for i := n - 1; i > 0; i-- {
+		b = append(b, byte(v>>(7*uint(i)))&0x7F|0x80)
+	}
+	return append(b, byte(v)&0x7F)
+}
+
+// appendVarint64 appends v as a signed VLQ.
+func appendVarint64(b []byte, v int64) []byte {
+	if v >= 1<<55 || v < -(1<<55) {
+		for i := 7; i >= 0; i-- {
+			b = append(b, byte(v>>(8+7*uint(i)))&0x7F|0x80)
+		}
+		return append(b, byte(v))
+	}
+	n := 1
+	for n < 8 && (v >= 1<<(7*uint(n)-1) || v < -(1<<(7*uint(n)-1))) {
+		n++
+	}
+	for i := n - 1; i > 0; i-- {
+		b = append(b, byte(v>>(7*uint(i)))&0x7F|0x80)
+	}
+	return append(b, byte(v)&0x7F)
+}
+
+// reader decodes S7CommPlus data from a byte slice. The first error sticks;
+// later reads return zero values.
+type reader struct {
+	b   []byte
+	pos int
+	err error
+}
+
+func (r *reader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf(format, args...)
+	}
+}
+
+// remaining returns the number of unread bytes.
+func (r *reader) remaining() int {
+	return len(r.b) - r.pos
+}
+
+// bytes returns the next n bytes.
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.remaining() < n {
+		r.fail("s7plus: need %d bytes at offset %d, have %d", n, r.pos, r.remaining())
+		return nil
+	}
+	v := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return v
+}
+
+// peek returns the next byte without consuming it, or 0 at the end.
+func (r *reader) peek() byte {
+	if r.err != nil || r.remaining() < 1 {
+		return 0
+	}
+	return r.b[r.pos]
+}
+
+func (r *reader) uint8() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return uint16(b[0])<<8 | uint16(b[1])
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	}
+	return 0
+}
+
+func (r *reader) uint64() uint64 {
+	return uint64(r.uint32())<<32 | uint64(r.uint32())
+}
+
+// uint16LE and uint32LE read the little-endian fields of type information.
+func (r *reader) uint16LE() uint16 {
+	if b := r.bytes(2); b != nil {
+		return uint16(b[1])<<8 | uint16(b[0])
+	}
+	return 0
+}
+
+func (r *reader) uint32LE() uint32 {
+	if b := r.bytes(4); b != nil {
+		return uint32(b[3])<<24 | uint32(b[2])<<16 | uint32(b[1])<<8 | uint32(b[0])
+	}
+	return 0
+}
+
+// uvarint32 reads an unsigned VLQ of at most 5 bytes.
+func (r *reader) uvarint32() uint32 {
+	var v uint32
+	for i := 0; i < 5; i++ {
+		b := r.uint8()
+		v = v<<7 | uint32(b&0x7F)
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+	r.fail("s7plus: VLQ too long at offset %d", r.pos)
+	return 0
+}
+
+// uvarint64 reads an unsigned VLQ of at most 9 bytes.
+func (r *reader) uvarint64() uint64 {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		b := r.uint8()
+		if i == 8 {
+			return v<<8 | uint64(b)
+		}
+		v = v<<7 | uint64(b&0x7F)
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+	return v
+}
+
+// varint64 reads a signed VLQ of at most 9 bytes.
+func (r *reader) varint64() int64 {
+	var v int64
+	for i := 0; i < 9; i++ {
+		b := r.uint8()
+		if i == 8 {
+			return v<<8 | int64(b)
+		}
+		if i == 0 && b&0x40 != 0 {
+			v = -1 // Sign-extend
+		}
+		v = v<<7 | int64(b&0x7F)
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+	return v
+}
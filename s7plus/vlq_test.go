@@ -0,0 +1,79 @@
+package s7plus
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestUvarintRoundTrip(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{0x7F, []byte{0x7F}},
+		{0x80, []byte{0x81, 0x00}},
+		{2550, []byte{0x93, 0x76}},
+		{0x8A0E0001, []byte{0x88, 0xD0, 0xB8, 0x80, 0x01}},
+	}
+	for _, tt := range tests {
+		got := appendUvarint64(nil, tt.v)
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("appendUvarint64(%#x) = % X, want % X", tt.v, got, tt.want)
+		}
+	}
+
+	for _, v := range []uint64{0, 1, 127, 128, 16383, 16384, 1<<32 - 1, 1<<56 - 1, 1 << 56, math.MaxUint64} {
+		r := &reader{b: appendUvarint64(nil, v)}
+		if got := r.uvarint64(); got != v || r.err != nil || r.remaining() != 0 {
+			t.Errorf("uvarint64 round trip %#x: got %#x, err %v, %d left", v, got, r.err, r.remaining())
+		}
+	}
+	if n := len(appendUvarint64(nil, math.MaxUint64)); n != 9 {
+		t.Errorf("MaxUint64 encodes in %d bytes, want 9", n)
+	}
+}
+
+func TestVarintRoundTrip(t *testing.T) {
+	if got := appendVarint64(nil, -1); !bytes.Equal(got, []byte{0x7F}) {
+		t.Errorf("appendVarint64(-1) = % X, want 7F", got)
+	}
+	if got := appendVarint64(nil, 64); !bytes.Equal(got, []byte{0x80, 0x40}) {
+		t.Errorf("appendVarint64(64) = % X, want 80 40", got)
+	}
+
+	for _, v := range []int64{0, 1, -1, 63, 64, -64, -65, 1000, -1000, 1<<55 - 1, -(1 << 55), 1 << 55, math.MaxInt64, math.MinInt64} {
+		r := &reader{b: appendVarint64(nil, v)}
+		if got := r.varint64(); got != v || r.err != nil || r.remaining() != 0 {
+			t.Errorf("varint64 round trip %d: got %d, err %v, %d left", v, got, r.err, r.remaining())
+		}
+	}
+}
+
+func TestReaderShortData(t *testing.T) {
+	r := &reader{b: []byte{0x81}}
+	r.uvarint32()
+	if r.err == nil {
+		t.Fatal("expected error for truncated VLQ")
+	}
+	if r.uint32() != 0 {
+		t.Error("reads after an error should return zero")
+	}
+}
+
+func TestAccessIDString(t *testing.T) {
+	id := AccessID{Area: 0x8A0E0005, LIDs: []uint32{10, 3}}
+	if s := id.String(); s != "8A0E0005.A.3" {
+		t.Errorf("String() = %q", s)
+	}
+	parsed, err := ParseAccessID("8a0e0005.a.3")
+	if err != nil || parsed.Area != id.Area || len(parsed.LIDs) != 2 || parsed.LIDs[0] != 10 || parsed.LIDs[1] != 3 {
+		t.Errorf("ParseAccessID = %+v, %v", parsed, err)
+	}
+	for _, bad := range []string{"DB1.DBW0", "8A0E0005", "12345678.1", "8A0E0005.x"} {
+		if _, err := ParseAccessID(bad); err == nil {
+			t.Errorf("ParseAccessID(%q): expected error", bad)
+		}
+	}
+}